@@ -0,0 +1,44 @@
+package jwt
+
+import "errors"
+
+// AccessTokenTypHeader is the "typ" header field value
+// of the JWT access tokens (RFC 9068, Section 2.1).
+const AccessTokenTypHeader = "at+jwt"
+
+// accessTokenRequiredClaims are the mandatory claims of
+// the JWT access tokens (RFC 9068, Section 2.2).
+var accessTokenRequiredClaims = []string{"iss", "exp", "aud", "sub", "iat", "jti", "client_id"}
+
+var errAccessTokenConfig = errors.New("jwt: access token: issuer and audience are required")
+
+// VerifyAccessToken verifies a JWT access token of the RFC 9068 profile.
+// Besides the signature and the standard claims' validation (see `Verify`), it checks that:
+//   - the "typ" header field is "at+jwt", otherwise it returns an `ErrInvalidTypHeader` error
+//   - the "iss", "exp", "aud", "sub", "iat", "jti" and "client_id" claims are present,
+//     otherwise it returns a `*MissingClaimError` of the first missing one
+//   - the "iss" claim matches the "issuer", otherwise it returns an `ErrInvalidIssuer` error
+//   - the "aud" claim contains the "audience", otherwise it returns an `ErrInvalidAudience` error.
+//
+// Both "issuer" and "audience" are required.
+// Any extra "validators" run after the profile's checks.
+//
+// Example Code:
+//
+//	verifiedToken, err := jwt.VerifyAccessToken(jwt.RS256, publicKey, token, "https://auth.example.com", "my-api")
+//	var missing *jwt.MissingClaimError
+//	if errors.As(err, &missing) { [...] }
+func VerifyAccessToken(alg Alg, key PublicKey, token []byte, issuer, audience string, validators ...TokenValidator) (*VerifiedToken, error) {
+	if issuer == "" || audience == "" {
+		return nil, errAccessTokenConfig
+	}
+
+	profile := []TokenValidator{
+		WithExpectedTypHeader(AccessTokenTypHeader),
+		WithRequiredClaims(accessTokenRequiredClaims...),
+		WithExpectedIssuer(issuer),
+		WithExpectedAudience(audience),
+	}
+
+	return verifyToken(alg, key, nil, token, nil, append(profile, validators...)...)
+}
@@ -0,0 +1,96 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestVerifyAccessToken(t *testing.T) {
+	const (
+		issuer   = "https://auth.example.com"
+		audience = "my-api"
+	)
+
+	validClaims := func() Map {
+		now := Clock()
+		return Map{
+			"iss":       issuer,
+			"exp":       now.Add(time.Minute).Unix(),
+			"aud":       audience,
+			"sub":       "user-1",
+			"iat":       now.Unix(),
+			"jti":       "id-1",
+			"client_id": "client-1",
+		}
+	}
+
+	sign := func(claims Map, opts ...SignOption) []byte {
+		t.Helper()
+		token, err := Sign(testAlg, testSecret, claims, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	verifiedToken, err := VerifyAccessToken(testAlg, testSecret, sign(validClaims(), WithTypHeader("at+jwt")), issuer, audience)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "user-1", verifiedToken.StandardClaims.Subject; expected != got {
+		t.Fatalf("expected subject: %q but got: %q", expected, got)
+	}
+
+	// Missing mandatory claims.
+	for _, name := range accessTokenRequiredClaims {
+		claims := validClaims()
+		delete(claims, name)
+
+		_, err = VerifyAccessToken(testAlg, testSecret, sign(claims, WithTypHeader("at+jwt")), issuer, audience)
+		var missing *MissingClaimError
+		if !errors.As(err, &missing) || !errors.Is(err, ErrMissingRequiredClaim) {
+			t.Fatalf("[%s] expected a *MissingClaimError but got: %v", name, err)
+		}
+
+		if missing.Claim != name {
+			t.Fatalf("expected missing claim: %q but got: %q", name, missing.Claim)
+		}
+	}
+
+	var tests = []struct {
+		name        string
+		token       []byte
+		expectedErr error
+	}{
+		{"typ header", sign(validClaims()), ErrInvalidTypHeader},
+		{"issuer", sign(mergeTestMap(validClaims(), Map{"iss": "https://other.example.com"}), WithTypHeader("at+jwt")), ErrInvalidIssuer},
+		{"audience", sign(mergeTestMap(validClaims(), Map{"aud": "other-api"}), WithTypHeader("at+jwt")), ErrInvalidAudience},
+		{"expired", sign(mergeTestMap(validClaims(), Map{"exp": Clock().Add(-time.Minute).Unix()}), WithTypHeader("at+jwt")), ErrExpired},
+	}
+
+	for _, tt := range tests {
+		if _, err = VerifyAccessToken(testAlg, testSecret, tt.token, issuer, audience); !errors.Is(err, tt.expectedErr) {
+			t.Fatalf("[%s] expected error: %v but got: %v", tt.name, tt.expectedErr, err)
+		}
+	}
+
+	// Extra validators.
+	token := sign(validClaims(), WithTypHeader("at+jwt"))
+	if _, err = VerifyAccessToken(testAlg, testSecret, token, issuer, audience, WithExpectedSubject("user-2")); !errors.Is(err, ErrInvalidSubject) {
+		t.Fatalf("expected error: ErrInvalidSubject but got: %v", err)
+	}
+
+	if _, err = VerifyAccessToken(testAlg, testSecret, token, "", audience); err != errAccessTokenConfig {
+		t.Fatalf("expected error: errAccessTokenConfig but got: %v", err)
+	}
+}
+
+func mergeTestMap(dst, src Map) Map {
+	for k, v := range src {
+		dst[k] = v
+	}
+
+	return dst
+}
@@ -6,13 +6,22 @@ import (
 	_ "crypto/sha256" // ignore:lint
 	_ "crypto/sha512"
 	"errors"
+	"fmt"
+	"strings"
+	"sync"
 )
 
 var (
 	// ErrTokenSignature indicates that the verification failed.
 	ErrTokenSignature = errors.New("jwt: invalid token signature")
+	// ErrInvalidSignature indicates that the token is well-formed
+	// but its signature does not match, e.g. it was signed by a different key
+	// or it was tampered. It is an alias of ErrTokenSignature.
+	ErrInvalidSignature = ErrTokenSignature
 	// ErrInvalidKey indicates that an algorithm required secret key is not a valid type.
 	ErrInvalidKey = errors.New("jwt: invalid key")
+	// ErrAlgRegistered indicates that an algorithm with the same name was already registered.
+	ErrAlgRegistered = errors.New("jwt: algorithm already registered")
 )
 
 // Alg represents a signing and verifying algorithm.
@@ -22,6 +31,7 @@ type Alg interface {
 	// Sign should accept the private key given on jwt.Sign and
 	// the base64-encoded header and payload data.
 	// Should return the signature.
+	// The headerAndPayload is reused after Sign returns, it must not be retained.
 	Sign(key PrivateKey, headerAndPayload []byte) ([]byte, error)
 	// Verify should verify the JWT "signature" (base64-decoded) against
 	// the header and payload (base64-encoded).
@@ -69,6 +79,11 @@ var (
 	//    "name": "Pretty Name",
 	//    "lastpage": "/views/settings"
 	//  }
+	//
+	// Accepting unsigned tokens is a well-known JWT vulnerability,
+	// therefore a token with a "NONE" header is only verified when
+	// the NONE algorithm is explicitly passed to the `Verify` function
+	// and its signature part is empty. Its header is never trusted to select it.
 	NONE Alg = &algNONE{}
 	// HMAC-SHA signing algorithms.
 	// Keys should be type of []byte.
@@ -141,9 +156,18 @@ var (
 	// The generated files are in PEM format as well,
 	// so simply pasting them in your source will suffice.
 	// It generates a smaller token (almost 3 times less).
-	ES256 Alg = &algECDSA{"ES256", crypto.SHA256, 32, 256}
-	ES384 Alg = &algECDSA{"ES384", crypto.SHA384, 48, 384}
-	ES512 Alg = &algECDSA{"ES512", crypto.SHA512, 66, 521}
+	ES256 Alg = &algECDSA{"ES256", crypto.SHA256, 32, 256, "P-256"}
+	ES384 Alg = &algECDSA{"ES384", crypto.SHA384, 48, 384, "P-384"}
+	ES512 Alg = &algECDSA{"ES512", crypto.SHA512, 66, 521, "P-521"}
+	// ES256K is the ECDSA algorithm using the secp256k1 curve and SHA-256 (RFC 8812).
+	// Sign   key: *ecdsa.PrivateKey
+	// Verify key: *ecdsa.PublicKey (or *ecdsa.PrivateKey with its PublicKey filled)
+	//
+	// The Go standard library does not ship the secp256k1 curve,
+	// the keys should be created through a third-party elliptic.Curve implementation
+	// which reports "secp256k1" as its Params().Name,
+	// e.g. the S256() of the github.com/decred/dcrd/dcrec/secp256k1 package.
+	ES256K Alg = &algECDSA{"ES256K", crypto.SHA256, 32, 256, "secp256k1"}
 	// Ed25519 Edwards-curve Digital Signature Algorithm.
 	// The algorithm's name is: "EdDSA".
 	// Sign   key: ed25519.PrivateKey
@@ -157,6 +181,9 @@ var (
 
 	allAlgs = []Alg{
 		NONE,
+		HS256,
+		HS384,
+		HS512,
 		RS256,
 		RS384,
 		RS512,
@@ -166,6 +193,69 @@ var (
 		ES256,
 		ES384,
 		ES512,
+		ES256K,
 		EdDSA,
 	}
 )
+
+var (
+	algsMu sync.RWMutex
+	algs   = make(map[string]Alg, len(allAlgs))
+)
+
+func init() {
+	for _, alg := range allAlgs {
+		algs[alg.Name()] = alg
+	}
+}
+
+// RegisterAlg registers a custom algorithm, e.g. an HSM-backed signer,
+// so it can be found by its "alg" header name through the `GetAlg` function.
+// The builtin algorithms are already registered.
+//
+// It returns an `ErrAlgRegistered` error if the "name" is already registered.
+// It is safe for concurrent use.
+func RegisterAlg(name string, alg Alg) error {
+	if name == "" || alg == nil {
+		return fmt.Errorf("jwt: register algorithm: empty name or nil algorithm")
+	}
+
+	algsMu.Lock()
+	defer algsMu.Unlock()
+
+	if _, exists := algs[name]; exists {
+		return fmt.Errorf("%w: %q", ErrAlgRegistered, name)
+	}
+
+	algs[name] = alg
+	return nil
+}
+
+// GetAlg returns a registered algorithm based on its name (the "alg" header field).
+// It reports false if no algorithm was registered under that name.
+// It is safe for concurrent use.
+func GetAlg(name string) (Alg, bool) {
+	algsMu.RLock()
+	alg, ok := algs[name]
+	algsMu.RUnlock()
+
+	return alg, ok
+}
+
+// findAlg is like GetAlg but it falls back to a case-insensitive search.
+func findAlg(name string) (Alg, bool) {
+	if alg, ok := GetAlg(name); ok {
+		return alg, true
+	}
+
+	algsMu.RLock()
+	defer algsMu.RUnlock()
+
+	for k, alg := range algs {
+		if strings.EqualFold(k, name) {
+			return alg, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,68 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+)
+
+type testCustomAlg struct {
+	Alg
+	name string
+}
+
+func (a *testCustomAlg) Name() string {
+	return a.name
+}
+
+func TestRegisterAlg(t *testing.T) {
+	for _, alg := range allAlgs {
+		got, ok := GetAlg(alg.Name())
+		if !ok {
+			t.Fatalf("expected builtin algorithm %q to be registered", alg.Name())
+		}
+
+		if got != alg {
+			t.Fatalf("expected registered algorithm %q to match the builtin one", alg.Name())
+		}
+	}
+
+	alg := &testCustomAlg{Alg: HS256, name: "TEST256"}
+	if err := RegisterAlg(alg.Name(), alg); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		algsMu.Lock()
+		delete(algs, alg.Name())
+		algsMu.Unlock()
+	})
+
+	if got, ok := GetAlg(alg.Name()); !ok || got != alg {
+		t.Fatalf("expected custom algorithm to be registered")
+	}
+
+	if err := RegisterAlg(alg.Name(), alg); !errors.Is(err, ErrAlgRegistered) {
+		t.Fatalf("expected error: ErrAlgRegistered but got: %v", err)
+	}
+
+	if err := RegisterAlg(HS256.Name(), alg); !errors.Is(err, ErrAlgRegistered) {
+		t.Fatalf("expected builtin algorithms to not be overridden but got: %v", err)
+	}
+
+	if err := RegisterAlg("", alg); err == nil {
+		t.Fatalf("expected error on empty algorithm name")
+	}
+
+	if _, ok := GetAlg("unknown"); ok {
+		t.Fatalf("expected unknown algorithm to not be found")
+	}
+
+	// Test that keys configuration can find custom, registered, algorithms.
+	keys, err := KeysConfiguration{{ID: "api", Alg: "test256", Private: string(testSecret)}}.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := keys["api"].Alg; got != alg {
+		t.Fatalf("expected the custom algorithm but got: %s", got.Name())
+	}
+}
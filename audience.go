@@ -0,0 +1,63 @@
+package jwt
+
+import "encoding/json"
+
+// WithScalarAudience is a SignOption which emits a single-element "aud" claim
+// as a string, e.g. "aud":"my-api" instead of "aud":["my-api"].
+// Both forms are valid (RFC 7519, Section 4.1.3) but some strict verifiers
+// expect a string. Audiences of more than one element are always emitted as arrays.
+//
+// The `Audience` type decodes both forms to a slice.
+//
+// Usage:
+//
+//	token, err := jwt.Sign(jwt.HS256, sharedKey, jwt.Claims{Audience: jwt.Audience{"my-api"}}, jwt.WithScalarAudience())
+func WithScalarAudience() SignConfigOption {
+	return func(c *signConfig) {
+		c.scalarAudience = true
+	}
+}
+
+// scalarAudience replaces the top-level "aud" fields of a single-element array
+// with their string value. The rest of the payload is kept as it is.
+// A payload which is not a JSON object is returned as it is.
+func scalarAudience(payload []byte) []byte {
+	type span struct {
+		start, end int
+		value      []byte
+	}
+
+	var spans []span
+	walkObject(payload, func(key []byte, start, end int) bool {
+		if string(key) != "aud" || payload[start] != '[' {
+			return true
+		}
+
+		var aud []string
+		if err := json.Unmarshal(payload[start:end], &aud); err != nil || len(aud) != 1 {
+			return true
+		}
+
+		value, err := json.Marshal(aud[0])
+		if err != nil {
+			return true
+		}
+
+		spans = append(spans, span{start, end, value})
+		return true
+	})
+
+	if len(spans) == 0 {
+		return payload
+	}
+
+	b := make([]byte, 0, len(payload))
+	last := 0
+	for _, s := range spans {
+		b = append(b, payload[last:s.start]...)
+		b = append(b, s.value...)
+		last = s.end
+	}
+
+	return append(b, payload[last:]...)
+}
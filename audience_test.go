@@ -0,0 +1,80 @@
+package jwt
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestScalarAudience(t *testing.T) {
+	var tests = []struct {
+		payload  string
+		expected string
+	}{
+		{`{"aud":["api"]}`, `{"aud":"api"}`},
+		{`{"sub":"user","aud":["api"],"exp":1}`, `{"sub":"user","aud":"api","exp":1}`},
+		{`{ "aud" : [ "a\"pi" ] , "exp": 1 }`, `{ "aud" : "a\"pi" , "exp": 1 }`},
+		{`{"aud":["api","admin"]}`, `{"aud":["api","admin"]}`},
+		{`{"aud":"api"}`, `{"aud":"api"}`},
+		{`{"aud":[]}`, `{"aud":[]}`},
+		{`{"data":{"aud":["api"]}}`, `{"data":{"aud":["api"]}}`},
+		{`{"aud":[1]}`, `{"aud":[1]}`},
+		{`"aud"`, `"aud"`},
+	}
+
+	for i, tt := range tests {
+		if got := string(scalarAudience([]byte(tt.payload))); tt.expected != got {
+			t.Fatalf("[%d] expected: %s but got: %s", i, tt.expected, got)
+		}
+	}
+}
+
+func TestWithScalarAudience(t *testing.T) {
+	var tests = []struct {
+		audience Audience
+		expected string
+	}{
+		{Audience{"api"}, `"aud":"api"`},
+		{Audience{"api", "admin"}, `"aud":["api","admin"]`},
+	}
+
+	for i, tt := range tests {
+		token, err := Sign(testAlg, testSecret, Map{"username": "kataras"}, Claims{Audience: tt.audience}, WithScalarAudience())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		payload, err := Base64Decode(bytes.Split(token, sep)[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Contains(payload, []byte(tt.expected)) {
+			t.Fatalf("[%d] expected payload to contain: %s but got: %s", i, tt.expected, payload)
+		}
+
+		verifiedToken, err := Verify(testAlg, testSecret, token, WithExpectedAudience("api"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(tt.audience, verifiedToken.StandardClaims.Audience) {
+			t.Fatalf("[%d] expected audience: %#v but got: %#v", i, tt.audience, verifiedToken.StandardClaims.Audience)
+		}
+	}
+
+	// Without the option the array form is kept.
+	token, err := Sign(testAlg, testSecret, Claims{Audience: Audience{"api"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, err := Base64Decode(bytes.Split(token, sep)[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := `"aud":["api"]`; !bytes.Contains(payload, []byte(expected)) {
+		t.Fatalf("expected payload to contain: %s but got: %s", expected, payload)
+	}
+}
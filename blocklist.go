@@ -29,9 +29,64 @@ type Blocklist struct {
 	// ^ we could make it a map[*VerifiedToken]struct{} too
 	// but let's have a more general usage here.
 	mu sync.RWMutex
+
+	cancel context.CancelFunc // stops the GC goroutine, see `Close`.
+}
+
+var (
+	_ TokenValidator = (*Blocklist)(nil)
+	_ BlocklistStore = (*Blocklist)(nil)
+)
+
+// BlocklistStore is the interface of a storage of revoked token IDs ("jti" claim),
+// see the `WithBlocklist` verify option.
+//
+// The `Blocklist` structure is an in-memory implementation of it,
+// see `NewMemoryBlocklist`. Any database (e.g. redis) can be used instead,
+// as long as it implements the two methods below.
+type BlocklistStore interface {
+	// Has reports whether the given token ID is blocked.
+	Has(jti string) (bool, error)
+	// Set blocks the given token ID until the "exp" time,
+	// after that time the entry can be safely removed.
+	// A zero "exp" (a token without an "exp" claim) blocks the token ID forever.
+	Set(jti string, exp time.Time) error
+}
+
+// WithBlocklist is a VerifyOption which rejects tokens
+// whose "jti" claim is blocked by the given "store", with an `ErrBlocked` error.
+// Tokens without a "jti" claim are not checked.
+// This enables token revocation and replay prevention.
+//
+// Usage:
+//
+//	blocklist := jwt.NewMemoryBlocklist()
+//	defer blocklist.Close()
+//	verifiedToken, err := jwt.Verify(jwt.HS256, sharedKey, token, jwt.WithBlocklist(blocklist))
+//	[...]
+//	blocklist.Set(verifiedToken.StandardClaims.ID, verifiedToken.StandardClaims.ExpiresAt())
+func WithBlocklist(store BlocklistStore) VerifyOption {
+	return func(c *verifyConfig) {
+		c.blocklist = store
+	}
 }
 
-var _ TokenValidator = (*Blocklist)(nil)
+func validateBlocklist(store BlocklistStore, jti string) error {
+	if jti == "" {
+		return nil
+	}
+
+	blocked, err := store.Has(jti)
+	if err != nil {
+		return err
+	}
+
+	if blocked {
+		return ErrBlocked
+	}
+
+	return nil
+}
 
 // NewBlocklist returns a new up and running in-memory Token Blocklist.
 // It accepts the clear every "x" duration. Indeed, this duration
@@ -42,22 +97,87 @@ func NewBlocklist(gcEvery time.Duration) *Blocklist {
 	return NewBlocklistContext(context.Background(), gcEvery)
 }
 
+// BlocklistOption sets an option of a memory Blocklist.
+// See `NewMemoryBlocklist`.
+type BlocklistOption func(*memoryBlocklistConfig)
+
+type memoryBlocklistConfig struct {
+	gcEvery time.Duration
+	clock   func() time.Time
+}
+
+// WithGCInterval sets the interval which a background goroutine
+// removes the expired entries of the blocklist.
+// Defaults to `DefaultBlocklistGCInterval`, zero or negative disables it.
+func WithGCInterval(every time.Duration) BlocklistOption {
+	return func(c *memoryBlocklistConfig) {
+		c.gcEvery = every
+	}
+}
+
+// DefaultBlocklistGCInterval is the default interval
+// of the `NewMemoryBlocklist` background GC.
+const DefaultBlocklistGCInterval = 30 * time.Minute
+
+// WithBlocklistClock sets the current time source which the expiration
+// of the blocklist entries is checked against, instead of the `Clock` package-level variable.
+// It sets the `Blocklist.Clock` field.
+func WithBlocklistClock(clock func() time.Time) BlocklistOption {
+	return func(c *memoryBlocklistConfig) {
+		c.clock = clock
+	}
+}
+
+// NewMemoryBlocklist returns a new in-memory `BlocklistStore`.
+// Entries past their expiration are purged on lookup
+// and by a background goroutine every `DefaultBlocklistGCInterval`
+// (see `WithGCInterval`). Call the `Blocklist.Close` method to stop it.
+//
+// It is safe for concurrent use.
+func NewMemoryBlocklist(opts ...BlocklistOption) *Blocklist {
+	c := memoryBlocklistConfig{gcEvery: DefaultBlocklistGCInterval}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	if c.clock == nil {
+		c.clock = Clock
+	}
+
+	return newBlocklist(context.Background(), c.gcEvery, c.clock)
+}
+
 // NewBlocklistContext same as `NewBlocklist`
 // but it also accepts a standard Go Context for GC cancelation.
 func NewBlocklistContext(ctx context.Context, gcEvery time.Duration) *Blocklist {
+	return newBlocklist(ctx, gcEvery, Clock)
+}
+
+func newBlocklist(ctx context.Context, gcEvery time.Duration, clock func() time.Time) *Blocklist {
 	b := &Blocklist{
 		entries: make(map[string]int64),
-		Clock:   Clock,
+		Clock:   clock,
 		GetKey:  defaultGetKey,
 	}
 
 	if gcEvery > 0 {
+		ctx, b.cancel = context.WithCancel(ctx)
 		go b.runGC(ctx, gcEvery)
 	}
 
 	return b
 }
 
+// Close stops the background GC goroutine, if any.
+// The blocklist can still be used afterwards.
+func (b *Blocklist) Close() error {
+	if b.cancel != nil {
+		b.cancel()
+	}
+
+	return nil
+}
+
 func defaultGetKey(token []byte, c Claims) string {
 	if c.ID != "" {
 		return c.ID
@@ -71,7 +191,7 @@ func defaultGetKey(token []byte, c Claims) string {
 func (b *Blocklist) ValidateToken(token []byte, c Claims, err error) error {
 	key := b.GetKey(token, c)
 	if err != nil {
-		if err == ErrExpired {
+		if errors.Is(err, ErrExpired) {
 			b.Del(key)
 		}
 
@@ -122,22 +242,56 @@ func (b *Blocklist) Count() (int64, error) {
 	return int64(n), nil
 }
 
+// Set blocks the given "key" (e.g. a token ID) until the "exp" time,
+// a zero "exp" blocks it forever. It completes the `BlocklistStore` interface.
+func (b *Blocklist) Set(key string, exp time.Time) error {
+	if len(key) == 0 {
+		return ErrMissing
+	}
+
+	var expiry int64
+	if !exp.IsZero() {
+		expiry = exp.Unix()
+	}
+
+	b.mu.Lock()
+	b.entries[key] = expiry
+	b.mu.Unlock()
+
+	return nil
+}
+
 // Has reports whether the given "key" is blocked by the server.
 // This method is called before the token verification,
 // so even if was expired it is removed from the blocklist.
+//
+// An entry past its expiration is removed and it's not reported as blocked.
 func (b *Blocklist) Has(key string) (bool, error) {
 	if len(key) == 0 {
 		return false, ErrMissing
 	}
 
+	now := b.Clock().Round(time.Second).Unix()
+
 	b.mu.RLock()
-	_, ok := b.entries[key]
+	expiry, ok := b.entries[key]
 	b.mu.RUnlock()
 
+	if ok && expiry > 0 && now > expiry {
+		b.mu.Lock()
+		if expiry, ok = b.entries[key]; ok && expiry > 0 && now > expiry { // check again, it may be renewed.
+			delete(b.entries, key)
+		}
+		b.mu.Unlock()
+
+		return false, nil
+	}
+
 	return ok, nil
 }
 
-// GC iterates over all entries and removes expired tokens.
+// GC iterates over all entries and removes expired tokens,
+// the entries which are blocked forever are kept.
 // This method is helpful to keep the list size small.
 // Depending on the application, the GC method can be scheduled
 // to called every half or a whole hour.
@@ -148,19 +302,20 @@ func (b *Blocklist) GC() int {
 
 	b.mu.RLock()
 	for token, expiry := range b.entries {
-		if now > expiry {
+		if expiry > 0 && now > expiry {
 			markedForDeletion = append(markedForDeletion, token)
 		}
 	}
 	b.mu.RUnlock()
 
-	n := len(markedForDeletion)
-	if n > 0 {
-		for _, token := range markedForDeletion {
-			b.mu.Lock()
+	n := 0
+	for _, token := range markedForDeletion {
+		b.mu.Lock()
+		if expiry, ok := b.entries[token]; ok && expiry > 0 && now > expiry { // check again, it may be renewed.
 			delete(b.entries, token)
-			b.mu.Unlock()
+			n++
 		}
+		b.mu.Unlock()
 	}
 
 	return n
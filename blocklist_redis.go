@@ -0,0 +1,112 @@
+package jwt
+
+import (
+	"time"
+)
+
+// DefaultRedisBlocklistKeyPrefix is the default prefix
+// of the keys stored by a RedisBlocklist.
+const DefaultRedisBlocklistKeyPrefix = "jwt:blocklist:"
+
+// RedisClient is the minimal interface of a redis client
+// which a RedisBlocklist requires. Any redis library can be used
+// through a small adapter, e.g. for the github.com/redis/go-redis:
+//
+//	type goRedisClient struct{ *redis.Client }
+//
+//	func (c goRedisClient) Set(key, value string, ttl time.Duration) error {
+//	  return c.Client.Set(context.Background(), key, value, ttl).Err()
+//	}
+//
+//	func (c goRedisClient) Exists(key string) (bool, error) {
+//	  n, err := c.Client.Exists(context.Background(), key).Result()
+//	  return n > 0, err
+//	}
+type RedisClient interface {
+	// Set stores the "value" of "key" which expires after "ttl",
+	// a zero "ttl" means no expiration (like the redis SET key value EX ttl command).
+	Set(key, value string, ttl time.Duration) error
+	// Exists reports whether the "key" exists (like the redis EXISTS key command).
+	Exists(key string) (bool, error)
+}
+
+// RedisBlocklist is a `BlocklistStore` backed by redis,
+// revoked tokens survive process restarts and are shared
+// across a cluster of servers.
+// Each token ID is stored with a TTL equal to the token's remaining lifetime,
+// so entries are removed by redis itself.
+//
+// See `NewRedisBlocklist` to create a new one.
+type RedisBlocklist struct {
+	client RedisClient
+	prefix string
+	clock  func() time.Time
+}
+
+var _ BlocklistStore = (*RedisBlocklist)(nil)
+
+// RedisBlocklistOption sets an option of a RedisBlocklist.
+// See `NewRedisBlocklist`.
+type RedisBlocklistOption func(*RedisBlocklist)
+
+// WithRedisKeyPrefix sets the prefix of each key stored by a RedisBlocklist,
+// the key is the prefix followed by the token ID ("jti" claim).
+// Useful to share a redis database between different applications.
+// Defaults to `DefaultRedisBlocklistKeyPrefix`.
+func WithRedisKeyPrefix(prefix string) RedisBlocklistOption {
+	return func(b *RedisBlocklist) {
+		b.prefix = prefix
+	}
+}
+
+// NewRedisBlocklist returns a new RedisBlocklist of the given redis "client".
+//
+// Example Code:
+//
+//	blocklist := jwt.NewRedisBlocklist(goRedisClient{rdb}, jwt.WithRedisKeyPrefix("myapp:revoked:"))
+//	verifiedToken, err := jwt.Verify(jwt.HS256, sharedKey, token, jwt.WithBlocklist(blocklist))
+func NewRedisBlocklist(client RedisClient, opts ...RedisBlocklistOption) *RedisBlocklist {
+	b := &RedisBlocklist{
+		client: client,
+		prefix: DefaultRedisBlocklistKeyPrefix,
+		clock:  Clock,
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
+}
+
+// Has reports whether the given token ID is blocked.
+// It completes the `BlocklistStore` interface.
+func (b *RedisBlocklist) Has(jti string) (bool, error) {
+	if len(jti) == 0 {
+		return false, ErrMissing
+	}
+
+	return b.client.Exists(b.prefix + jti)
+}
+
+// Set blocks the given token ID until the "exp" time.
+// An already expired token is not stored at all.
+// A zero "exp" blocks the token ID forever.
+// It completes the `BlocklistStore` interface.
+func (b *RedisBlocklist) Set(jti string, exp time.Time) error {
+	if len(jti) == 0 {
+		return ErrMissing
+	}
+
+	var ttl time.Duration
+	if !exp.IsZero() {
+		ttl = exp.Sub(b.clock())
+		if ttl <= 0 {
+			return nil
+		}
+		// Round up to seconds, the entry should not expire before the token.
+		ttl = (ttl + time.Second - 1).Truncate(time.Second)
+	}
+
+	return b.client.Set(b.prefix+jti, "1", ttl)
+}
@@ -0,0 +1,108 @@
+package jwt
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// testRedisClient is a RedisClient mock with expiring keys.
+type testRedisClient struct {
+	mu      sync.Mutex
+	entries map[string]time.Time // key = redis key | value = expiration, zero for none.
+	ttls    map[string]time.Duration
+	clock   func() time.Time
+}
+
+func newTestRedisClient(clock func() time.Time) *testRedisClient {
+	return &testRedisClient{
+		entries: make(map[string]time.Time),
+		ttls:    make(map[string]time.Duration),
+		clock:   clock,
+	}
+}
+
+func (c *testRedisClient) Set(key, value string, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var exp time.Time
+	if ttl > 0 {
+		exp = c.clock().Add(ttl)
+	}
+
+	c.entries[key] = exp
+	c.ttls[key] = ttl
+	return nil
+}
+
+func (c *testRedisClient) Exists(key string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	exp, ok := c.entries[key]
+	if ok && !exp.IsZero() && !c.clock().Before(exp) {
+		delete(c.entries, key)
+		return false, nil
+	}
+
+	return ok, nil
+}
+
+func TestRedisBlocklist(t *testing.T) {
+	now := time.Now()
+	clock := func() time.Time { return now }
+
+	client := newTestRedisClient(clock)
+	blocklist := NewRedisBlocklist(client, WithRedisKeyPrefix("myapp:"))
+	blocklist.clock = clock
+
+	token, err := Sign(testAlg, testSecret, Claims{ID: "jti:1", Expiry: now.Add(10 * time.Minute).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token, WithBlocklist(blocklist))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = blocklist.Set(verifiedToken.StandardClaims.ID, verifiedToken.StandardClaims.ExpiresAt()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := client.entries["myapp:jti:1"]; !ok {
+		t.Fatalf("expected key to be stored with the given prefix")
+	}
+
+	if expected, got := 10*time.Minute, client.ttls["myapp:jti:1"]; expected != got {
+		t.Fatalf("expected ttl: %s but got: %s", expected, got)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, WithBlocklist(blocklist)); err != ErrBlocked {
+		t.Fatalf("expected error: ErrBlocked but got: %v", err)
+	}
+
+	// The entry is removed by redis when the token expires.
+	now = now.Add(10 * time.Minute)
+	if has, _ := blocklist.Has("jti:1"); has {
+		t.Fatalf("expected entry to be expired")
+	}
+
+	// Already expired tokens are not stored.
+	if err = blocklist.Set("jti:2", now.Add(-time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := client.entries["myapp:jti:2"]; ok {
+		t.Fatalf("expected expired token to not be stored")
+	}
+
+	if err = blocklist.Set("", now); err != ErrMissing {
+		t.Fatalf("expected error: ErrMissing but got: %v", err)
+	}
+
+	if prefix := NewRedisBlocklist(client).prefix; prefix != DefaultRedisBlocklistKeyPrefix {
+		t.Fatalf("expected default prefix: %q but got: %q", DefaultRedisBlocklistKeyPrefix, prefix)
+	}
+}
@@ -2,6 +2,8 @@ package jwt
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 )
@@ -85,3 +87,155 @@ func TestBlocklist(t *testing.T) {
 		t.Fatalf("expected all entries to be removed but: %d", got)
 	}
 }
+
+func TestWithBlocklist(t *testing.T) {
+	blocklist := NewMemoryBlocklist()
+	defer blocklist.Close()
+
+	if blocklist.cancel == nil {
+		t.Fatalf("expected a background GC goroutine by default")
+	}
+
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"}, Claims{ID: "jti:1"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token, WithBlocklist(blocklist))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = blocklist.Set(verifiedToken.StandardClaims.ID, verifiedToken.StandardClaims.ExpiresAt()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, WithBlocklist(blocklist)); err != ErrBlocked {
+		t.Fatalf("expected error: ErrBlocked but got: %v", err)
+	}
+
+	// Tokens without a "jti" are not checked.
+	token, err = Sign(testAlg, testSecret, Map{"username": "kataras"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, WithBlocklist(blocklist)); err != nil {
+		t.Fatalf("expected a token without jti to pass but got: %v", err)
+	}
+
+	if err = blocklist.Set("", time.Now()); err != ErrMissing {
+		t.Fatalf("expected error: ErrMissing but got: %v", err)
+	}
+}
+
+func TestWithBlocklistAfterValidators(t *testing.T) {
+	blocklist := NewMemoryBlocklist()
+	defer blocklist.Close()
+
+	// The "iat" is slightly in the future and the Future validator skips that error,
+	// the blocklist must still be checked.
+	token, err := Sign(testAlg, testSecret, Claims{
+		IssuedAt: Clock().Add(10 * time.Second).Unix(),
+		Expiry:   Clock().Add(time.Minute).Unix(),
+		ID:       "jti:1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = blocklist.Set("jti:1", Clock().Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, Future(time.Minute), WithBlocklist(blocklist)); err != ErrBlocked {
+		t.Fatalf("expected error: ErrBlocked but got: %v", err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, Future(time.Minute), Plain, WithBlocklist(blocklist)); err != ErrBlocked {
+		t.Fatalf("expected error: ErrBlocked but got: %v", err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, WithBlocklist(blocklist), Future(time.Minute)); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestMemoryBlocklistPurgeExpired(t *testing.T) {
+	now := time.Now()
+	blocklist := NewMemoryBlocklist(WithGCInterval(0), WithBlocklistClock(func() time.Time { return now }))
+	if blocklist.cancel != nil {
+		t.Fatalf("expected no background GC goroutine with a zero interval")
+	}
+
+	blocklist.Set("jti:1", now.Add(time.Minute))
+	blocklist.Set("jti:2", now.Add(time.Hour))
+
+	if has, _ := blocklist.Has("jti:1"); !has {
+		t.Fatalf("expected jti:1 to be blocked")
+	}
+
+	now = now.Add(2 * time.Minute)
+
+	if has, _ := blocklist.Has("jti:1"); has {
+		t.Fatalf("expected jti:1 to not be blocked after its expiration")
+	}
+
+	if has, _ := blocklist.Has("jti:2"); !has {
+		t.Fatalf("expected jti:2 to be blocked")
+	}
+
+	if expected, got := int64(1), mustCount(t, blocklist); expected != got {
+		t.Fatalf("expected the expired entry to be removed: %d entries but got: %d", expected, got)
+	}
+}
+
+func TestMemoryBlocklistGC(t *testing.T) {
+	blocklist := NewMemoryBlocklist(WithGCInterval(50 * time.Millisecond))
+	defer blocklist.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			jti := fmt.Sprintf("jti:%d", i)
+			if err := blocklist.Set(jti, time.Now().Add(time.Second)); err != nil {
+				t.Error(err)
+				return
+			}
+
+			if has, err := blocklist.Has(jti); err != nil || !has {
+				t.Errorf("expected %s to be blocked: %v", jti, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	blocklist.Set("jti:long", time.Now().Add(time.Hour))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for mustCount(t, blocklist) > 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected expired entries to be removed by the GC but got: %d entries", mustCount(t, blocklist))
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	if has, _ := blocklist.Has("jti:long"); !has {
+		t.Fatalf("expected not expired entry to be kept")
+	}
+}
+
+func mustCount(t *testing.T, b *Blocklist) int64 {
+	t.Helper()
+
+	n, err := b.Count()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return n
+}
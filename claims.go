@@ -1,9 +1,12 @@
 package jwt
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
 	"time"
 )
 
@@ -14,11 +17,56 @@ var (
 	ErrNotValidYet = errors.New("jwt: token not valid yet")
 	// ErrIssuedInTheFuture indicates that the "iat" claim is in the future.
 	ErrIssuedInTheFuture = errors.New("jwt: token issued in the future")
+	// ErrInvalidTimestamp indicates that a "nbf", "iat" or "exp" claim is not a number,
+	// e.g. a numeric string without the `WithLenientTimestamps` verify option.
+	ErrInvalidTimestamp = errors.New("jwt: invalid timestamp claim")
 )
 
+// ValidationError describes a failed validation of a standard time claim.
+// It wraps the sentinel error, e.g. ErrExpired, so
+// errors.Is(err, ErrExpired) still reports true,
+// and it holds the claim name and the times involved,
+// e.g. to log how long ago a token was expired:
+//
+//	var vErr *jwt.ValidationError
+//	if errors.As(err, &vErr) {
+//	    log.Printf("token expired %s ago", vErr.Now.Sub(vErr.Value))
+//	}
+type ValidationError struct {
+	Err    error         // The sentinel error: ErrExpired, ErrNotValidYet or ErrIssuedInTheFuture.
+	Claim  string        // The claim name: "exp", "nbf" or "iat".
+	Value  time.Time     // The claim's time value.
+	Now    time.Time     // The time which the token was validated against (see `WithClock`).
+	Leeway time.Duration // The clock skew tolerance, if any (see `WithLeeway`, `Leeway` and `Future`).
+}
+
+func newValidationError(err error, claim string, value int64, now time.Time, leeway time.Duration) *ValidationError {
+	return &ValidationError{
+		Err:    err,
+		Claim:  claim,
+		Value:  time.Unix(value, 0),
+		Now:    now,
+		Leeway: leeway,
+	}
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%v: %s: %s, now: %s", e.Err, e.Claim,
+		e.Value.UTC().Format(time.RFC3339), e.Now.UTC().Format(time.RFC3339))
+}
+
+// Unwrap returns the sentinel error.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
 // Claims holds the standard JWT claims (payload fields).
 // It can be used to validate the JWT and to sign it.
 // It completes the `SignOption` interface.
+//
+// On `Verify`, the "nbf", "iat" and "exp" numeric dates
+// of a float form (e.g. 1700000000.5) are truncated to seconds.
 type Claims struct {
 	// The opposite of the exp claim. A number representing a specific
 	// date and time in the format “seconds since epoch” as defined by POSIX.
@@ -33,8 +81,8 @@ type Claims struct {
 	// format “seconds since epoch” as defined by POSIX6.
 	// This claims sets the exact moment from which
 	// this JWT is considered invalid. This implementation allow for a certain skew
-	// between clocks (by considering this JWT to be valid for a few minutes after the expiration
-	// date, modify the `Clock` variable).
+	// between clocks (by considering this JWT to be valid for a few seconds after the expiration
+	// date, see the `WithLeeway` verify option).
 	Expiry int64 `json:"exp,omitempty"`
 	// A string representing a unique identifier for this JWT. This claim may be
 	// used to differentiate JWTs with other similar content (preventing replays, for instance). It is
@@ -59,33 +107,136 @@ type Claims struct {
 	// disregard the data contained in the JWT. As in the case of the iss and sub claims, this claim
 	// is application specific.
 	Audience Audience `json:"aud,omitempty"`
+
+	// now is the current time installed by the sign and verify functions
+	// for the sign options and the token validators, see `WithSignClock` and `WithClock`.
+	now time.Time
+}
+
+// currentTime returns the installed current time, defaults to the `Clock` package-level variable.
+func (c Claims) currentTime() time.Time {
+	if !c.now.IsZero() {
+		return c.now
+	}
+
+	return Clock()
 }
 
 type claimsSecondChance struct {
-	NotBefore json.Number `json:"nbf,omitempty"`
-	IssuedAt  json.Number `json:"iat,omitempty"`
-	Expiry    json.Number `json:"exp,omitempty"`
-	ID        string      `json:"jti,omitempty"`
-	OriginID  string      `json:"origin_jti,omitempty"`
-	Issuer    interface{} `json:"iss,omitempty"`
-	Subject   interface{} `json:"sub,omitempty"`
-	Audience  Audience    `json:"aud,omitempty"`
+	NotBefore json.RawMessage `json:"nbf,omitempty"`
+	IssuedAt  json.RawMessage `json:"iat,omitempty"`
+	Expiry    json.RawMessage `json:"exp,omitempty"`
+	ID        string          `json:"jti,omitempty"`
+	OriginID  string          `json:"origin_jti,omitempty"`
+	Issuer    interface{}     `json:"iss,omitempty"`
+	Subject   interface{}     `json:"sub,omitempty"`
+	Audience  Audience        `json:"aud,omitempty"`
 }
 
-func (c claimsSecondChance) toClaims() Claims {
-	nbf, _ := c.NotBefore.Float64() // some authorities generates floats for unix timestamp (1-35 seconds), with the leeway of 1 minute we really don't care.
-	iat, _ := c.IssuedAt.Float64()
-	exp, _ := c.Expiry.Float64()
-
-	return Claims{
-		NotBefore: int64(nbf),
-		IssuedAt:  int64(iat),
-		Expiry:    int64(exp),
-		ID:        c.ID,
-		OriginID:  c.OriginID,
-		Issuer:    getStr(c.Issuer),
-		Subject:   getStr(c.Subject),
-		Audience:  c.Audience,
+func (c claimsSecondChance) toClaims(lenient bool) (Claims, error) {
+	claims := Claims{
+		ID:       c.ID,
+		OriginID: c.OriginID,
+		Issuer:   getStr(c.Issuer),
+		Subject:  getStr(c.Subject),
+		Audience: c.Audience,
+	}
+
+	var err error
+	if claims.NotBefore, err = parseNumericDate("nbf", c.NotBefore, lenient); err != nil {
+		return claims, err
+	}
+
+	if claims.IssuedAt, err = parseNumericDate("iat", c.IssuedAt, lenient); err != nil {
+		return claims, err
+	}
+
+	claims.Expiry, err = parseNumericDate("exp", c.Expiry, lenient)
+	return claims, err
+}
+
+// parseNumericDate parses the "raw" value of the "name" time claim as unix seconds.
+// Some authorities generates floats for unix timestamp (1-35 seconds),
+// with the leeway of 1 minute we really don't care, they are truncated.
+// A numeric string is accepted only if "lenient" is true (see `WithLenientTimestamps`).
+func parseNumericDate(name string, raw json.RawMessage, lenient bool) (int64, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return 0, nil
+	}
+
+	if raw[0] == '"' {
+		if !lenient {
+			return 0, fmt.Errorf("%w: %s: string value", ErrInvalidTimestamp, name)
+		}
+
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return 0, fmt.Errorf("%w: %s: %v", ErrInvalidTimestamp, name, err)
+		}
+
+		// ParseFloat accepts "NaN", "Inf" and hex floats too.
+		if !isDecimal(s) {
+			return 0, fmt.Errorf("%w: %s: %q", ErrInvalidTimestamp, name, s)
+		}
+		raw = json.RawMessage(s)
+	}
+
+	n, ok := parseUnixSeconds(string(raw))
+	if !ok {
+		return 0, fmt.Errorf("%w: %s: %s", ErrInvalidTimestamp, name, raw)
+	}
+
+	return n, nil
+}
+
+// parseUnixSeconds parses the JSON number "s" as unix seconds, a float is truncated.
+// It reports false if "s" is not a number or out of the int64 range.
+func parseUnixSeconds(s string) (int64, bool) {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, true
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	// -2^63 and 2^63 are exact floats, the conversion of anything beyond is undefined.
+	if err != nil || math.IsNaN(f) || f < -(1<<63) || f >= 1<<63 {
+		return 0, false
+	}
+
+	return int64(f), true
+}
+
+// isDecimal reports whether "s" is a plain decimal number, e.g. "-1700000000.5".
+func isDecimal(s string) bool {
+	if len(s) > 0 && s[0] == '-' {
+		s = s[1:]
+	}
+
+	digits, dot := 0, false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c >= '0' && c <= '9':
+			digits++
+		case c == '.' && !dot && digits > 0:
+			dot = true
+			digits = 0 // at least one digit after the dot.
+		default:
+			return false
+		}
+	}
+
+	return digits > 0
+}
+
+// WithLenientTimestamps is a VerifyOption which accepts "nbf", "iat" and "exp"
+// claims of numeric strings, e.g. "exp":"1700000000", sent by some non-compliant issuers.
+// By default such tokens are rejected with an error which wraps `ErrInvalidTimestamp`.
+//
+// Usage:
+//
+//	verifiedToken, err := jwt.Verify(jwt.HS256, sharedKey, token, jwt.WithLenientTimestamps())
+func WithLenientTimestamps() VerifyOption {
+	return func(c *verifyConfig) {
+		c.lenientTimestamps = true
 	}
 }
 
@@ -106,7 +257,8 @@ func getStr(v interface{}) string {
 type Audience []string
 
 // UnmarshalJSON implements the json.Unmarshaler interface.
-// The audience is expected to be single string an array of strings.
+// The audience is expected to be a single string or an array of strings
+// (RFC 7519, Section 4.1.3), it is always normalized to a slice.
 func (aud *Audience) UnmarshalJSON(data []byte) (err error) {
 	// Fixes #3.
 	if len(data) > 0 {
@@ -146,26 +298,63 @@ func (c Claims) Timeleft() time.Duration {
 	// return c.ExpiresAt().Sub(Clock())
 }
 
+// Valid validates the "nbf", "iat" and "exp" claims against the given "now" time,
+// the same way the `Verify` function does, e.g. for claims decoded from a custom source.
+// It returns a `*ValidationError` which wraps the `ErrNotValidYet`, `ErrIssuedInTheFuture`
+// or `ErrExpired` sentinel error on failure. Zero claims are not validated.
+//
+// Usage:
+//
+//	err := claims.Valid(time.Now())
+//	if errors.Is(err, jwt.ErrExpired) { [...] }
+func (c Claims) Valid(now time.Time) error {
+	return validateClaims(now, c)
+}
+
 // See TokenValidator and its implementations
 // for further validation options.
 func validateClaims(t time.Time, claims Claims) error {
-	now := t.Round(time.Second).Unix()
+	return validateClaimsWithLeeway(t, claims, 0)
+}
 
+// validateClaimsWithLeeway same as validateClaims but it allows
+// a "leeway" tolerance on each one of the "nbf", "iat" and "exp" checks.
+func validateClaimsWithLeeway(t time.Time, claims Claims, leeway time.Duration) error {
+	if err := validateNotBefore(t, claims, leeway); err != nil {
+		return err
+	}
+
+	if err := validateIssuedAt(t, claims, leeway); err != nil {
+		return err
+	}
+
+	return validateExpiry(t, claims, leeway)
+}
+
+func validateNotBefore(t time.Time, claims Claims, leeway time.Duration) error {
 	if claims.NotBefore > 0 {
-		if now < claims.NotBefore {
-			return ErrNotValidYet
+		if t.Add(leeway).Round(time.Second).Unix() < claims.NotBefore {
+			return newValidationError(ErrNotValidYet, "nbf", claims.NotBefore, t, leeway)
 		}
 	}
 
+	return nil
+}
+
+func validateIssuedAt(t time.Time, claims Claims, leeway time.Duration) error {
 	if claims.IssuedAt > 0 {
-		if now < claims.IssuedAt {
-			return ErrIssuedInTheFuture
+		if t.Add(leeway).Round(time.Second).Unix() < claims.IssuedAt {
+			return newValidationError(ErrIssuedInTheFuture, "iat", claims.IssuedAt, t, leeway)
 		}
 	}
 
+	return nil
+}
+
+func validateExpiry(t time.Time, claims Claims, leeway time.Duration) error {
 	if claims.Expiry > 0 {
-		if now > claims.Expiry {
-			return ErrExpired
+		if t.Add(-leeway).Round(time.Second).Unix() > claims.Expiry {
+			return newValidationError(ErrExpired, "exp", claims.Expiry, t, leeway)
 		}
 	}
 
@@ -213,22 +402,27 @@ func (c Claims) ApplyClaims(dest *Claims) {
 //
 // If maxAge > second then sets expiration to the token.
 // It's a helper field to set the `Expiry` and `IssuedAt`
-// fields at once.
+// fields at once: "iat" is the current time and "exp" is
+// the current time plus the "maxAge", both read from a single clock call
+// at sign time.
 //
-// See the `Clock` package-level variable to modify
+// See the `WithSignClock` option to modify
 // the current time function.
 func MaxAge(maxAge time.Duration) SignOptionFunc {
 	return func(c *Claims) {
 		if maxAge <= time.Second {
 			return
 		}
-		now := Clock()
+		now := c.currentTime()
 		c.Expiry = now.Add(maxAge).Unix()
 		c.IssuedAt = now.Unix()
 	}
 }
 
 // MaxAgeMap is a helper to set "exp" and "iat" claims to a map claims.
+// The current time is read from the `Clock` package-level variable,
+// pass the `MaxAge` option along with `WithSignClock` to the `Sign` function instead
+// to use a different clock per call.
 // Usage:
 // claims := map[string]interface{}{"foo": "bar"}
 // MaxAgeMap(15 * time.Minute, claims)
@@ -249,13 +443,17 @@ func MaxAgeMap(maxAge time.Duration, claims Map) {
 	}
 }
 
+var emptyObject = []byte("{}")
+
 // Merge accepts two claim structs or maps
 // and returns a flattened JSON result of both (no checks for duplicatations are maden).
+// It is the fastest way to merge claims, if the inputs may contain
+// the same fields (e.g. "exp") use the `MergeUnique` function instead.
 //
 // Usage:
 //
 //	claims := Merge(map[string]interface{}{"foo":"bar"}, Claims{
-//	  MaxAge: 15 * time.Minute,
+//	  Expiry: time.Now().Add(15 * time.Minute).Unix(),
 //	  Issuer: "an-issuer",
 //	})
 //	Sign(alg, key, claims)
@@ -264,6 +462,7 @@ func MaxAgeMap(maxAge time.Duration, claims Map) {
 //
 //	Sign(alg, key, claims, MaxAge(time.Duration))
 //	Sign(alg, key, claims, Claims{...})
+//	Sign(alg, key, claims, WithClaims(Claims{...}))
 func Merge(claims interface{}, other interface{}) []byte {
 	claimsB, err := Marshal(claims)
 	if err != nil {
@@ -275,14 +474,68 @@ func Merge(claims interface{}, other interface{}) []byte {
 		return nil
 	}
 
-	if len(otherB) == 0 {
+	if len(otherB) == 0 || bytes.Equal(otherB, emptyObject) {
 		return claimsB
 	}
 
+	if bytes.Equal(claimsB, emptyObject) {
+		return otherB
+	}
+
 	claimsB = claimsB[0 : len(claimsB)-1] // remove last '}'
 	otherB = otherB[1:]                   // remove first '{'
 
-	raw := append(claimsB, ',')
+	// Do not append to claimsB itself, a raw []byte input
+	// (e.g. a fixed header) would be modified.
+	raw := make([]byte, 0, len(claimsB)+1+len(otherB))
+	raw = append(raw, claimsB...)
+	raw = append(raw, ',')
 	raw = append(raw, otherB...)
 	return raw
 }
+
+// MergeUnique same as `Merge` but it guarantees that the result has no duplicated keys.
+// Both claim structs or maps are decoded and merged, the "other" wins
+// on fields that are present in both, and the result is encoded again (sorted keys).
+// It is slower than `Merge`, use it when the inputs may overlap.
+//
+// It returns nil if any of the inputs is not a JSON object.
+//
+// Usage:
+//
+//	claims := MergeUnique(map[string]interface{}{"foo":"bar", "exp": 1}, Claims{
+//	  Expiry: time.Now().Add(15 * time.Minute).Unix(),
+//	})
+//	Sign(alg, key, claims)
+func MergeUnique(claims interface{}, other interface{}) []byte {
+	claimsB, err := Marshal(claims)
+	if err != nil {
+		return nil
+	}
+
+	otherB, err := Marshal(other)
+	if err != nil {
+		return nil
+	}
+
+	var merged map[string]json.RawMessage
+	if err = json.Unmarshal(claimsB, &merged); err != nil || merged == nil {
+		return nil
+	}
+
+	var otherFields map[string]json.RawMessage
+	if err = json.Unmarshal(otherB, &otherFields); err != nil {
+		return nil
+	}
+
+	for k, v := range otherFields {
+		merged[k] = v
+	}
+
+	raw, err := json.Marshal(merged)
+	if err != nil {
+		return nil
+	}
+
+	return raw
+}
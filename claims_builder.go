@@ -0,0 +1,87 @@
+package jwt
+
+import "time"
+
+// ClaimsBuilder is a fluent builder of the standard Claims.
+// The "iat" and "exp" claims are computed on `Build`,
+// relative to the `SetClock` one, defaults to the `Clock` package-level variable.
+// See `NewClaims` to create a new one.
+type ClaimsBuilder struct {
+	claims Claims
+	maxAge time.Duration
+	clock  func() time.Time
+}
+
+// NewClaims returns a new ClaimsBuilder.
+//
+// Example Code:
+//
+//	claims := jwt.NewClaims().
+//	  SetSubject("user-id").
+//	  SetIssuer("my-app").
+//	  SetAudience("my-api").
+//	  SetExpiry(15 * time.Minute).
+//	  Build()
+//	token, err := jwt.Sign(jwt.HS256, sharedKey, claims)
+func NewClaims() *ClaimsBuilder {
+	return new(ClaimsBuilder)
+}
+
+// SetSubject sets the "sub" claim.
+func (b *ClaimsBuilder) SetSubject(sub string) *ClaimsBuilder {
+	b.claims.Subject = sub
+	return b
+}
+
+// SetIssuer sets the "iss" claim.
+func (b *ClaimsBuilder) SetIssuer(iss string) *ClaimsBuilder {
+	b.claims.Issuer = iss
+	return b
+}
+
+// SetAudience sets the "aud" claim.
+func (b *ClaimsBuilder) SetAudience(aud ...string) *ClaimsBuilder {
+	b.claims.Audience = aud
+	return b
+}
+
+// SetID sets the "jti" claim.
+func (b *ClaimsBuilder) SetID(id string) *ClaimsBuilder {
+	b.claims.ID = id
+	return b
+}
+
+// SetExpiry sets the lifetime of the token,
+// the "exp" claim is computed on `Build` as now+maxAge.
+// Like `MaxAge`, a "maxAge" of less or equal to a second is ignored.
+func (b *ClaimsBuilder) SetExpiry(maxAge time.Duration) *ClaimsBuilder {
+	b.maxAge = maxAge
+	return b
+}
+
+// SetClock sets the current time source which the "iat" and "exp" claims
+// are computed against on `Build`, instead of the `Clock` package-level variable.
+func (b *ClaimsBuilder) SetClock(clock func() time.Time) *ClaimsBuilder {
+	b.clock = clock
+	return b
+}
+
+// Build returns the standard Claims.
+// The "iat" claim is set to the current time and
+// the "exp" to the current time plus the `SetExpiry` duration, if any.
+// The result can be passed as the claims or as a SignOption of the `Sign` function.
+func (b *ClaimsBuilder) Build() Claims {
+	claims := b.claims
+	clock := b.clock
+	if clock == nil {
+		clock = Clock
+	}
+
+	now := clock()
+	claims.IssuedAt = now.Unix()
+	if b.maxAge > time.Second {
+		claims.Expiry = now.Add(b.maxAge).Unix()
+	}
+
+	return claims
+}
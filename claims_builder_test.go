@@ -0,0 +1,97 @@
+package jwt
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClaimsBuilder(t *testing.T) {
+	prevClock := Clock
+	defer func() {
+		Clock = prevClock
+	}()
+
+	now := time.Date(2020, 10, 26, 1, 1, 1, 0, time.UTC)
+	Clock = func() time.Time {
+		return now
+	}
+
+	claims := NewClaims().
+		SetSubject("u1").
+		SetAudience("api").
+		SetExpiry(15 * time.Minute).
+		SetIssuer("me").
+		SetID("id").
+		Build()
+
+	expected := Claims{
+		IssuedAt: now.Unix(),
+		Expiry:   now.Add(15 * time.Minute).Unix(),
+		ID:       "id",
+		Issuer:   "me",
+		Subject:  "u1",
+		Audience: Audience{"api"},
+	}
+
+	if !reflect.DeepEqual(expected, claims) {
+		t.Fatalf("expected claims:\n%#+v\nbut got:\n%#+v", expected, claims)
+	}
+
+	if expected, got := 15*time.Minute, claims.Age(); expected != got {
+		t.Fatalf("expected age: %s but got: %s", expected, got)
+	}
+
+	token, err := Sign(testAlg, testSecret, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token, WithExpectedAudience("api"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(expected, verifiedToken.StandardClaims) {
+		t.Fatalf("expected verified claims:\n%#+v\nbut got:\n%#+v", expected, verifiedToken.StandardClaims)
+	}
+
+	// As a SignOption.
+	token, err = Sign(testAlg, testSecret, Map{"username": "kataras"}, NewClaims().SetSubject("u1").Build())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err = Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "u1", verifiedToken.StandardClaims.Subject; expected != got {
+		t.Fatalf("expected subject: %q but got: %q", expected, got)
+	}
+
+	if got := verifiedToken.StandardClaims.Expiry; got != 0 {
+		t.Fatalf("expected no expiration but got: %d", got)
+	}
+
+	// The times are computed on Build.
+	b := NewClaims().SetExpiry(time.Minute)
+	now = now.Add(time.Hour)
+	if expected, got := now.Add(time.Minute).Unix(), b.Build().Expiry; expected != got {
+		t.Fatalf("expected exp: %d but got: %d", expected, got)
+	}
+}
+
+func TestClaimsBuilderSetClock(t *testing.T) {
+	now := time.Date(2020, 10, 26, 1, 1, 1, 0, time.UTC)
+
+	claims := NewClaims().SetExpiry(time.Minute).SetClock(func() time.Time { return now }).Build()
+	if expected, got := now.Unix(), claims.IssuedAt; expected != got {
+		t.Fatalf("expected iat: %d but got: %d", expected, got)
+	}
+
+	if expected, got := now.Add(time.Minute).Unix(), claims.Expiry; expected != got {
+		t.Fatalf("expected exp: %d but got: %d", expected, got)
+	}
+}
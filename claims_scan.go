@@ -0,0 +1,255 @@
+package jwt
+
+// WithTimeOnlyValidation is a VerifyOption which validates the "exp", "nbf" and "iat"
+// claims by scanning the payload for just those fields,
+// instead of decoding the JSON payload to the standard Claims structure.
+// It is a fast path for the common case of checking the token's lifetime only.
+//
+// The verified token's StandardClaims contain only the time claims,
+// so validators of the rest standard claims (e.g. `Expected`)
+// should not be combined with it. The `WithBlocklist` and `WithExpectedAuthorizedParty`
+// options disable the fast path, the payload is fully decoded instead.
+// The `VerifiedToken.Claims` method can still be used to decode the whole payload.
+//
+// On a payload which can not be scanned (e.g. malformed JSON)
+// it falls back to the full decode.
+//
+// Usage:
+//
+//	verifiedToken, err := jwt.Verify(jwt.HS256, sharedKey, token, jwt.WithTimeOnlyValidation())
+func WithTimeOnlyValidation() VerifyOption {
+	return func(c *verifyConfig) {
+		c.timeOnly = true
+	}
+}
+
+// scanTimeClaims scans the top-level "exp", "nbf" and "iat" fields of a JSON object payload.
+// It reports false if the payload is not a JSON object, a key contains escape characters
+// or a time claim is not a number, so the caller can fall back to the full decode.
+func scanTimeClaims(payload []byte) (Claims, bool) {
+	var claims Claims
+
+	i := skipSpace(payload, 0)
+	if i >= len(payload) || payload[i] != '{' {
+		return claims, false
+	}
+
+	i = skipSpace(payload, i+1)
+	if i < len(payload) && payload[i] == '}' {
+		return claims, skipSpace(payload, i+1) == len(payload)
+	}
+
+	for {
+		// key.
+		if i >= len(payload) || payload[i] != '"' {
+			return claims, false
+		}
+
+		start := i + 1
+		end := start
+		for end < len(payload) && payload[end] != '"' {
+			if payload[end] == '\\' {
+				return claims, false
+			}
+			end++
+		}
+
+		if end >= len(payload) {
+			return claims, false
+		}
+
+		key := payload[start:end]
+
+		i = skipSpace(payload, end+1)
+		if i >= len(payload) || payload[i] != ':' {
+			return claims, false
+		}
+		i = skipSpace(payload, i+1)
+
+		// value.
+		var (
+			dest *int64
+			ok   bool
+		)
+		switch string(key) {
+		case "exp":
+			dest = &claims.Expiry
+		case "nbf":
+			dest = &claims.NotBefore
+		case "iat":
+			dest = &claims.IssuedAt
+		}
+
+		if dest != nil {
+			*dest, i, ok = scanNumber(payload, i)
+		} else {
+			i, ok = skipValue(payload, i)
+		}
+
+		if !ok {
+			return claims, false
+		}
+
+		i = skipSpace(payload, i)
+		if i >= len(payload) {
+			return claims, false
+		}
+
+		switch payload[i] {
+		case ',':
+			i = skipSpace(payload, i+1)
+		case '}':
+			return claims, skipSpace(payload, i+1) == len(payload)
+		default:
+			return claims, false
+		}
+	}
+}
+
+// walkObject calls "fn" for each top-level field of the JSON object "b"
+// with its key (as it is, without the quotes) and the start and end indices of its value.
+// It stops when "fn" returns false.
+// It reports false if "b" is not a JSON object.
+func walkObject(b []byte, fn func(key []byte, start, end int) bool) bool {
+	i := skipSpace(b, 0)
+	if i >= len(b) || b[i] != '{' {
+		return false
+	}
+
+	i = skipSpace(b, i+1)
+	if i < len(b) && b[i] == '}' {
+		return true
+	}
+
+	for i < len(b) && b[i] == '"' {
+		keyEnd, ok := skipString(b, i)
+		if !ok {
+			return false
+		}
+		key := b[i+1 : keyEnd-1]
+
+		i = skipSpace(b, keyEnd)
+		if i >= len(b) || b[i] != ':' {
+			return false
+		}
+		i = skipSpace(b, i+1)
+
+		end, ok := skipValue(b, i)
+		if !ok {
+			return false
+		}
+
+		if !fn(key, i, end) {
+			return true
+		}
+
+		i = skipSpace(b, end)
+		if i >= len(b) {
+			return false
+		}
+
+		switch b[i] {
+		case ',':
+			i = skipSpace(b, i+1)
+		case '}':
+			return true
+		default:
+			return false
+		}
+	}
+
+	return false
+}
+
+func skipSpace(b []byte, i int) int {
+	for i < len(b) {
+		switch b[i] {
+		case ' ', '\t', '\r', '\n':
+			i++
+		default:
+			return i
+		}
+	}
+
+	return i
+}
+
+// scanNumber parses the number at "i" as unix seconds,
+// floats are truncated like the full decode does.
+func scanNumber(b []byte, i int) (int64, int, bool) {
+	start := i
+	for i < len(b) {
+		c := b[i]
+		if (c >= '0' && c <= '9') || c == '-' || c == '+' || c == '.' || c == 'e' || c == 'E' {
+			i++
+			continue
+		}
+		break
+	}
+
+	if start == i {
+		return 0, i, false
+	}
+
+	n, ok := parseUnixSeconds(BytesToString(b[start:i]))
+	return n, i, ok
+}
+
+// skipValue skips the JSON value at "i" and returns the index after it.
+// The nested objects and arrays are not validated, only their strings and brackets are tracked.
+func skipValue(b []byte, i int) (int, bool) {
+	if i >= len(b) {
+		return i, false
+	}
+
+	switch b[i] {
+	case '"':
+		return skipString(b, i)
+	case '{', '[':
+		depth := 0
+		for i < len(b) {
+			switch b[i] {
+			case '"':
+				var ok bool
+				if i, ok = skipString(b, i); !ok {
+					return i, false
+				}
+				continue
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					return i + 1, true
+				}
+			}
+			i++
+		}
+
+		return i, false
+	default: // number, true, false or null.
+		start := i
+		for i < len(b) {
+			switch b[i] {
+			case ',', '}', ']', ' ', '\t', '\r', '\n':
+				return i, i > start
+			}
+			i++
+		}
+
+		return i, false
+	}
+}
+
+func skipString(b []byte, i int) (int, bool) {
+	for i++; i < len(b); i++ {
+		switch b[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1, true
+		}
+	}
+
+	return i, false
+}
@@ -0,0 +1,145 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestScanTimeClaims(t *testing.T) {
+	var tests = []struct {
+		payload  string
+		expected Claims
+		ok       bool
+	}{
+		{`{}`, Claims{}, true},
+		{` { "exp" : 1700000000 } `, Claims{Expiry: 1700000000}, true},
+		{`{"username":"kataras","nbf":1,"iat":2,"exp":3}`, Claims{NotBefore: 1, IssuedAt: 2, Expiry: 3}, true},
+		{`{"exp":1.7e9,"iat":1600000000.52}`, Claims{Expiry: 1700000000, IssuedAt: 1600000000}, true},
+		{`{"a":{"exp":1,"b":["}", "\"exp\"", {"c": null}]},"exp":2,"d":[1,2],"e":true,"f":"x\\"}`, Claims{Expiry: 2}, true},
+		{`{"exp":1,"exp":2}`, Claims{Expiry: 2}, true}, // last wins, like encoding/json.
+		// fall back.
+		{``, Claims{}, false},
+		{`[]`, Claims{}, false},
+		{`raw payload`, Claims{}, false},
+		{`{"exp":"1700000000"}`, Claims{}, false},
+		{`{"exp":null}`, Claims{}, false},
+		{`{"exp":-}`, Claims{}, false},
+		{`{"exp":1`, Claims{}, false},
+		{`{"exp":1,}`, Claims{}, false},
+		{`{"a":"b" "exp":1}`, Claims{}, false},
+		{`{"a":{"b":1}`, Claims{}, false},
+		{`{"exp":1} trailing`, Claims{}, false},
+		{`{"exp":1e19}`, Claims{}, false}, // out of the int64 range.
+		{`{"exp":-1e19}`, Claims{}, false},
+		{`{"exp":1.8e308}`, Claims{}, false},
+	}
+
+	for i, tt := range tests {
+		got, ok := scanTimeClaims([]byte(tt.payload))
+		if ok != tt.ok {
+			t.Fatalf("[%d] %s: expected ok: %v but got: %v", i, tt.payload, tt.ok, ok)
+		}
+
+		if ok && !reflect.DeepEqual(got, tt.expected) {
+			t.Fatalf("[%d] %s: expected claims: %#+v but got: %#+v", i, tt.payload, tt.expected, got)
+		}
+	}
+}
+
+func TestWithTimeOnlyValidation(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"}, Claims{Issuer: "my-app"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token, WithTimeOnlyValidation())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if verifiedToken.StandardClaims.Expiry == 0 || verifiedToken.StandardClaims.IssuedAt == 0 {
+		t.Fatalf("expected time claims to be set")
+	}
+
+	if verifiedToken.StandardClaims.Issuer != "" {
+		t.Fatalf("expected time claims only")
+	}
+
+	expiredToken, err := Sign(testAlg, testSecret, Map{"username": "kataras"}, Claims{Expiry: Clock().Add(-time.Minute).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, expiredToken, WithTimeOnlyValidation()); !errors.Is(err, ErrExpired) {
+		t.Fatalf("expected error: ErrExpired but got: %v", err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, expiredToken, WithTimeOnlyValidation(), WithLeeway(2*time.Minute)); err != nil {
+		t.Fatalf("expected leeway to be respected but got: %v", err)
+	}
+
+	// Fall back to the full decode on a non-JSON payload.
+	plainToken, err := Sign(testAlg, testSecret, []byte("raw payload"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, plainToken, WithTimeOnlyValidation()); err != errPayloadNotJSON {
+		t.Fatalf("expected error: errPayloadNotJSON but got: %v", err)
+	}
+}
+
+func TestWithTimeOnlyValidationFullDecode(t *testing.T) {
+	blocklist := NewMemoryBlocklist()
+	defer blocklist.Close()
+
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"}, Claims{ID: "jti:1"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = blocklist.Set("jti:1", Clock().Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The blocklist needs the "jti" claim.
+	if _, err = Verify(testAlg, testSecret, token, WithBlocklist(blocklist), WithTimeOnlyValidation()); err != ErrBlocked {
+		t.Fatalf("expected error: ErrBlocked but got: %v", err)
+	}
+
+	// The authorized party needs the "aud" claim.
+	token, err = Sign(testAlg, testSecret, Map{"aud": []string{"client", "other-api"}}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, WithExpectedAuthorizedParty("client"), WithTimeOnlyValidation()); !errors.Is(err, ErrInvalidAuthorizedParty) {
+		t.Fatalf("expected error: ErrInvalidAuthorizedParty but got: %v", err)
+	}
+}
+
+var benchmarkScanPayload = []byte(`{"username":"kataras","email":"kataras2006@hotmail.com","roles":["admin","user"],"iat":1600000000,"exp":1900000000,"iss":"my-app","sub":"kataras","aud":["app1","app2"]}`)
+
+func BenchmarkScanTimeClaims(b *testing.B) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, ok := scanTimeClaims(benchmarkScanPayload); !ok {
+			b.Fatal("scan failed")
+		}
+	}
+}
+
+func BenchmarkUnmarshalClaims(b *testing.B) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var claims Claims
+		if err := json.Unmarshal(benchmarkScanPayload, &claims); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
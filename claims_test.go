@@ -1,6 +1,9 @@
 package jwt
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"reflect"
 	"testing"
 	"time"
@@ -37,7 +40,7 @@ func TestValidateClaimsNotBefore(t *testing.T) {
 	claims := Claims{
 		NotBefore: now.Add(1 * time.Minute).Unix(),
 	}
-	if err := validateClaims(now, claims); err != ErrNotValidYet {
+	if err := validateClaims(now, claims); !errors.Is(err, ErrNotValidYet) {
 		t.Fatalf("expected token error: %v but got: %v", ErrNotValidYet, err)
 	}
 }
@@ -53,7 +56,7 @@ func TestValidateClaimsIssuedAt(t *testing.T) {
 	// t.Logf("Now Unix: %d", now.Unix())
 	// t.Logf("Before now Unix: %d", past.Unix())
 
-	if err := validateClaims(past, claims); err != ErrIssuedInTheFuture {
+	if err := validateClaims(past, claims); !errors.Is(err, ErrIssuedInTheFuture) {
 		t.Fatalf("expected token error: %v but got: %v", ErrIssuedInTheFuture, err)
 	}
 }
@@ -64,11 +67,261 @@ func TestValidateClaimsExpiry(t *testing.T) {
 		Expiry: now.Add(20 * time.Second).Unix(),
 	}
 
-	if err := validateClaims(now.Add(21*time.Second), claims); err != ErrExpired {
+	if err := validateClaims(now.Add(21*time.Second), claims); !errors.Is(err, ErrExpired) {
 		t.Fatalf("expected token error: %v but got: %v", ErrExpired, err)
 	}
 }
 
+func TestClaimsValid(t *testing.T) {
+	now := time.Now()
+
+	var tests = []struct {
+		claims Claims
+		err    error
+	}{
+		{Claims{}, nil},
+		{Claims{NotBefore: now.Unix(), IssuedAt: now.Unix(), Expiry: now.Add(time.Minute).Unix()}, nil},
+		{Claims{NotBefore: now.Add(1 * time.Minute).Unix()}, ErrNotValidYet},
+		{Claims{IssuedAt: now.Add(2 * time.Minute).Unix()}, ErrIssuedInTheFuture},
+		{Claims{Expiry: now.Add(-20 * time.Second).Unix()}, ErrExpired},
+	}
+
+	for i, tt := range tests {
+		err := tt.claims.Valid(now)
+		if !errors.Is(err, tt.err) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, tt.err, err)
+		}
+
+		var vErr *ValidationError
+		if tt.err != nil && !errors.As(err, &vErr) {
+			t.Fatalf("[%d] expected a *ValidationError but got: %T", i, err)
+		}
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	leeway := 5 * time.Second
+
+	var tests = []struct {
+		claims      Claims
+		expectedErr error
+		claim       string
+		value       int64
+	}{
+		{Claims{Expiry: now.Unix() - 10}, ErrExpired, "exp", now.Unix() - 10},
+		{Claims{NotBefore: now.Unix() + 10}, ErrNotValidYet, "nbf", now.Unix() + 10},
+		{Claims{IssuedAt: now.Unix() + 10}, ErrIssuedInTheFuture, "iat", now.Unix() + 10},
+	}
+
+	for i, tt := range tests {
+		err := validateClaimsWithLeeway(now, tt.claims, leeway)
+		if !errors.Is(err, tt.expectedErr) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, tt.expectedErr, err)
+		}
+
+		var vErr *ValidationError
+		if !errors.As(err, &vErr) {
+			t.Fatalf("[%d] expected a *ValidationError but got: %T", i, err)
+		}
+
+		if vErr.Unwrap() != tt.expectedErr {
+			t.Fatalf("[%d] expected unwrapped error: %v but got: %v", i, tt.expectedErr, vErr.Unwrap())
+		}
+
+		if vErr.Claim != tt.claim {
+			t.Fatalf("[%d] expected claim: %q but got: %q", i, tt.claim, vErr.Claim)
+		}
+
+		if !vErr.Value.Equal(time.Unix(tt.value, 0)) {
+			t.Fatalf("[%d] expected value: %s but got: %s", i, time.Unix(tt.value, 0), vErr.Value)
+		}
+
+		if !vErr.Now.Equal(now) {
+			t.Fatalf("[%d] expected now: %s but got: %s", i, now, vErr.Now)
+		}
+
+		if vErr.Leeway != leeway {
+			t.Fatalf("[%d] expected leeway: %s but got: %s", i, leeway, vErr.Leeway)
+		}
+	}
+
+	err := validateClaims(now, Claims{Expiry: now.Unix() - 60})
+	if expected, got := "jwt: token expired: exp: 2020-09-13T12:25:40Z, now: 2020-09-13T12:26:40Z", err.Error(); expected != got {
+		t.Fatalf("expected error message: %q but got: %q", expected, got)
+	}
+
+	// Through Verify and the Leeway validator.
+	token, err := Sign(testAlg, testSecret, Claims{Expiry: Clock().Add(2 * time.Second).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Verify(testAlg, testSecret, token, Leeway(time.Minute))
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || !errors.Is(err, ErrExpired) {
+		t.Fatalf("expected a *ValidationError of ErrExpired but got: %v", err)
+	}
+
+	if vErr.Leeway != time.Minute {
+		t.Fatalf("expected leeway: %s but got: %s", time.Minute, vErr.Leeway)
+	}
+}
+
+func TestAudienceUnmarshalJSON(t *testing.T) {
+	var tests = []struct {
+		payload  string
+		expected Audience
+	}{
+		{`{"aud":"api"}`, Audience{"api"}},
+		{`{"aud":["api"]}`, Audience{"api"}},
+		{`{"aud":["api","admin"]}`, Audience{"api", "admin"}},
+		{`{"aud": "api" }`, Audience{"api"}},
+		{`{"aud":"https://example.com/api?x=\u0026"}`, Audience{"https://example.com/api?x=&"}},
+		{`{"aud":null}`, nil},
+		{`{}`, nil},
+	}
+
+	for i, tt := range tests {
+		var claims Claims
+		if err := json.Unmarshal([]byte(tt.payload), &claims); err != nil {
+			t.Fatalf("[%d] %v", i, err)
+		}
+
+		if !reflect.DeepEqual(tt.expected, claims.Audience) {
+			t.Fatalf("[%d] expected audience: %#v but got: %#v", i, tt.expected, claims.Audience)
+		}
+	}
+
+	var aud Audience
+	if err := json.Unmarshal([]byte(`[1]`), &aud); err == nil {
+		t.Fatalf("expected error on a non-string array element")
+	}
+
+	// Through Sign and Verify, of a token issued by a provider with a scalar "aud".
+	token, err := Sign(testAlg, testSecret, Map{"aud": "api"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token, WithExpectedAudience("api"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := (Audience{"api"}), verifiedToken.StandardClaims.Audience; !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected audience: %#v but got: %#v", expected, got)
+	}
+}
+
+func TestVerifyNumericDateForms(t *testing.T) {
+	now := Clock().Unix()
+
+	var tests = []struct {
+		payload       string
+		expectedErr   error
+		expectedValue int64
+	}{
+		// integer.
+		{fmt.Sprintf(`{"exp":%d}`, now+60), nil, now + 60},
+		{fmt.Sprintf(`{"exp":%d}`, now-60), ErrExpired, 0},
+		// float, truncated to seconds.
+		{fmt.Sprintf(`{"exp":%d.5}`, now+60), nil, now + 60},
+		{fmt.Sprintf(`{"exp":%d.5}`, now-60), ErrExpired, 0},
+		{fmt.Sprintf(`{"nbf":%d.5}`, now+60), ErrNotValidYet, 0},
+		{fmt.Sprintf(`{"iat":%d.5}`, now+60), ErrIssuedInTheFuture, 0},
+		{`{"exp":1.7e9}`, ErrExpired, 0},
+		// out of the int64 range.
+		{`{"exp":1e19}`, ErrInvalidTimestamp, 0},
+		{`{"exp":-1e19}`, ErrInvalidTimestamp, 0},
+		{`{"exp":1.8e308}`, ErrInvalidTimestamp, 0},
+		{`{"nbf":1e19}`, ErrInvalidTimestamp, 0},
+		{`{"iat":-1e19}`, ErrInvalidTimestamp, 0},
+		{`{"exp":9223372036854775808}`, ErrInvalidTimestamp, 0},
+		// string-number, see TestWithLenientTimestamps.
+		{fmt.Sprintf(`{"exp":"%d"}`, now+60), ErrInvalidTimestamp, 0},
+	}
+
+	for i, tt := range tests {
+		token, err := Sign(testAlg, testSecret, []byte(tt.payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		verifiedToken, err := Verify(testAlg, testSecret, token)
+		if !errors.Is(err, tt.expectedErr) {
+			t.Fatalf("[%d] %s: expected error: %v but got: %v", i, tt.payload, tt.expectedErr, err)
+		}
+
+		// Same result through the time claims scanner.
+		if _, timeOnlyErr := Verify(testAlg, testSecret, token, WithTimeOnlyValidation()); !errors.Is(timeOnlyErr, tt.expectedErr) {
+			t.Fatalf("[%d] %s: expected time only error: %v but got: %v", i, tt.payload, tt.expectedErr, timeOnlyErr)
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if expected, got := tt.expectedValue, verifiedToken.StandardClaims.Expiry; expected != got {
+			t.Fatalf("[%d] expected exp: %d but got: %d", i, expected, got)
+		}
+	}
+}
+
+func TestWithLenientTimestamps(t *testing.T) {
+	now := Clock().Unix()
+
+	var tests = []struct {
+		payload       string
+		strictErr     error // without the option.
+		expectedErr   error
+		expectedValue int64
+	}{
+		{fmt.Sprintf(`{"exp":"%d"}`, now+60), ErrInvalidTimestamp, nil, now + 60},
+		{fmt.Sprintf(`{"exp":"%d.5"}`, now+60), ErrInvalidTimestamp, nil, now + 60},
+		{fmt.Sprintf(`{"exp":"%d"}`, now-60), ErrInvalidTimestamp, ErrExpired, 0},
+		{fmt.Sprintf(`{"nbf":"%d"}`, now+60), ErrInvalidTimestamp, ErrNotValidYet, 0},
+		{fmt.Sprintf(`{"exp":%d}`, now+60), nil, nil, now + 60},
+		{`{"exp":"tomorrow"}`, ErrInvalidTimestamp, ErrInvalidTimestamp, 0},
+		// not a plain finite decimal.
+		{`{"exp":"NaN"}`, ErrInvalidTimestamp, ErrInvalidTimestamp, 0},
+		{`{"exp":"Inf"}`, ErrInvalidTimestamp, ErrInvalidTimestamp, 0},
+		{`{"exp":"+Inf"}`, ErrInvalidTimestamp, ErrInvalidTimestamp, 0},
+		{`{"exp":"-Inf"}`, ErrInvalidTimestamp, ErrInvalidTimestamp, 0},
+		{`{"exp":"0x1p62"}`, ErrInvalidTimestamp, ErrInvalidTimestamp, 0},
+		{`{"exp":"1e19"}`, ErrInvalidTimestamp, ErrInvalidTimestamp, 0},
+		{`{"exp":"99999999999999999999"}`, ErrInvalidTimestamp, ErrInvalidTimestamp, 0},
+		{`{"exp":"+1700000000"}`, ErrInvalidTimestamp, ErrInvalidTimestamp, 0},
+		{`{"exp":"1700000000."}`, ErrInvalidTimestamp, ErrInvalidTimestamp, 0},
+		{`{"exp":""}`, ErrInvalidTimestamp, ErrInvalidTimestamp, 0},
+		{`{"exp":true}`, ErrInvalidTimestamp, ErrInvalidTimestamp, 0},
+	}
+
+	for i, tt := range tests {
+		token, err := Sign(testAlg, testSecret, []byte(tt.payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = Verify(testAlg, testSecret, token); !errors.Is(err, tt.strictErr) {
+			t.Fatalf("[%d] %s: expected strict error: %v but got: %v", i, tt.payload, tt.strictErr, err)
+		}
+
+		verifiedToken, err := Verify(testAlg, testSecret, token, WithLenientTimestamps())
+		if !errors.Is(err, tt.expectedErr) {
+			t.Fatalf("[%d] %s: expected error: %v but got: %v", i, tt.payload, tt.expectedErr, err)
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if expected, got := tt.expectedValue, verifiedToken.StandardClaims.Expiry; expected != got {
+			t.Fatalf("[%d] expected exp: %d but got: %d", i, expected, got)
+		}
+	}
+}
+
 func TestApplyClaims(t *testing.T) {
 	claims := Claims{
 		NotBefore: 1,
@@ -110,6 +363,66 @@ func TestMaxAge(t *testing.T) {
 	}
 }
 
+func TestSignWithMaxAge(t *testing.T) {
+	prevClock := Clock
+	defer func() {
+		Clock = prevClock
+	}()
+
+	now := time.Date(2020, 10, 26, 1, 1, 1, 0, time.UTC)
+	Clock = func() time.Time {
+		return now
+	}
+
+	maxAge := 15 * time.Minute
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"}, MaxAge(maxAge))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var claims map[string]interface{}
+	if err = json.Unmarshal(verifiedToken.Payload, &claims); err != nil {
+		t.Fatal(err)
+	}
+
+	iat, ok := claims["iat"].(float64)
+	if !ok {
+		t.Fatalf("expected iat claim to be present but got: %v", claims["iat"])
+	}
+
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		t.Fatalf("expected exp claim to be present but got: %v", claims["exp"])
+	}
+
+	if expected, got := now.Unix(), int64(iat); expected != got {
+		t.Fatalf("expected iat: %d but got: %d", expected, got)
+	}
+
+	if expected, got := int64(maxAge.Seconds()), int64(exp-iat); expected != got {
+		t.Fatalf("expected exp - iat: %d but got: %d", expected, got)
+	}
+
+	if expected, got := verifiedToken.StandardClaims.Timeleft(), maxAge; expected != got {
+		t.Fatalf("expected time left: %s but got: %s", expected, got)
+	}
+
+	if claims["username"] != "kataras" {
+		t.Fatalf("expected custom claims to be kept but got: %v", claims)
+	}
+
+	// The token expires after max age.
+	now = now.Add(maxAge + time.Second)
+	if _, err = Verify(testAlg, testSecret, token); !errors.Is(err, ErrExpired) {
+		t.Fatalf("expected error: ErrExpired but got: %v", err)
+	}
+}
+
 func TestMaxAgeMap(t *testing.T) {
 	prevClock := Clock
 	defer func() {
@@ -162,3 +475,70 @@ func TestClaimsSubAsInt(t *testing.T) {
 		t.Fatalf("expected: %#+v but got: %#+v\n", expectedClaims, verifiedToken.StandardClaims)
 	}
 }
+
+func TestMerge(t *testing.T) {
+	var tests = []struct {
+		claims   interface{}
+		other    interface{}
+		expected string
+	}{
+		{Map{"username": "kataras"}, Claims{Issuer: "myapp"}, `{"username":"kataras","iss":"myapp"}`},
+		{Map{"username": "kataras"}, Claims{}, `{"username":"kataras"}`},
+		{Map{}, Claims{Issuer: "myapp"}, `{"iss":"myapp"}`},
+		{Map{}, Claims{}, `{}`},
+	}
+
+	for i, tt := range tests {
+		got := Merge(tt.claims, tt.other)
+		if string(got) != tt.expected {
+			t.Fatalf("[%d] expected: %s but got: %s", i, tt.expected, got)
+		}
+	}
+
+	// A raw input with extra capacity should not be modified.
+	raw := make([]byte, 0, 64)
+	raw = append(raw, `{"alg":"HS256","typ":"JWT"}`...)
+	if got := Merge(raw, Map{"kid": "1"}); string(got) != `{"alg":"HS256","typ":"JWT","kid":"1"}` {
+		t.Fatalf("unexpected merge result: %s", got)
+	}
+
+	if expected, got := `{"alg":"HS256","typ":"JWT"}`, string(raw); expected != got {
+		t.Fatalf("expected input to not be modified: %s but got: %s", expected, got)
+	}
+}
+
+func TestMergeUnique(t *testing.T) {
+	var tests = []struct {
+		claims   interface{}
+		other    interface{}
+		expected string
+	}{
+		{Map{"username": "kataras", "exp": 1}, Claims{Expiry: 2}, `{"exp":2,"username":"kataras"}`},
+		{Map{"username": "kataras", "role": "user"}, Map{"role": "admin", "iss": "myapp"}, `{"iss":"myapp","role":"admin","username":"kataras"}`},
+		{[]byte(`{"exp":1,"exp":3}`), Claims{}, `{"exp":3}`},
+		{Map{}, Claims{Issuer: "myapp"}, `{"iss":"myapp"}`},
+		{Map{"username": "kataras"}, nil, `{"username":"kataras"}`},
+	}
+
+	for i, tt := range tests {
+		got := MergeUnique(tt.claims, tt.other)
+		if string(got) != tt.expected {
+			t.Fatalf("[%d] expected: %s but got: %s", i, tt.expected, got)
+		}
+	}
+
+	// The byte-concatenation Merge keeps both keys.
+	if expected, got := `{"exp":1,"exp":2}`, string(Merge(Map{"exp": 1}, Claims{Expiry: 2})); expected != got {
+		t.Fatalf("expected: %s but got: %s", expected, got)
+	}
+
+	for i, tt := range []interface{}{[]byte("[]"), []byte("null"), []byte("{")} {
+		if got := MergeUnique(tt, Claims{Expiry: 2}); got != nil {
+			t.Fatalf("[%d] expected nil but got: %s", i, got)
+		}
+	}
+
+	if got := MergeUnique(Map{"username": "kataras"}, []byte("[]")); got != nil {
+		t.Fatalf("expected nil but got: %s", got)
+	}
+}
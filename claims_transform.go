@@ -0,0 +1,41 @@
+package jwt
+
+// WithClaimsTransform is a SignOption which calls the "transform" function
+// with the assembled claims, after the standard claims of the rest options are merged,
+// its result is the payload which is signed. It can modify and return the given map,
+// e.g. to inject tenant-specific fields or to rename a claim.
+// Numbers are type of json.Number. A nil result signs an empty JSON object.
+// Multiple transforms run in the order they are given.
+//
+// Example Code:
+//
+//	withTenant := jwt.WithClaimsTransform(func(claims map[string]interface{}) map[string]interface{} {
+//	  claims["tenant"] = tenantID
+//	  return claims
+//	})
+//
+//	token, err := jwt.Sign(jwt.HS256, sharedKey, claims, jwt.MaxAge(15*time.Minute), withTenant)
+func WithClaimsTransform(transform func(claims map[string]interface{}) map[string]interface{}) SignConfigOption {
+	return func(c *signConfig) {
+		if transform != nil {
+			c.claimsTransforms = append(c.claimsTransforms, transform)
+		}
+	}
+}
+
+// transformClaims decodes the JSON object "payload",
+// passes it through the "transforms" and encodes the result.
+func transformClaims(payload []byte, transforms []func(map[string]interface{}) map[string]interface{}) ([]byte, error) {
+	var claims map[string]interface{}
+	if err := defaultUnmarshal(payload, &claims); err != nil || claims == nil {
+		return nil, errPayloadNotJSON
+	}
+
+	for _, transform := range transforms {
+		if claims = transform(claims); claims == nil {
+			claims = make(map[string]interface{})
+		}
+	}
+
+	return Marshal(claims)
+}
@@ -0,0 +1,76 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithClaimsTransform(t *testing.T) {
+	renameUsername := WithClaimsTransform(func(claims map[string]interface{}) map[string]interface{} {
+		claims["preferred_username"] = claims["username"]
+		delete(claims, "username")
+		return claims
+	})
+
+	var exp json.Number
+	withTenant := WithClaimsTransform(func(claims map[string]interface{}) map[string]interface{} {
+		// Runs after the standard claims are merged and after the previous transform.
+		exp, _ = claims["exp"].(json.Number)
+		if _, ok := claims["username"]; ok {
+			t.Fatalf("expected the transforms to run in order")
+		}
+
+		claims["tenant"] = "acme"
+		return claims
+	})
+
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"}, MaxAge(time.Minute), renameUsername, withTenant)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var claims Map
+	if err = verifiedToken.Claims(&claims); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := 4, len(claims); expected != got { // preferred_username, tenant, iat, exp.
+		t.Fatalf("expected: %d claims but got: %d: %v", expected, got, claims)
+	}
+
+	if expected, got := "kataras", claims["preferred_username"]; expected != got {
+		t.Fatalf("expected preferred_username: %q but got: %v", expected, got)
+	}
+
+	if expected, got := "acme", claims["tenant"]; expected != got {
+		t.Fatalf("expected tenant: %q but got: %v", expected, got)
+	}
+
+	if expected, got := claims["exp"], exp; got == "" || expected != got {
+		t.Fatalf("expected the merged exp: %v but got: %q", expected, got)
+	}
+
+	// A nil result signs an empty object.
+	token, err = Sign(testAlg, testSecret, Map{"username": "kataras"}, WithClaimsTransform(func(map[string]interface{}) map[string]interface{} {
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if verifiedToken, err = Verify(testAlg, testSecret, token); err != nil || string(verifiedToken.Payload) != "{}" {
+		t.Fatalf("expected an empty payload but got: %s: %v", verifiedToken.Payload, err)
+	}
+
+	// Not a JSON object.
+	if _, err = Sign(testAlg, testSecret, []byte(`["kataras"]`), renameUsername); !errors.Is(err, errPayloadNotJSON) {
+		t.Fatalf("expected error: errPayloadNotJSON but got: %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package jwt
+
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultMaxDecompressedSize is the default maximum size of a compressed payload
+// after its decompression, see `WithMaxDecompressedSize`.
+const DefaultMaxDecompressedSize = 256 << 10 // 256KB.
+
+var (
+	// ErrDecompressedTooLarge indicates that a compressed ("zip":"DEF") payload
+	// exceeds the maximum size after its decompression.
+	ErrDecompressedTooLarge = errors.New("jwt: decompressed payload is too large")
+
+	errUnsupportedCompression = errors.New("jwt: unsupported compression algorithm")
+)
+
+// compressionDeflate is the "zip" header value of the DEFLATE (RFC 1951) compression.
+const compressionDeflate = "DEF"
+
+// WithCompression is a SignOption which compresses the payload
+// with the DEFLATE algorithm and sets the "zip":"DEF" header field (RFC 7516, Section 4.1.3).
+// The payload is compressed before an encryption, if any.
+//
+// The `Verify` functions decompress such payloads automatically.
+//
+// Usage:
+//
+//	token, err := jwt.Sign(jwt.HS256, sharedKey, claims, jwt.WithCompression())
+func WithCompression() SignConfigOption {
+	return func(c *signConfig) {
+		c.compress = true
+		c.setHeader("zip", compressionDeflate)
+	}
+}
+
+// WithMaxDecompressedSize is a VerifyOption which sets the maximum size
+// of a compressed payload after its decompression, it protects
+// against decompression bombs. A larger payload fails with `ErrDecompressedTooLarge`.
+// Defaults to `DefaultMaxDecompressedSize`.
+func WithMaxDecompressedSize(n int64) VerifyOption {
+	return func(c *verifyConfig) {
+		c.maxDecompressedSize = n
+	}
+}
+
+func deflate(payload []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = w.Write(payload); err != nil {
+		return nil, err
+	}
+
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func inflate(payload []byte, maxSize int64) ([]byte, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxDecompressedSize
+	}
+
+	r := flate.NewReader(bytes.NewReader(payload))
+	defer r.Close()
+
+	// Read one more byte to know if the limit was exceeded.
+	b, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: zip: %v", ErrTokenForm, err)
+	}
+
+	if int64(len(b)) > maxSize {
+		return nil, ErrDecompressedTooLarge
+	}
+
+	return b, nil
+}
+
+var zipHeaderKey = []byte(`"zip"`)
+
+// decompressPayload inflates the "payload" if the "header" contains a "zip" field.
+func decompressPayload(header, payload []byte, maxSize int64) ([]byte, error) {
+	if !bytes.Contains(header, zipHeaderKey) {
+		return payload, nil
+	}
+
+	var h struct {
+		Zip string `json:"zip"`
+	}
+	if err := Unmarshal(header, &h); err != nil {
+		return nil, fmt.Errorf("%w: header: %v", ErrTokenForm, err)
+	}
+
+	switch h.Zip {
+	case "":
+		return payload, nil
+	case compressionDeflate:
+		return inflate(payload, maxSize)
+	default:
+		return nil, fmt.Errorf("%w: %q", errUnsupportedCompression, h.Zip)
+	}
+}
@@ -0,0 +1,133 @@
+package jwt
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithCompression(t *testing.T) {
+	claims := Map{"username": strings.Repeat("kataras", 100)}
+
+	token, err := Sign(testAlg, testSecret, claims, WithCompression(), MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uncompressedToken, err := Sign(testAlg, testSecret, claims, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(token) >= len(uncompressedToken) {
+		t.Fatalf("expected compressed token (%d) to be smaller than the uncompressed one (%d)", len(token), len(uncompressedToken))
+	}
+
+	header, err := PeekHeader(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "DEF", header["zip"]; expected != got {
+		t.Fatalf("expected zip header: %q but got: %v", expected, got)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got Map
+	if err = verifiedToken.Claims(&got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got["username"] != claims["username"] {
+		t.Fatalf("expected round-trip claims")
+	}
+
+	if verifiedToken.StandardClaims.Expiry == 0 {
+		t.Fatalf("expected standard claims to be decoded from the decompressed payload")
+	}
+
+	// Compressed and then encrypted.
+	encrypt, decrypt, err := GCM(MustGenerateRandom(32), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err = SignEncrypted(testAlg, testSecret, encrypt, claims, WithCompression())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = VerifyEncrypted(testAlg, testSecret, decrypt, token); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWithCompressionMaxSize(t *testing.T) {
+	// A small token of a large payload.
+	claims := Map{"data": strings.Repeat("0", 1<<20)}
+
+	token, err := Sign(testAlg, testSecret, claims, WithCompression())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(token) > 4<<10 {
+		t.Fatalf("expected a highly compressed token but got size: %d", len(token))
+	}
+
+	if _, err = Verify(testAlg, testSecret, token); err != ErrDecompressedTooLarge {
+		t.Fatalf("expected error: ErrDecompressedTooLarge but got: %v", err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, WithMaxDecompressedSize(2<<20)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Unsupported compression algorithm.
+	token, err = SignWithHeader(testAlg, testSecret, Map{"username": "kataras"}, Map{"alg": testAlg.Name(), "typ": "JWT", "zip": "GZIP"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token); !errors.Is(err, errUnsupportedCompression) {
+		t.Fatalf("expected error: errUnsupportedCompression but got: %v", err)
+	}
+
+	// Not a DEFLATE payload.
+	token, err = SignWithHeader(testAlg, testSecret, Map{"username": "kataras"}, Map{"alg": testAlg.Name(), "typ": "JWT", "zip": "DEF"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token); !errors.Is(err, ErrTokenForm) {
+		t.Fatalf("expected error: ErrTokenForm but got: %v", err)
+	}
+}
+
+func TestInflate(t *testing.T) {
+	payload := []byte(`{"username":"kataras"}`)
+
+	compressed, err := deflate(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := inflate(compressed, int64(len(payload)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(payload, got) {
+		t.Fatalf("expected: %s but got: %s", payload, got)
+	}
+
+	if _, err = inflate(compressed, int64(len(payload)-1)); err != ErrDecompressedTooLarge {
+		t.Fatalf("expected error: ErrDecompressedTooLarge but got: %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrCookieExpired indicates that the token passed to `SetTokenCookie` is already expired.
+var ErrCookieExpired = errors.New("jwt: cookie: token is expired")
+
+// CookieOption sets a field of the cookie written by `SetTokenCookie`,
+// e.g. `WithCookiePath`.
+type CookieOption func(*http.Cookie)
+
+// WithCookiePath sets the "Path" attribute of the token cookie.
+// Defaults to "/".
+func WithCookiePath(path string) CookieOption {
+	return func(c *http.Cookie) {
+		c.Path = path
+	}
+}
+
+// WithCookieDomain sets the "Domain" attribute of the token cookie.
+func WithCookieDomain(domain string) CookieOption {
+	return func(c *http.Cookie) {
+		c.Domain = domain
+	}
+}
+
+// WithCookieSameSite sets the "SameSite" attribute of the token cookie.
+// Defaults to http.SameSiteLaxMode.
+func WithCookieSameSite(sameSite http.SameSite) CookieOption {
+	return func(c *http.Cookie) {
+		c.SameSite = sameSite
+	}
+}
+
+// WithCookieInsecure removes the "Secure" attribute of the token cookie,
+// so it is sent over plain HTTP too. Use it for local development only.
+func WithCookieInsecure() CookieOption {
+	return func(c *http.Cookie) {
+		c.Secure = false
+	}
+}
+
+// WithCookieClock sets the current time source which the "Max-Age" attribute
+// of the token cookie is computed against, instead of the `Clock` package-level variable.
+func WithCookieClock(clock func() time.Time) CookieOption {
+	return func(c *http.Cookie) {
+		if !c.Expires.IsZero() {
+			c.MaxAge = cookieMaxAge(c.Expires, clock)
+		}
+	}
+}
+
+func cookieMaxAge(expiresAt time.Time, clock func() time.Time) int {
+	return int(expiresAt.Sub(clock()) / time.Second)
+}
+
+// SetTokenCookie writes the "token" as an HttpOnly, Secure and SameSite=Lax cookie
+// of the given "name". The cookie expires along with the token:
+// its "Max-Age" and "Expires" attributes are derived from the token's "exp" claim,
+// a token without an "exp" claim results to a session cookie.
+// Read it back with the `FromCookie` extractor of the `Middleware`.
+//
+// The token is not verified, it should be the one just signed by the caller.
+// It returns an `ErrCookieExpired` error if the token is already expired.
+//
+// Example Code:
+//
+//	token, err := jwt.Sign(jwt.HS256, sharedKey, claims, jwt.MaxAge(15*time.Minute))
+//	[...]
+//	err = jwt.SetTokenCookie(w, "token", token)
+func SetTokenCookie(w http.ResponseWriter, name string, token []byte, opts ...CookieOption) error {
+	unverifiedToken, err := Decode(token)
+	if err != nil {
+		return err
+	}
+
+	var claims struct {
+		Expiry json.RawMessage `json:"exp"`
+	}
+	if err = json.Unmarshal(unverifiedToken.Payload, &claims); err != nil {
+		return errPayloadNotJSON
+	}
+
+	exp, err := parseNumericDate("exp", claims.Expiry, false)
+	if err != nil {
+		return err
+	}
+
+	cookie := &http.Cookie{
+		Name:     name,
+		Value:    string(token),
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	}
+
+	if exp > 0 {
+		cookie.Expires = time.Unix(exp, 0)
+		cookie.MaxAge = cookieMaxAge(cookie.Expires, Clock)
+	}
+
+	for _, opt := range opts {
+		opt(cookie)
+	}
+
+	// Checked after the options, see `WithCookieClock`.
+	if exp > 0 && cookie.MaxAge <= 0 {
+		return fmt.Errorf("%w: %s", ErrCookieExpired, time.Unix(exp, 0).UTC().Format(time.RFC3339))
+	}
+
+	http.SetCookie(w, cookie)
+	return nil
+}
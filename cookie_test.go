@@ -0,0 +1,132 @@
+package jwt
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSetTokenCookie(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	prevClock := Clock
+	t.Cleanup(func() {
+		Clock = prevClock
+	})
+
+	Clock = func() time.Time {
+		return now
+	}
+
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"}, MaxAge(15*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	if err = SetTokenCookie(w, "token", token); err != nil {
+		t.Fatal(err)
+	}
+
+	cookies := w.Result().Cookies()
+	if expected, got := 1, len(cookies); expected != got {
+		t.Fatalf("expected: %d cookie but got: %d", expected, got)
+	}
+
+	cookie := cookies[0]
+	if expected, got := 15*60, cookie.MaxAge; expected != got {
+		t.Fatalf("expected Max-Age: %d but got: %d", expected, got)
+	}
+
+	if expected, got := now.Add(15*time.Minute).Unix(), cookie.Expires.Unix(); expected != got {
+		t.Fatalf("expected Expires: %d but got: %d", expected, got)
+	}
+
+	if cookie.Name != "token" || cookie.Value != string(token) || cookie.Path != "/" {
+		t.Fatalf("unexpected cookie: %s", cookie)
+	}
+
+	if !cookie.HttpOnly || !cookie.Secure || cookie.SameSite != http.SameSiteLaxMode {
+		t.Fatalf("expected an HttpOnly, Secure and SameSite=Lax cookie but got: %s", cookie)
+	}
+
+	// Read it back through the FromCookie extractor.
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(cookie)
+	if got, ok := FromCookie("token")(r); !ok || got != string(token) {
+		t.Fatalf("expected the cookie token to be extracted")
+	}
+
+	// Options.
+	w = httptest.NewRecorder()
+	err = SetTokenCookie(w, "token", token, WithCookiePath("/api"), WithCookieDomain("example.com"),
+		WithCookieSameSite(http.SameSiteStrictMode), WithCookieInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cookie = w.Result().Cookies()[0]
+	if cookie.Path != "/api" || cookie.Domain != "example.com" || cookie.SameSite != http.SameSiteStrictMode || cookie.Secure {
+		t.Fatalf("unexpected cookie: %s", cookie)
+	}
+
+	// Session cookie.
+	token, err = Sign(testAlg, testSecret, Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w = httptest.NewRecorder()
+	if err = SetTokenCookie(w, "token", token); err != nil {
+		t.Fatal(err)
+	}
+
+	if cookie = w.Result().Cookies()[0]; cookie.MaxAge != 0 || !cookie.Expires.IsZero() {
+		t.Fatalf("expected a session cookie but got: %s", cookie)
+	}
+
+	// Expired.
+	token, err = Sign(testAlg, testSecret, Map{"exp": now.Add(-time.Second).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w = httptest.NewRecorder()
+	if err = SetTokenCookie(w, "token", token); !errors.Is(err, ErrCookieExpired) {
+		t.Fatalf("expected error: ErrCookieExpired but got: %v", err)
+	}
+
+	if len(w.Result().Cookies()) != 0 {
+		t.Fatalf("expected no cookie to be written")
+	}
+
+	if err = SetTokenCookie(httptest.NewRecorder(), "token", []byte("invalid")); !errors.Is(err, ErrTokenForm) {
+		t.Fatalf("expected error: ErrTokenForm but got: %v", err)
+	}
+}
+
+func TestSetTokenCookieWithClock(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	clock := func() time.Time { return now }
+
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"}, MaxAge(15*time.Minute), WithSignClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	if err = SetTokenCookie(w, "token", token, WithCookieClock(clock)); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := 15*60, w.Result().Cookies()[0].MaxAge; expected != got {
+		t.Fatalf("expected Max-Age: %d but got: %d", expected, got)
+	}
+
+	// Expired at the given time.
+	late := func() time.Time { return now.Add(time.Hour) }
+	if err = SetTokenCookie(httptest.NewRecorder(), "token", token, WithCookieClock(late)); !errors.Is(err, ErrCookieExpired) {
+		t.Fatalf("expected error: ErrCookieExpired but got: %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrUnsupportedCriticalHeader indicates that the token's "crit" header field
+// lists a header parameter which is not understood by the verifier (RFC 7515, Section 4.1.11).
+// See `WithCriticalHeaders` to register the ones the application processes.
+var ErrUnsupportedCriticalHeader = errors.New("jwt: unsupported critical header")
+
+var critHeaderKey = []byte(`"crit"`)
+
+// understoodCriticalHeaders are the header parameters processed by this package.
+var understoodCriticalHeaders = []string{"zip"}
+
+// WithCriticalHeaders is a VerifyOption which registers header parameters
+// the application understands and processes itself, so a token
+// which lists them in its "crit" header field is accepted.
+// By default, a token which lists any other parameter than "zip"
+// in its "crit" header field fails with `ErrUnsupportedCriticalHeader`.
+//
+// Example Code:
+//
+//	verifiedToken, err := jwt.Verify(jwt.RS256, publicKey, token, jwt.WithCriticalHeaders("b64"))
+func WithCriticalHeaders(names ...string) VerifyOption {
+	return func(c *verifyConfig) {
+		c.criticalHeaders = append(c.criticalHeaders, names...)
+	}
+}
+
+// validateCritHeader validates the "crit" header field, if any.
+// It must be a non-empty array of the understood header names
+// and each one of them should be present on the header.
+func validateCritHeader(headerDecoded []byte, understood []string) error {
+	if !bytes.Contains(headerDecoded, critHeaderKey) {
+		return nil
+	}
+
+	var header map[string]json.RawMessage
+	if err := json.Unmarshal(headerDecoded, &header); err != nil {
+		return fmt.Errorf("%w: header: %v", ErrTokenForm, err)
+	}
+
+	rawCrit, ok := header["crit"]
+	if !ok {
+		return nil
+	}
+
+	var crit []string
+	if err := json.Unmarshal(rawCrit, &crit); err != nil || len(crit) == 0 {
+		return fmt.Errorf("%w: crit: expected a non-empty array of strings", ErrUnsupportedCriticalHeader)
+	}
+
+	for _, name := range crit {
+		if !containsString(understoodCriticalHeaders, name) && !containsString(understood, name) {
+			return fmt.Errorf("%w: %q", ErrUnsupportedCriticalHeader, name)
+		}
+
+		if _, ok := header[name]; !ok {
+			return fmt.Errorf("%w: %q: missing from header", ErrUnsupportedCriticalHeader, name)
+		}
+	}
+
+	return nil
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,53 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVerifyCriticalHeader(t *testing.T) {
+	claims := Map{"username": "kataras"}
+
+	token, err := Sign(testAlg, testSecret, claims, WithHeader("crit", []string{"exp"}), WithHeader("exp", 1363284000))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token); !errors.Is(err, ErrUnsupportedCriticalHeader) {
+		t.Fatalf("expected error: ErrUnsupportedCriticalHeader but got: %v", err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, WithCriticalHeaders("exp")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Processed by this package.
+	token, err = Sign(testAlg, testSecret, claims, WithCompression(), WithHeader("crit", []string{"zip"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token); err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		name string
+		crit interface{}
+	}{
+		{"empty", []string{}},
+		{"not an array", "exp"},
+		{"missing from header", []string{"b64"}},
+	}
+
+	for _, tt := range tests {
+		token, err = Sign(testAlg, testSecret, claims, WithHeader("crit", tt.crit))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = Verify(testAlg, testSecret, token, WithCriticalHeaders("b64")); !errors.Is(err, ErrUnsupportedCriticalHeader) {
+			t.Fatalf("[%s] expected error: ErrUnsupportedCriticalHeader but got: %v", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+// Dump returns a human-readable view of the compact form "token":
+// its header and payload as indented JSON and its signature redacted,
+// e.g. for CLI debugging and development logs.
+//
+// WARNING: Dump performs NO verification, the output is labeled as "UNVERIFIED"
+// for that reason. See `Decode` for more.
+//
+// It returns `ErrTokenForm` when the token has not three dot-separated parts
+// and an error that wraps `ErrTokenForm` when a part is not valid base64.
+//
+// Example Output:
+//
+//	UNVERIFIED TOKEN (the signature is not checked)
+//	header:
+//	{
+//	  "alg": "HS256",
+//	  "typ": "JWT"
+//	}
+//	payload:
+//	{
+//	  "username": "kataras"
+//	}
+//	signature: [REDACTED 32 bytes]
+func Dump(token []byte) (string, error) {
+	tok, err := Decode(token)
+	if err != nil {
+		return "", err
+	}
+
+	var b bytes.Buffer
+	b.WriteString("UNVERIFIED TOKEN (the signature is not checked)\n")
+
+	b.WriteString("header:\n")
+	dumpJSON(&b, tok.Header)
+
+	b.WriteString("payload:\n")
+	dumpJSON(&b, tok.Payload)
+
+	b.WriteString("signature: [REDACTED ")
+	b.WriteString(strconv.Itoa(len(tok.Signature)))
+	b.WriteString(" bytes]")
+
+	return b.String(), nil
+}
+
+// dumpJSON writes the indented "data" or, if it's not a JSON one, as a quoted string.
+func dumpJSON(b *bytes.Buffer, data []byte) {
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, data, "", "  "); err != nil {
+		b.WriteString(strconv.Quote(string(data)))
+	} else {
+		b.Write(indented.Bytes())
+	}
+
+	b.WriteByte('\n')
+}
@@ -0,0 +1,57 @@
+package jwt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDump(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Dump(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `UNVERIFIED TOKEN (the signature is not checked)
+header:
+{
+  "alg": "HS256",
+  "typ": "JWT"
+}
+payload:
+{
+  "username": "kataras"
+}
+signature: [REDACTED 32 bytes]`
+	if expected != got {
+		t.Fatalf("expected:\n%s\nbut got:\n%s", expected, got)
+	}
+
+	_, _, signature, _ := SplitToken(token)
+	if strings.Contains(got, string(signature)) {
+		t.Fatalf("expected signature to be redacted")
+	}
+
+	// Non-JSON payload.
+	token, err = Sign(testAlg, testSecret, []byte("raw data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err = Dump(token); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(got, "payload:\n\"raw data\"\n") {
+		t.Fatalf("expected quoted raw payload but got:\n%s", got)
+	}
+
+	if _, err = Dump([]byte("a.b")); !errors.Is(err, ErrTokenForm) {
+		t.Fatalf("expected error: ErrTokenForm but got: %v", err)
+	}
+}
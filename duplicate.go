@@ -0,0 +1,112 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrDuplicateClaim indicates that the token's header or payload
+// contains the same JSON key more than once, e.g. two "alg" or "exp" fields.
+// Different parsers keep a different one of them (the first or the last),
+// which can be abused to smuggle a value through a verifier.
+// See `WithRejectDuplicateKeys`.
+var ErrDuplicateClaim = errors.New("jwt: duplicate claim")
+
+// WithRejectDuplicateKeys is a VerifyOption which makes the verification
+// to fail with an `ErrDuplicateClaim` error if the header or the payload
+// contains a repeated JSON object key, at any depth.
+// Keys are compared after their escape sequences are decoded,
+// e.g. "\u0061lg" is a duplicate of "alg".
+//
+// By default, as with the standard encoding/json package, the last one wins.
+// This option costs an extra parse of the header and the payload.
+//
+// Example Code:
+//
+//	verifiedToken, err := jwt.Verify(jwt.HS256, sharedKey, token, jwt.WithRejectDuplicateKeys())
+func WithRejectDuplicateKeys() VerifyOption {
+	return func(c *verifyConfig) {
+		c.rejectDuplicateKeys = true
+	}
+}
+
+// rejectDuplicateKeys reports an ErrDuplicateClaim error
+// if the header or the (JSON) payload contains a repeated key.
+func rejectDuplicateKeys(header, payload []byte) error {
+	if err := findDuplicateKey("header", header); err != nil {
+		return err
+	}
+
+	if trimmed := bytes.TrimSpace(payload); len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return nil // not a JSON payload, e.g. raw bytes.
+	}
+
+	return findDuplicateKey("payload", payload)
+}
+
+// findDuplicateKey walks the JSON value of "b" (the token's "part")
+// and reports the first repeated object key.
+func findDuplicateKey(part string, b []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	if err := walkDuplicateKeys(part, dec); err != nil {
+		if errors.Is(err, ErrDuplicateClaim) {
+			return err
+		}
+
+		return fmt.Errorf("%w: %s: %v", ErrTokenForm, part, err)
+	}
+
+	return nil
+}
+
+func walkDuplicateKeys(part string, dec *json.Decoder) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return nil // a scalar value.
+	}
+
+	switch delim {
+	case '{':
+		keys := make(map[string]struct{})
+		for dec.More() {
+			tok, err = dec.Token()
+			if err != nil {
+				return err
+			}
+
+			key := tok.(string) // the decoder reports a syntax error on non-string keys.
+			if _, exists := keys[key]; exists {
+				return fmt.Errorf("%w: %s: %q", ErrDuplicateClaim, part, key)
+			}
+			keys[key] = struct{}{}
+
+			if err = walkDuplicateKeys(part, dec); err != nil {
+				return err
+			}
+		}
+	case '[':
+		for dec.More() {
+			if err = walkDuplicateKeys(part, dec); err != nil {
+				return err
+			}
+		}
+	}
+
+	// The closing delimiter.
+	if _, err = dec.Token(); err != nil {
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+
+	return nil
+}
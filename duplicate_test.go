@@ -0,0 +1,56 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func testCraftToken(t *testing.T, header, payload string) []byte {
+	t.Helper()
+
+	input := append(append(Base64Encode([]byte(header)), '.'), Base64Encode([]byte(payload))...)
+	signature, err := testAlg.Sign(testSecret, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return AssembleToken(input, signature)
+}
+
+func TestWithRejectDuplicateKeys(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Unix()
+	expired := time.Now().Add(-time.Hour).Unix()
+
+	var tests = []struct {
+		name    string
+		header  string
+		payload string
+	}{
+		{"payload", `{"alg":"HS256","typ":"JWT"}`, fmt.Sprintf(`{"exp":%d,"exp":%d}`, expired, exp)},
+		{"escaped payload key", `{"alg":"HS256","typ":"JWT"}`, fmt.Sprintf(`{"exp":%d,"\u0065xp":%d}`, expired, exp)},
+		{"nested payload", `{"alg":"HS256","typ":"JWT"}`, fmt.Sprintf(`{"exp":%d,"user":[{"role":"user","role":"admin"}]}`, exp)},
+		{"header", `{"alg":"HS256","typ":"JWT","typ":"at+jwt"}`, fmt.Sprintf(`{"exp":%d}`, exp)},
+	}
+
+	for _, tt := range tests {
+		token := testCraftToken(t, tt.header, tt.payload)
+
+		// Lenient by default, the last key wins.
+		if _, err := Verify(testAlg, testSecret, token); err != nil {
+			t.Fatalf("[%s] %v", tt.name, err)
+		}
+
+		if _, err := Verify(testAlg, testSecret, token, WithRejectDuplicateKeys()); !errors.Is(err, ErrDuplicateClaim) {
+			t.Fatalf("[%s] expected error: ErrDuplicateClaim but got: %v", tt.name, err)
+		}
+	}
+
+	// Same key on different objects is not a duplicate.
+	token := testCraftToken(t, `{"alg":"HS256","typ":"JWT"}`, fmt.Sprintf(`{"exp":%d,"user":{"exp":%d}}`, exp, exp))
+	if _, err := Verify(testAlg, testSecret, token, WithRejectDuplicateKeys()); err != nil {
+		t.Fatal(err)
+	}
+
+}
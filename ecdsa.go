@@ -3,9 +3,9 @@ package jwt
 import (
 	"crypto"
 	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/x509"
-	"encoding/pem"
 	"fmt"
 	"math/big"
 )
@@ -15,6 +15,21 @@ type algECDSA struct {
 	hasher    crypto.Hash
 	keySize   int
 	curveBits int
+	curveName string // as reported by the curve's Params().Name.
+}
+
+// ecdsaSign computes the signature of the hashed "header.payload".
+// It is a variable so tests can inject a deterministic nonce
+// and assert the exact signature, the default one is randomized.
+var ecdsaSign = func(privateKey *ecdsa.PrivateKey, hashed []byte) (*big.Int, *big.Int, error) {
+	return ecdsa.Sign(rand.Reader, privateKey, hashed)
+}
+
+// validCurve reports whether the key's curve is the algorithm's one,
+// e.g. a secp256k1 key (256 bits too) can not be used by ES256.
+func (a *algECDSA) validCurve(curve elliptic.Curve) bool {
+	params := curve.Params()
+	return params.BitSize == a.curveBits && params.Name == a.curveName
 }
 
 func (a *algECDSA) Parse(private, public []byte) (privateKey PrivateKey, publicKey PublicKey, err error) {
@@ -47,24 +62,21 @@ func (a *algECDSA) Sign(key PrivateKey, headerAndPayload []byte) ([]byte, error)
 		return nil, ErrInvalidKey
 	}
 
-	h := a.hasher.New()
-	// header.payload
-	_, err := h.Write(headerAndPayload)
-	if err != nil {
-		return nil, err
+	// Check the curve before signing, e.g. a P-384 key can not be used by ES256.
+	if !a.validCurve(privateKey.Curve) {
+		return nil, ErrInvalidKey
 	}
+	curveBits := a.curveBits
 
-	hashed := h.Sum(nil)
-	r, s, err := ecdsa.Sign(rand.Reader, privateKey, hashed)
+	// header.payload
+	hashed := sumHash(a.hasher, headerAndPayload)
+	r, s, err := ecdsaSign(privateKey, hashed)
 	if err != nil {
 		return nil, err
 	}
 
-	curveBits := privateKey.Curve.Params().BitSize
-	if a.curveBits != curveBits {
-		return nil, ErrInvalidKey
-	}
-
+	// RFC 7518, section 3.4: the signature is the fixed-width big-endian
+	// concatenation of R and S (not ASN.1 DER).
 	keyBytes := curveBits / 8
 	if curveBits%8 > 0 {
 		keyBytes++
@@ -92,6 +104,10 @@ func (a *algECDSA) Verify(key PublicKey, headerAndPayload []byte, signature []by
 		}
 	}
 
+	if !a.validCurve(publicKey.Curve) {
+		return ErrInvalidKey
+	}
+
 	if len(signature) != 2*a.keySize {
 		return ErrTokenSignature
 	}
@@ -99,14 +115,8 @@ func (a *algECDSA) Verify(key PublicKey, headerAndPayload []byte, signature []by
 	r := big.NewInt(0).SetBytes(signature[:a.keySize])
 	s := big.NewInt(0).SetBytes(signature[a.keySize:])
 
-	h := a.hasher.New()
 	// header.payload
-	_, err := h.Write(headerAndPayload)
-	if err != nil {
-		return err
-	}
-
-	hashed := h.Sum(nil)
+	hashed := sumHash(a.hasher, headerAndPayload)
 	if !ecdsa.Verify(publicKey, hashed, r, s) {
 		return ErrTokenSignature
 	}
@@ -116,6 +126,22 @@ func (a *algECDSA) Verify(key PublicKey, headerAndPayload []byte, signature []by
 
 // Key Helpers.
 
+// GenerateECDSAKeys generates a random ECDSA private key of the given curve,
+// e.g. elliptic.P256() for ES256, elliptic.P384() for ES384 and elliptic.P521() for ES512.
+// If "curve" is nil then P-256 is used.
+// The private key can be passed to the ES algorithms for signing
+// and its `PublicKey` field (pointer) for verification.
+//
+// Useful for development and tests, production keys should be
+// loaded through the `LoadPrivateKeyECDSA` and `LoadPublicKeyECDSA` functions.
+func GenerateECDSAKeys(curve elliptic.Curve) (*ecdsa.PrivateKey, error) {
+	if curve == nil {
+		curve = elliptic.P256()
+	}
+
+	return ecdsa.GenerateKey(curve, rand.Reader)
+}
+
 // MustLoadECDSA accepts private and public PEM filenames
 // and returns a pair of private and public ECDSA keys.
 // Pass the returned private key to the `Token` (signing) function
@@ -172,30 +198,50 @@ func LoadPublicKeyECDSA(filename string) (*ecdsa.PublicKey, error) {
 
 // ParsePrivateKeyECDSA decodes and parses the
 // PEM-encoded ECDSA private key's raw contents.
+// Both SEC 1 ("EC PRIVATE KEY") and PKCS #8 ("PRIVATE KEY") encodings are supported,
+// the PEM armor is optional (raw DER-encoded keys are accepted too).
 // Pass the result to the `Token` (signing) function.
 func ParsePrivateKeyECDSA(key []byte) (*ecdsa.PrivateKey, error) {
-	block, _ := pem.Decode(key)
-	if block == nil {
-		return nil, fmt.Errorf("private key: malformed or missing PEM format (ECDSA)")
+	der, isPEM := decodePEM(key)
+
+	privateKey, err := x509.ParseECPrivateKey(der)
+	if err != nil {
+		if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+			pKey, ok := key.(*ecdsa.PrivateKey)
+			if !ok {
+				return nil, fmt.Errorf("private key: expected a type of *ecdsa.PrivateKey")
+			}
+
+			privateKey = pKey
+		} else {
+			if !isPEM {
+				return nil, fmt.Errorf("private key: malformed or missing PEM format (ECDSA)")
+			}
+
+			return nil, err
+		}
 	}
 
-	return x509.ParseECPrivateKey(block.Bytes)
+	return privateKey, nil
 }
 
 // ParsePublicKeyECDSA decodes and parses the
 // PEM-encoded ECDSA public key's raw contents.
+// PKIX ("PUBLIC KEY") and certificate encodings are supported,
+// the PEM armor is optional (raw DER-encoded keys are accepted too).
 // Pass the result to the `Verify` function.
 func ParsePublicKeyECDSA(key []byte) (*ecdsa.PublicKey, error) {
-	block, _ := pem.Decode(key)
-	if block == nil {
-		return nil, fmt.Errorf("public key: malformed or missing PEM format (ECDSA)")
-	}
+	der, isPEM := decodePEM(key)
 
-	parsedKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	parsedKey, err := x509.ParsePKIXPublicKey(der)
 	if err != nil {
-		if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+		if cert, err := x509.ParseCertificate(der); err == nil {
 			parsedKey = cert.PublicKey
 		} else {
+			if !isPEM {
+				return nil, fmt.Errorf("public key: malformed or missing PEM format (ECDSA)")
+			}
+
 			return nil, err
 		}
 	}
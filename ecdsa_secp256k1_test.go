@@ -0,0 +1,176 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// testSecp256k1 is a minimal, NOT constant-time, affine implementation
+// of the secp256k1 curve (y² = x³ + 7), used to test the ES256K algorithm
+// without a third-party dependency. The elliptic.CurveParams methods
+// can not be used as they assume the a = -3 curves.
+type testSecp256k1 struct {
+	params *elliptic.CurveParams
+}
+
+func newTestSecp256k1() *testSecp256k1 {
+	hex := func(s string) *big.Int {
+		n, _ := new(big.Int).SetString(s, 16)
+		return n
+	}
+
+	return &testSecp256k1{params: &elliptic.CurveParams{
+		P:       hex("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC2F"),
+		N:       hex("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141"),
+		B:       big.NewInt(7),
+		Gx:      hex("79BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798"),
+		Gy:      hex("483ADA7726A3C4655DA4FBFC0E1108A8FD17B448A68554199C47D08FFB10D4B8"),
+		BitSize: 256,
+		Name:    "secp256k1",
+	}}
+}
+
+func (c *testSecp256k1) Params() *elliptic.CurveParams { return c.params }
+
+func (c *testSecp256k1) IsOnCurve(x, y *big.Int) bool {
+	p := c.params.P
+	y2 := new(big.Int).Exp(y, big.NewInt(2), p)
+	x3 := new(big.Int).Exp(x, big.NewInt(3), p)
+	x3.Add(x3, c.params.B).Mod(x3, p)
+	return y2.Cmp(x3) == 0
+}
+
+func (c *testSecp256k1) Add(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	p := c.params.P
+	if x1.Sign() == 0 && y1.Sign() == 0 {
+		return new(big.Int).Set(x2), new(big.Int).Set(y2)
+	}
+	if x2.Sign() == 0 && y2.Sign() == 0 {
+		return new(big.Int).Set(x1), new(big.Int).Set(y1)
+	}
+
+	if x1.Cmp(x2) == 0 {
+		if sum := new(big.Int).Add(y1, y2); sum.Mod(sum, p).Sign() == 0 {
+			return new(big.Int), new(big.Int) // point at infinity.
+		}
+		return c.Double(x1, y1)
+	}
+
+	// λ = (y2 - y1) / (x2 - x1)
+	num := new(big.Int).Sub(y2, y1)
+	den := new(big.Int).Sub(x2, x1)
+	den.Mod(den, p).ModInverse(den, p)
+	l := num.Mul(num, den).Mod(num, p)
+
+	return c.affine(l, x1, y1, x2)
+}
+
+func (c *testSecp256k1) Double(x1, y1 *big.Int) (*big.Int, *big.Int) {
+	p := c.params.P
+	if y1.Sign() == 0 {
+		return new(big.Int), new(big.Int)
+	}
+
+	// λ = 3x² / 2y
+	num := new(big.Int).Mul(x1, x1)
+	num.Mul(num, big.NewInt(3))
+	den := new(big.Int).Lsh(y1, 1)
+	den.Mod(den, p).ModInverse(den, p)
+	l := num.Mul(num, den).Mod(num, p)
+
+	return c.affine(l, x1, y1, x1)
+}
+
+func (c *testSecp256k1) affine(l, x1, y1, x2 *big.Int) (*big.Int, *big.Int) {
+	p := c.params.P
+	x3 := new(big.Int).Mul(l, l)
+	x3.Sub(x3, x1).Sub(x3, x2).Mod(x3, p)
+
+	y3 := new(big.Int).Sub(x1, x3)
+	y3.Mul(y3, l).Sub(y3, y1).Mod(y3, p)
+	return x3, y3
+}
+
+func (c *testSecp256k1) ScalarMult(x1, y1 *big.Int, k []byte) (*big.Int, *big.Int) {
+	x, y := new(big.Int), new(big.Int)
+	for _, b := range k {
+		for bit := 7; bit >= 0; bit-- {
+			x, y = c.Double(x, y)
+			if b>>uint(bit)&1 == 1 {
+				x, y = c.Add(x, y, x1, y1)
+			}
+		}
+	}
+
+	return x, y
+}
+
+func (c *testSecp256k1) ScalarBaseMult(k []byte) (*big.Int, *big.Int) {
+	return c.ScalarMult(c.params.Gx, c.params.Gy, k)
+}
+
+func TestEncodeDecodeTokenES256K(t *testing.T) {
+	curve := newTestSecp256k1()
+	if !curve.IsOnCurve(curve.params.Gx, curve.params.Gy) {
+		t.Fatalf("expected the generator point to be on the curve")
+	}
+
+	privateKey, err := GenerateECDSAKeys(curve)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testEncodeDecodeToken(t, ES256K, privateKey, &privateKey.PublicKey, nil)
+
+	header, err := PeekHeader(mustSignTestToken(t, ES256K, privateKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "ES256K", header["alg"]; expected != got {
+		t.Fatalf("expected alg header: %q but got: %v", expected, got)
+	}
+
+	if alg, ok := GetAlg("ES256K"); !ok || alg != ES256K {
+		t.Fatalf("expected ES256K to be registered")
+	}
+
+	// Same size, different curve.
+	p256PrivateKey, err := GenerateECDSAKeys(elliptic.P256())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Sign(ES256K, p256PrivateKey, Map{"username": "kataras"}); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected P-256 key to be rejected by ES256K but got: %v", err)
+	}
+
+	if _, err = Sign(ES256, privateKey, Map{"username": "kataras"}); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected secp256k1 key to be rejected by ES256 but got: %v", err)
+	}
+
+	token := mustSignTestToken(t, ES256, p256PrivateKey)
+	if _, err = Verify(ES256K, &p256PrivateKey.PublicKey, token); !errors.Is(err, ErrTokenAlg) {
+		t.Fatalf("expected error: ErrTokenAlg but got: %v", err)
+	}
+
+	if _, err = VerifyWithHeaderValidator(ES256K, &p256PrivateKey.PublicKey, token, func(string, []byte) (Alg, PublicKey, InjectFunc, error) {
+		return nil, nil, nil, nil
+	}); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected error: ErrInvalidKey but got: %v", err)
+	}
+}
+
+func mustSignTestToken(t *testing.T, alg Alg, key *ecdsa.PrivateKey) []byte {
+	t.Helper()
+
+	token, err := Sign(alg, key, Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token
+}
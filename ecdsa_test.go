@@ -1,6 +1,16 @@
 package jwt
 
 import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
 	"testing"
 )
 
@@ -35,3 +45,251 @@ func TestMustLoadECDSA(t *testing.T) {
 		MustLoadECDSA("./invalid.pem", "./invalid.pem")
 	})
 }
+
+func TestParseKeysECDSAEncodings(t *testing.T) {
+	privateKey, err := LoadPrivateKeyECDSA("./_testfiles/ecdsa_private_key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sec1, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var privateKeys = map[string][]byte{
+		"SEC1":      pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: sec1}),
+		"PKCS8":     pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}),
+		"SEC1 DER":  sec1,
+		"PKCS8 DER": pkcs8,
+	}
+
+	for name, key := range privateKeys {
+		got, err := ParsePrivateKeyECDSA(key)
+		if err != nil {
+			t.Fatalf("[%s] %v", name, err)
+		}
+
+		if !got.Equal(privateKey) {
+			t.Fatalf("[%s] private key mismatch", name)
+		}
+	}
+
+	pkix, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var publicKeys = map[string][]byte{
+		"PKIX":     pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pkix}),
+		"PKIX DER": pkix,
+	}
+
+	for name, key := range publicKeys {
+		got, err := ParsePublicKeyECDSA(key)
+		if err != nil {
+			t.Fatalf("[%s] %v", name, err)
+		}
+
+		if !got.Equal(&privateKey.PublicKey) {
+			t.Fatalf("[%s] public key mismatch", name)
+		}
+	}
+
+	// Invalid keys.
+	rsaPrivateKey, err := ReadFile("./_testfiles/rsa_private_key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rsaPublicKey, err := ReadFile("./_testfiles/rsa_public_key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range [][]byte{nil, []byte("invalid"), rsaPrivateKey} {
+		if _, err = ParsePrivateKeyECDSA(key); err == nil {
+			t.Fatalf("expected error on private key: %q", key)
+		}
+	}
+
+	for _, key := range [][]byte{nil, []byte("invalid"), rsaPublicKey} {
+		if _, err = ParsePublicKeyECDSA(key); err == nil {
+			t.Fatalf("expected error on public key: %q", key)
+		}
+	}
+}
+
+func TestGenerateECDSAKeys(t *testing.T) {
+	var tests = []struct {
+		alg   Alg
+		curve elliptic.Curve
+	}{
+		{ES256, nil},
+		{ES256, elliptic.P256()},
+		{ES384, elliptic.P384()},
+		{ES512, elliptic.P521()},
+	}
+
+	for _, tt := range tests {
+		privateKey, err := GenerateECDSAKeys(tt.curve)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		testEncodeDecodeToken(t, tt.alg, privateKey, &privateKey.PublicKey, nil)
+	}
+}
+
+func TestECDSASignatureEncoding(t *testing.T) {
+	var tests = []struct {
+		alg     Alg
+		curve   elliptic.Curve
+		hasher  crypto.Hash
+		sigSize int
+	}{
+		{ES256, elliptic.P256(), crypto.SHA256, 64},
+		{ES384, elliptic.P384(), crypto.SHA384, 96},
+		{ES512, elliptic.P521(), crypto.SHA512, 132},
+	}
+
+	for _, tt := range tests {
+		privateKey, err := GenerateECDSAKeys(tt.curve)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		token, err := Sign(tt.alg, privateKey, Map{"username": "kataras"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		parts := bytes.Split(token, sep)
+		signature, err := Base64Decode(parts[2])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// RFC 7518, section 3.4: R||S, not ASN.1 DER.
+		if got := len(signature); got != tt.sigSize {
+			t.Fatalf("[%s] expected signature size: %d but got: %d", tt.alg.Name(), tt.sigSize, got)
+		}
+
+		// Verify the R||S pair independently of the algorithm's Verify method,
+		// the way any other RFC 7518 compatible library would do.
+		h := tt.hasher.New()
+		h.Write(joinParts(parts[0], parts[1]))
+		half := tt.sigSize / 2
+		r := new(big.Int).SetBytes(signature[:half])
+		s := new(big.Int).SetBytes(signature[half:])
+		if !ecdsa.Verify(&privateKey.PublicKey, h.Sum(nil), r, s) {
+			t.Fatalf("[%s] expected R||S signature to be valid", tt.alg.Name())
+		}
+
+		// ASN.1 DER signatures must be rejected.
+		der, err := ecdsa.SignASN1(rand.Reader, privateKey, h.Sum(nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = tt.alg.Verify(&privateKey.PublicKey, joinParts(parts[0], parts[1]), der); err != ErrTokenSignature {
+			t.Fatalf("[%s] expected DER signature to fail with ErrTokenSignature but got: %v", tt.alg.Name(), err)
+		}
+	}
+
+	// A key of a different curve is rejected before signing.
+	privateKey, err := GenerateECDSAKeys(elliptic.P384())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Sign(ES256, privateKey, Map{"username": "kataras"}); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected error: ErrInvalidKey but got: %v", err)
+	}
+}
+
+// withTestECDSANonce makes the ECDSA algorithms to sign with
+// the nonce ("k") read from the given reader, so the signature is deterministic.
+func withTestECDSANonce(t *testing.T, nonce io.Reader) {
+	t.Helper()
+
+	prevSign := ecdsaSign
+	t.Cleanup(func() {
+		ecdsaSign = prevSign
+	})
+
+	ecdsaSign = func(privateKey *ecdsa.PrivateKey, hashed []byte) (*big.Int, *big.Int, error) {
+		params := privateKey.Curve.Params()
+
+		b := make([]byte, (params.N.BitLen()+7)/8)
+		if _, err := io.ReadFull(nonce, b); err != nil {
+			return nil, nil, err
+		}
+		k := new(big.Int).SetBytes(b)
+
+		// e is the leftmost N bits of the hash (SEC 1, section 4.1.3).
+		e := new(big.Int).SetBytes(hashed)
+		if excess := len(hashed)*8 - params.N.BitLen(); excess > 0 {
+			e.Rsh(e, uint(excess))
+		}
+
+		// r = (kG).x mod N, s = k⁻¹(e + rd) mod N
+		r, _ := privateKey.Curve.ScalarBaseMult(k.Bytes())
+		r.Mod(r, params.N)
+
+		s := new(big.Int).Mul(r, privateKey.D)
+		s.Add(s, e).Mul(s, new(big.Int).ModInverse(k, params.N)).Mod(s, params.N)
+		return r, s, nil
+	}
+}
+
+func TestECDSADeterministicSignature(t *testing.T) {
+	hex := func(s string) []byte {
+		b, ok := new(big.Int).SetString(s, 16)
+		if !ok {
+			t.Fatalf("invalid hex: %s", s)
+		}
+		return b.FillBytes(make([]byte, 32))
+	}
+
+	// RFC 6979, Appendix A.2.5: ECDSA, 256 Bits (Prime Field), SHA-256, message "sample".
+	d := new(big.Int).SetBytes(hex("C9AFA9D845BA75166B5C215767B1D6934E50C3DB36E89B127B8A622B120F6721"))
+	privateKey := &ecdsa.PrivateKey{D: d}
+	privateKey.Curve = elliptic.P256()
+	privateKey.X, privateKey.Y = privateKey.Curve.ScalarBaseMult(d.Bytes())
+
+	k := hex("A6E3C57DD01ABE90086538398355DD4C3B17AA873382B0F24D6129493D8AAD60")
+	expected := append(
+		hex("EFD48B2AACB6A8FD1140DD9CD45E81D69D2C877B56AAF991C34D0EA84EAF3716"),
+		hex("F7CB1C942D657C41D436C7A1B6E29F65F3E900DBB9AFF4064DC4AB2F843ACDA8")...)
+
+	withTestECDSANonce(t, bytes.NewReader(k))
+
+	signature, err := ES256.Sign(privateKey, []byte("sample"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(signature, expected) {
+		t.Fatalf("expected signature:\n%x\nbut got:\n%x", expected, signature)
+	}
+
+	if err = ES256.Verify(&privateKey.PublicKey, []byte("sample"), signature); err != nil {
+		t.Fatal(err)
+	}
+
+	// The same nonce results to the same token.
+	withTestECDSANonce(t, bytes.NewReader(bytes.Repeat(k, 2)))
+
+	expectedToken, err := Sign(ES256, privateKey, Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testEncodeDecodeToken(t, ES256, privateKey, &privateKey.PublicKey, expectedToken)
+}
@@ -50,11 +50,11 @@ func (a *algEdDSA) Name() string {
 func (a *algEdDSA) Sign(key PrivateKey, headerAndPayload []byte) ([]byte, error) {
 	privateKey, ok := key.(ed25519.PrivateKey)
 	if !ok {
-		return nil, ErrInvalidKey
+		return nil, fmt.Errorf("expected an ed25519.PrivateKey key: %w", ErrInvalidKey)
 	}
 
 	if len(privateKey) != ed25519.PrivateKeySize {
-		return nil, ErrInvalidKey
+		return nil, fmt.Errorf("ed25519 private key: bad length: %d: %w", len(privateKey), ErrInvalidKey)
 	}
 
 	return ed25519.Sign(privateKey, []byte(headerAndPayload)), nil
@@ -63,15 +63,21 @@ func (a *algEdDSA) Sign(key PrivateKey, headerAndPayload []byte) ([]byte, error)
 func (a *algEdDSA) Verify(key PublicKey, headerAndPayload []byte, signature []byte) error {
 	publicKey, ok := key.(ed25519.PublicKey)
 	if !ok {
-		if privateKey, ok := key.(ed25519.PrivateKey); ok {
-			publicKey = privateKey.Public().(ed25519.PublicKey)
-		} else {
-			return ErrInvalidKey
+		privateKey, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return fmt.Errorf("expected an ed25519.PublicKey key: %w", ErrInvalidKey)
 		}
+
+		// The public key is embedded to the private one, a shorter one would panic.
+		if len(privateKey) != ed25519.PrivateKeySize {
+			return fmt.Errorf("ed25519 private key: bad length: %d: %w", len(privateKey), ErrInvalidKey)
+		}
+
+		publicKey = privateKey.Public().(ed25519.PublicKey)
 	}
 
 	if len(publicKey) != ed25519.PublicKeySize {
-		return ErrInvalidKey
+		return fmt.Errorf("ed25519 public key: bad length: %d: %w", len(publicKey), ErrInvalidKey)
 	}
 
 	if !ed25519.Verify(publicKey, headerAndPayload, signature) {
@@ -193,6 +199,18 @@ func ParsePublicKeyEdDSA(key []byte) (ed25519.PublicKey, error) {
 	return publicKey, nil
 }
 
+// GenerateEdDSAKeys generates a random ed25519 private key.
+// The private key can be passed to the EdDSA algorithm for signing
+// and its public key, `privateKey.Public()`, for verification.
+// See `GenerateEdDSA` to generate PEM-encoded keys instead.
+//
+// Useful for development and tests, production keys should be
+// loaded through the `LoadPrivateKeyEdDSA` and `LoadPublicKeyEdDSA` functions.
+func GenerateEdDSAKeys() (ed25519.PrivateKey, error) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	return privateKey, err
+}
+
 // GenerateEdDSA generates random public and private keys for ed25519.
 func GenerateEdDSA() (ed25519.PublicKey, ed25519.PrivateKey, error) {
 	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
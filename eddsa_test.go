@@ -1,6 +1,9 @@
 package jwt
 
 import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
 	"testing"
 )
 
@@ -36,3 +39,65 @@ func TestMustLoadEdDSA(t *testing.T) {
 		MustLoadEdDSA("./invalid.pem", "./invalid.pem")
 	})
 }
+
+func TestGenerateEdDSAKeys(t *testing.T) {
+	privateKey, err := GenerateEdDSAKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testEncodeDecodeToken(t, EdDSA, privateKey, privateKey.Public(), nil)
+}
+
+func TestEdDSAKeyTypes(t *testing.T) {
+	privateKey, err := GenerateEdDSAKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey := privateKey.Public().(ed25519.PublicKey)
+
+	token, err := Sign(EdDSA, privateKey, Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tok, err := Decode(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := ed25519.SignatureSize, len(tok.Signature); expected != got {
+		t.Fatalf("expected signature size: %d but got: %d", expected, got)
+	}
+
+	// The public key embedded to the private one is usable for verification.
+	for _, key := range []PublicKey{publicKey, privateKey} {
+		if _, err = Verify(EdDSA, key, token); err != nil {
+			t.Fatalf("%T: %v", key, err)
+		}
+	}
+
+	rsaPrivateKey, rsaPublicKey := MustLoadRSA("./_testfiles/rsa_private_key.pem", "./_testfiles/rsa_public_key.pem")
+
+	for _, key := range []PrivateKey{rsaPrivateKey, &privateKey, []byte(privateKey), privateKey[:32], nil} {
+		if _, err = Sign(EdDSA, key, Map{"username": "kataras"}); !errors.Is(err, ErrInvalidKey) {
+			t.Fatalf("%T: expected error: ErrInvalidKey but got: %v", key, err)
+		}
+	}
+
+	for _, key := range []PublicKey{rsaPublicKey, &publicKey, []byte(publicKey), publicKey[:16], privateKey[:40], (*rsa.PublicKey)(nil)} {
+		if _, err = Verify(EdDSA, key, token); !errors.Is(err, ErrInvalidKey) {
+			t.Fatalf("%T: expected error: ErrInvalidKey but got: %v", key, err)
+		}
+	}
+
+	// A valid key of another pair.
+	otherPrivateKey, err := GenerateEdDSAKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(EdDSA, otherPrivateKey.Public(), token); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected error: ErrInvalidSignature but got: %v", err)
+	}
+}
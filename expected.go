@@ -87,3 +87,114 @@ func (e Expected) ValidateToken(token []byte, c Claims, err error) error {
 
 	return nil
 }
+
+// ErrInvalidAudience indicates that the token's "aud" claim
+// does not contain the expected audience, see `WithExpectedAudience`.
+var ErrInvalidAudience = errors.New("jwt: invalid audience")
+
+// WithExpectedAudience is a TokenValidator which makes sure that
+// the token's "aud" claim contains the given "aud" value.
+// Matching is case-sensitive. A token without an "aud" claim is invalid.
+//
+// It returns an ErrInvalidAudience error on validation failure.
+//
+// Usage:
+//
+//	verifiedToken, err := Verify(..., WithExpectedAudience("my-api"))
+func WithExpectedAudience(aud string) TokenValidatorFunc {
+	return func(_ []byte, c Claims, err error) error {
+		if err != nil {
+			return err
+		}
+
+		for _, v := range c.Audience {
+			if v == aud {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("%w: %q", ErrInvalidAudience, aud)
+	}
+}
+
+// ErrInvalidIssuer indicates that the token's "iss" claim
+// does not match the expected issuer, see `WithExpectedIssuer`.
+var ErrInvalidIssuer = errors.New("jwt: invalid issuer")
+
+// WithExpectedIssuer is a TokenValidator which makes sure that
+// the token's "iss" claim exactly matches the given "iss" value.
+// Like all token validators, it runs after a successful signature verification.
+//
+// It returns an ErrInvalidIssuer error on validation failure.
+//
+// Usage:
+//
+//	verifiedToken, err := Verify(..., WithExpectedIssuer("my-auth-service"))
+func WithExpectedIssuer(iss string) TokenValidatorFunc {
+	return func(_ []byte, c Claims, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if c.Issuer != iss {
+			return fmt.Errorf("%w: %q", ErrInvalidIssuer, c.Issuer)
+		}
+
+		return nil
+	}
+}
+
+// WithAllowedIssuers is a TokenValidator which makes sure that
+// the token's "iss" claim exactly matches one of the given "issuers",
+// e.g. on an API gateway which accepts tokens of several trusted issuers.
+// Combine it with a KeySet (or Keys) header validator to select
+// the verification key of each issuer by the token's "kid" header.
+//
+// It returns an ErrInvalidIssuer error on validation failure.
+//
+// Usage:
+//
+//	verifiedToken, err := VerifyWithHeaderValidator(nil, nil, token, keySet.ValidateHeader,
+//	  WithAllowedIssuers("https://issuer-a.example.com", "https://issuer-b.example.com"))
+func WithAllowedIssuers(issuers ...string) TokenValidatorFunc {
+	return func(_ []byte, c Claims, err error) error {
+		if err != nil {
+			return err
+		}
+
+		for _, iss := range issuers {
+			if c.Issuer == iss {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("%w: %q", ErrInvalidIssuer, c.Issuer)
+	}
+}
+
+// ErrInvalidSubject indicates that the token's "sub" claim
+// does not match the expected subject, see `WithExpectedSubject`.
+var ErrInvalidSubject = errors.New("jwt: invalid subject")
+
+// WithExpectedSubject is a TokenValidator which makes sure that
+// the token's "sub" claim exactly matches the given "sub" value.
+// Do not pass it to skip the check entirely.
+//
+// It returns an ErrInvalidSubject error on validation failure.
+//
+// Usage:
+//
+//	verifiedToken, err := Verify(..., WithExpectedSubject(userID))
+func WithExpectedSubject(sub string) TokenValidatorFunc {
+	return func(_ []byte, c Claims, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if c.Subject != sub {
+			return fmt.Errorf("%w: %q", ErrInvalidSubject, c.Subject)
+		}
+
+		return nil
+	}
+}
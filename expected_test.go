@@ -147,3 +147,151 @@ func TestExpected(t *testing.T) {
 		t.Fatalf("expected error: %v but got: %v", expectedErr, gotErr)
 	}
 }
+
+func TestWithExpectedAudience(t *testing.T) {
+	var tests = []struct {
+		audience Audience
+		ok       bool
+	}{
+		{Audience{"api"}, true},
+		{Audience{"web", "api"}, true},
+		{Audience{"web", "mobile"}, false},
+		{Audience{"API"}, false}, // case-sensitive.
+		{nil, false},             // missing "aud".
+		{Audience{}, false},
+	}
+
+	validator := WithExpectedAudience("api")
+	for i, tt := range tests {
+		err := validator.ValidateToken(nil, Claims{Audience: tt.audience}, nil)
+		if tt.ok && err != nil {
+			t.Fatalf("[%d] expected to pass but got error: %v", i, err)
+		}
+
+		if !tt.ok && !errors.Is(err, ErrInvalidAudience) {
+			t.Fatalf("[%d] expected error: ErrInvalidAudience but got: %v", i, err)
+		}
+	}
+
+	// Test respect previous error.
+	if err := validator.ValidateToken(nil, Claims{}, ErrExpired); err != ErrExpired {
+		t.Fatalf("expected to respect previous error 'ErrExpired' but got: %v", err)
+	}
+
+	// Test through Verify.
+	token, err := Sign(testAlg, testSecret, Map{"foo": "bar"}, Claims{Audience: Audience{"web", "api"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, validator); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, WithExpectedAudience("mobile")); !errors.Is(err, ErrInvalidAudience) {
+		t.Fatalf("expected error: ErrInvalidAudience but got: %v", err)
+	}
+}
+
+func TestWithExpectedIssuer(t *testing.T) {
+	validator := WithExpectedIssuer("my-iss")
+
+	if err := validator.ValidateToken(nil, Claims{Issuer: "my-iss"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, iss := range []string{"", "other-iss", "MY-ISS", "my-iss "} {
+		if err := validator.ValidateToken(nil, Claims{Issuer: iss}, nil); !errors.Is(err, ErrInvalidIssuer) {
+			t.Fatalf("[%q] expected error: ErrInvalidIssuer but got: %v", iss, err)
+		}
+	}
+
+	token, err := Sign(testAlg, testSecret, Claims{Issuer: "other-iss"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, validator); !errors.Is(err, ErrInvalidIssuer) {
+		t.Fatalf("expected error: ErrInvalidIssuer but got: %v", err)
+	}
+
+	// The signature is verified first.
+	if _, err = Verify(testAlg, []byte("othersecret"), token, validator); !errors.Is(err, ErrTokenSignature) {
+		t.Fatalf("expected error: ErrTokenSignature but got: %v", err)
+	}
+}
+
+func TestWithAllowedIssuers(t *testing.T) {
+	validator := WithAllowedIssuers("issuer-a", "issuer-b")
+
+	for _, iss := range []string{"issuer-a", "issuer-b"} {
+		if err := validator.ValidateToken(nil, Claims{Issuer: iss}, nil); err != nil {
+			t.Fatalf("[%q] %v", iss, err)
+		}
+	}
+
+	for _, iss := range []string{"", "issuer-c", "ISSUER-A", "issuer-a "} {
+		if err := validator.ValidateToken(nil, Claims{Issuer: iss}, nil); !errors.Is(err, ErrInvalidIssuer) {
+			t.Fatalf("[%q] expected error: ErrInvalidIssuer but got: %v", iss, err)
+		}
+	}
+
+	if err := WithAllowedIssuers().ValidateToken(nil, Claims{Issuer: "issuer-a"}, nil); !errors.Is(err, ErrInvalidIssuer) {
+		t.Fatalf("expected error: ErrInvalidIssuer but got: %v", err)
+	}
+
+	// Each issuer signs with its own key, selected by the "kid" header.
+	keys := make(Keys)
+	keys.Register(HS256, "issuer-a", []byte("secret-a"), []byte("secret-a"))
+	keys.Register(HS256, "issuer-b", []byte("secret-b"), []byte("secret-b"))
+	keys.Register(HS256, "issuer-c", []byte("secret-c"), []byte("secret-c"))
+
+	for _, iss := range []string{"issuer-a", "issuer-b"} {
+		token, err := keys.SignToken(iss, Claims{Issuer: iss})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		verifiedToken, err := VerifyWithHeaderValidator(nil, nil, token, keys.ValidateHeader, validator)
+		if err != nil {
+			t.Fatalf("[%q] %v", iss, err)
+		}
+
+		if expected, got := iss, verifiedToken.StandardClaims.Issuer; expected != got {
+			t.Fatalf("expected issuer: %q but got: %q", expected, got)
+		}
+	}
+
+	token, err := keys.SignToken("issuer-c", Claims{Issuer: "issuer-c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = VerifyWithHeaderValidator(nil, nil, token, keys.ValidateHeader, validator); !errors.Is(err, ErrInvalidIssuer) {
+		t.Fatalf("expected error: ErrInvalidIssuer but got: %v", err)
+	}
+}
+
+func TestWithExpectedSubject(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Claims{Subject: "user-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, WithExpectedSubject("user-1")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, WithExpectedSubject("user-2")); !errors.Is(err, ErrInvalidSubject) {
+		t.Fatalf("expected error: ErrInvalidSubject but got: %v", err)
+	}
+
+	// Without the option the subject is not checked at all.
+	if _, err = Verify(testAlg, testSecret, token); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = WithExpectedSubject("user-1").ValidateToken(nil, Claims{}, nil); !errors.Is(err, ErrInvalidSubject) {
+		t.Fatalf("expected error: ErrInvalidSubject but got: %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package jwt
+
+import "encoding/json"
+
+// claim returns the decoded value of the "name" claim.
+// Numbers are decoded as json.Number values.
+func (t *VerifiedToken) claim(name string) (interface{}, bool) {
+	var claims Map
+	if err := defaultUnmarshal(t.Payload, &claims); err != nil {
+		return nil, false
+	}
+
+	v, ok := claims[name]
+	return v, ok
+}
+
+// GetString returns the value of the "name" string claim.
+// It reports false if the claim is missing or it's not a string.
+//
+// Example Code:
+//
+//	username, ok := verifiedToken.GetString("username")
+func (t *VerifiedToken) GetString(name string) (string, bool) {
+	v, _ := t.claim(name)
+	s, ok := v.(string)
+	return s, ok
+}
+
+// GetInt64 returns the value of the "name" integer claim.
+// It reports false if the claim is missing or it's not an integer number,
+// e.g. 1.5 and "1" values are rejected.
+func (t *VerifiedToken) GetInt64(name string) (int64, bool) {
+	v, _ := t.claim(name)
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, false
+	}
+
+	i, err := n.Int64()
+	if err != nil {
+		return 0, false
+	}
+
+	return i, true
+}
+
+// GetFloat64 returns the value of the "name" number claim.
+// It reports false if the claim is missing or it's not a number.
+func (t *VerifiedToken) GetFloat64(name string) (float64, bool) {
+	v, _ := t.claim(name)
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, false
+	}
+
+	f, err := n.Float64()
+	if err != nil {
+		return 0, false
+	}
+
+	return f, true
+}
+
+// GetBool returns the value of the "name" boolean claim.
+// It reports false if the claim is missing or it's not a boolean.
+func (t *VerifiedToken) GetBool(name string) (bool, bool) {
+	v, _ := t.claim(name)
+	b, ok := v.(bool)
+	return b, ok
+}
+
+// GetStringSlice returns the value of the "name" claim of an array of strings.
+// It reports false if the claim is missing, it's not an array
+// or any of its elements is not a string.
+func (t *VerifiedToken) GetStringSlice(name string) ([]string, bool) {
+	v, _ := t.claim(name)
+	values, ok := v.([]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	s := make([]string, 0, len(values))
+	for _, value := range values {
+		str, ok := value.(string)
+		if !ok {
+			return nil, false
+		}
+
+		s = append(s, str)
+	}
+
+	return s, true
+}
@@ -0,0 +1,84 @@
+package jwt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVerifiedTokenGetters(t *testing.T) {
+	verifiedToken := testVerifiedToken(t, Map{
+		"username": "kataras",
+		"age":      27,
+		"big":      int64(1) << 60,
+		"ratio":    1.5,
+		"admin":    true,
+		"groups":   []string{"dev", "ops"},
+		"empty":    []string{},
+		"mixed":    []interface{}{"dev", 1},
+		"null":     nil,
+		"number":   "27",
+	})
+
+	if v, ok := verifiedToken.GetString("username"); !ok || v != "kataras" {
+		t.Fatalf("expected username: %q but got: %q (%v)", "kataras", v, ok)
+	}
+
+	if v, ok := verifiedToken.GetInt64("age"); !ok || v != 27 {
+		t.Fatalf("expected age: %d but got: %d (%v)", 27, v, ok)
+	}
+
+	if v, ok := verifiedToken.GetInt64("big"); !ok || v != int64(1)<<60 {
+		t.Fatalf("expected big: %d but got: %d (%v)", int64(1)<<60, v, ok)
+	}
+
+	if v, ok := verifiedToken.GetFloat64("ratio"); !ok || v != 1.5 {
+		t.Fatalf("expected ratio: %v but got: %v (%v)", 1.5, v, ok)
+	}
+
+	if v, ok := verifiedToken.GetFloat64("age"); !ok || v != 27 {
+		t.Fatalf("expected age: %v but got: %v (%v)", 27, v, ok)
+	}
+
+	if v, ok := verifiedToken.GetBool("admin"); !ok || !v {
+		t.Fatalf("expected admin: true but got: %v (%v)", v, ok)
+	}
+
+	if v, ok := verifiedToken.GetStringSlice("groups"); !ok || !reflect.DeepEqual(v, []string{"dev", "ops"}) {
+		t.Fatalf("expected groups: [dev ops] but got: %q (%v)", v, ok)
+	}
+
+	if v, ok := verifiedToken.GetStringSlice("empty"); !ok || len(v) != 0 {
+		t.Fatalf("expected an empty slice but got: %q (%v)", v, ok)
+	}
+
+	// Missing claims and type mismatches.
+	var tests = []struct {
+		name string
+		get  func(name string) bool
+	}{
+		{"missing", func(name string) bool { _, ok := verifiedToken.GetString(name); return ok }},
+		{"age", func(name string) bool { _, ok := verifiedToken.GetString(name); return ok }},
+		{"null", func(name string) bool { _, ok := verifiedToken.GetString(name); return ok }},
+		{"missing", func(name string) bool { _, ok := verifiedToken.GetInt64(name); return ok }},
+		{"ratio", func(name string) bool { _, ok := verifiedToken.GetInt64(name); return ok }},
+		{"number", func(name string) bool { _, ok := verifiedToken.GetInt64(name); return ok }},
+		{"admin", func(name string) bool { _, ok := verifiedToken.GetInt64(name); return ok }},
+		{"number", func(name string) bool { _, ok := verifiedToken.GetFloat64(name); return ok }},
+		{"missing", func(name string) bool { _, ok := verifiedToken.GetBool(name); return ok }},
+		{"username", func(name string) bool { _, ok := verifiedToken.GetBool(name); return ok }},
+		{"missing", func(name string) bool { _, ok := verifiedToken.GetStringSlice(name); return ok }},
+		{"username", func(name string) bool { _, ok := verifiedToken.GetStringSlice(name); return ok }},
+		{"mixed", func(name string) bool { _, ok := verifiedToken.GetStringSlice(name); return ok }},
+	}
+
+	for i, tt := range tests {
+		if tt.get(tt.name) {
+			t.Fatalf("[%d] %s: expected false", i, tt.name)
+		}
+	}
+
+	// Non-JSON payload.
+	if _, ok := (&VerifiedToken{Payload: []byte("raw")}).GetString("username"); ok {
+		t.Fatalf("expected false on non-JSON payload")
+	}
+}
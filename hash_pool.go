@@ -0,0 +1,36 @@
+package jwt
+
+import (
+	"crypto"
+	"hash"
+	"sync"
+)
+
+// hashPools caches the hash states of the asymmetric algorithms,
+// indexed by their crypto.Hash, to reduce the allocations per sign and verify.
+var hashPools [crypto.BLAKE2b_512 + 1]sync.Pool
+
+// sumHash returns the checksum of "data" using a pooled hash state of "hasher".
+// The state is reset before it's put back to the pool.
+func sumHash(hasher crypto.Hash, data []byte) []byte {
+	if int(hasher) >= len(hashPools) {
+		h := hasher.New()
+		h.Write(data)
+		return h.Sum(nil)
+	}
+
+	pool := &hashPools[hasher]
+
+	h, ok := pool.Get().(hash.Hash)
+	if !ok {
+		h = hasher.New()
+	}
+
+	h.Write(data) // it never returns an error.
+	sum := h.Sum(nil)
+
+	h.Reset()
+	pool.Put(h)
+
+	return sum
+}
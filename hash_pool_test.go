@@ -0,0 +1,37 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha256"
+	"crypto/sha512"
+	"sync"
+	"testing"
+)
+
+func TestSumHash(t *testing.T) {
+	data := []byte("header.payload")
+	expected256 := sha256.Sum256(data)
+	expected512 := sha512.Sum512(data)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 50; j++ {
+				if got := sumHash(crypto.SHA256, data); !bytes.Equal(expected256[:], got) {
+					t.Errorf("expected SHA256 sum: %x but got: %x", expected256, got)
+					return
+				}
+
+				if got := sumHash(crypto.SHA512, data); !bytes.Equal(expected512[:], got) {
+					t.Errorf("expected SHA512 sum: %x but got: %x", expected512, got)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -19,14 +19,29 @@ func (a *algHMAC) Name() string {
 	return a.name
 }
 
-// func (a *algHMAC) Parse(secret string) (string, error) {
-// 	return secret, nil
-// }
+// Parse completes the AlgParser interface.
+// HMAC uses a single shared key for both signing and verifying,
+// so if the "public" one is missing then the "private" one is used instead.
+func (a *algHMAC) Parse(private, public []byte) (privateKey PrivateKey, publicKey PublicKey, err error) {
+	if len(public) == 0 {
+		public = private
+	}
+
+	if len(private) > 0 {
+		privateKey = private
+	}
+
+	if len(public) > 0 {
+		publicKey = public
+	}
+
+	return
+}
 
 func (a *algHMAC) Sign(key PrivateKey, headerAndPayload []byte) ([]byte, error) {
 	secret, ok := key.([]byte)
 	if !ok {
-		return nil, fmt.Errorf("expected a string: %w", ErrInvalidKey)
+		return nil, fmt.Errorf("expected a []byte key: %w", ErrInvalidKey)
 	}
 
 	// We can improve its performance (if we store the secret on the same structure)
@@ -47,6 +62,7 @@ func (a *algHMAC) Verify(key PublicKey, headerAndPayload []byte, signature []byt
 		return err
 	}
 
+	// Constant time comparison, it does not leak timing information.
 	if !hmac.Equal(expectedSignature, signature) {
 		return ErrTokenSignature
 	}
@@ -1,6 +1,9 @@
 package jwt
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"testing"
 )
 
@@ -37,3 +40,129 @@ func catchPanic(t *testing.T, shouldPanic bool, fn func()) {
 		t.Fatalf("expected panic: %v: %v", got, val)
 	}
 }
+
+func TestEncodeDecodeTokenHMACVariants(t *testing.T) {
+	claims := Map{"username": "kataras", "age": 27.0}
+
+	for _, alg := range []Alg{HS256, HS384, HS512} {
+		token, err := Sign(alg, testSecret, claims)
+		if err != nil {
+			t.Fatalf("[%s] sign: %v", alg.Name(), err)
+		}
+
+		verifiedToken, err := Verify(alg, testSecret, token)
+		if err != nil {
+			t.Fatalf("[%s] verify: %v", alg.Name(), err)
+		}
+
+		var header map[string]interface{}
+		if err = json.Unmarshal(verifiedToken.Header, &header); err != nil {
+			t.Fatalf("[%s] header: %v", alg.Name(), err)
+		}
+
+		if expected, got := alg.Name(), header["alg"]; expected != got {
+			t.Fatalf("expected header alg: %q but got: %v", expected, got)
+		}
+
+		// The payload should be compatible with the standard encoding/json package.
+		var got map[string]interface{}
+		if err = json.Unmarshal(verifiedToken.Payload, &got); err != nil {
+			t.Fatalf("[%s] payload: %v", alg.Name(), err)
+		}
+
+		if !compareMap(claims, got) {
+			t.Fatalf("[%s] expected claims: %#+v but got: %#+v", alg.Name(), claims, got)
+		}
+
+		// Other HMAC algorithms should not verify a token signed by a different one.
+		for _, other := range []Alg{HS256, HS384, HS512} {
+			if other == alg {
+				continue
+			}
+
+			if _, err = Verify(other, testSecret, token); err == nil {
+				t.Fatalf("[%s] expected verification with %s to fail", alg.Name(), other.Name())
+			}
+		}
+	}
+}
+
+func TestVerifyInvalidSignatureHMAC(t *testing.T) {
+	headerAndPayload := testToken[:bytes.LastIndexByte(testToken, '.')]
+
+	signature, err := HS256.Sign(testSecret, headerAndPayload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = HS256.Verify(testSecret, headerAndPayload, signature); err != nil {
+		t.Fatal(err)
+	}
+
+	var invalidSignatures = [][]byte{
+		nil,
+		{},
+		signature[:len(signature)-1],
+		append(append([]byte{}, signature...), 0),
+		func() []byte { // same length, last bit flipped.
+			s := append([]byte{}, signature...)
+			s[len(s)-1] ^= 1
+			return s
+		}(),
+	}
+
+	for i, invalid := range invalidSignatures {
+		if err = HS256.Verify(testSecret, headerAndPayload, invalid); err != ErrTokenSignature {
+			t.Fatalf("[%d] expected error: ErrTokenSignature but got: %v", i, err)
+		}
+	}
+
+	if err = HS256.Verify([]byte("other"), headerAndPayload, signature); err != ErrTokenSignature {
+		t.Fatalf("expected error: ErrTokenSignature on a different key but got: %v", err)
+	}
+
+	if err = HS256.Verify("secret", headerAndPayload, signature); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected error: ErrInvalidKey on a string key but got: %v", err)
+	}
+}
+
+func TestLoadKeysConfigurationHMAC(t *testing.T) {
+	keys := KeysConfiguration{
+		{ID: "api", Alg: "HS512", Private: string(testSecret)},
+	}.MustLoad()
+
+	key, ok := keys.Get("api")
+	if !ok {
+		t.Fatalf("expected key to be registered")
+	}
+
+	if key.Alg != HS512 {
+		t.Fatalf("expected algorithm: HS512 but got: %s", key.Alg.Name())
+	}
+
+	token, err := keys.SignToken("api", Map{"foo": "bar"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var claims Map
+	if err = keys.VerifyToken(token, &claims); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "bar", claims["foo"]; expected != got {
+		t.Fatalf("expected claims[foo]: %q but got: %v", expected, got)
+	}
+}
+
+func BenchmarkSignHMAC(b *testing.B) {
+	headerAndPayload := testToken[:bytes.LastIndexByte(testToken, '.')]
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := HS256.Sign(testSecret, headerAndPayload); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
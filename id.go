@@ -0,0 +1,70 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// generatedIDSize is the number of the random bytes of a generated "jti".
+const generatedIDSize = 16
+
+// WithGeneratedID is a SignOption which sets the "jti" claim
+// to a random identifier (the base64url of 16 bytes of crypto/rand)
+// if the claims (or any other SignOption) does not contain one.
+// It pairs with the `WithBlocklist` verify option for token revocation.
+//
+// Example Code:
+//
+//	token, err := jwt.Sign(jwt.HS256, sharedKey, claims, jwt.MaxAge(15*time.Minute), jwt.WithGeneratedID())
+func WithGeneratedID() SignConfigOption {
+	return func(c *signConfig) {
+		c.generateID = true
+	}
+}
+
+// GenerateID returns a random identifier, the base64url of 16 bytes of crypto/rand.
+func GenerateID() (string, error) {
+	b := make([]byte, generatedIDSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("jwt: generate id: %w", err)
+	}
+
+	return string(Base64Encode(b)), nil
+}
+
+// withGeneratedID inserts a "jti" field of a generated identifier
+// to the JSON object "payload", if it does not contain one.
+func withGeneratedID(payload []byte) ([]byte, error) {
+	hasID := false
+	isObject := walkObject(payload, func(key []byte, _, _ int) bool {
+		hasID = string(key) == "jti"
+		return !hasID
+	})
+
+	if !isObject {
+		return nil, errPayloadNotJSON
+	}
+
+	if hasID {
+		return payload, nil
+	}
+
+	id, err := GenerateID()
+	if err != nil {
+		return nil, err
+	}
+
+	i := skipSpace(payload, 0) + 1 // after '{'.
+	isEmpty := payload[skipSpace(payload, i)] == '}'
+
+	b := make([]byte, 0, len(payload)+len(id)+9)
+	b = append(b, payload[:i]...)
+	b = append(b, `"jti":"`...)
+	b = append(b, id...)
+	b = append(b, '"')
+	if !isEmpty {
+		b = append(b, ',')
+	}
+
+	return append(b, payload[i:]...), nil
+}
@@ -0,0 +1,87 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithGeneratedID(t *testing.T) {
+	ids := make(map[string]struct{})
+	for i := 0; i < 2; i++ {
+		token, err := Sign(testAlg, testSecret, Map{"username": "kataras"}, MaxAge(time.Minute), WithGeneratedID())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		verifiedToken, err := Verify(testAlg, testSecret, token)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		id := verifiedToken.StandardClaims.ID
+		if expected, got := 22, len(id); expected != got { // base64url of 16 bytes.
+			t.Fatalf("expected id length: %d but got: %d (%q)", expected, got, id)
+		}
+
+		if _, exists := ids[id]; exists {
+			t.Fatalf("expected distinct ids but got: %q twice", id)
+		}
+		ids[id] = struct{}{}
+
+		var claims Map
+		if err = verifiedToken.Claims(&claims); err != nil {
+			t.Fatal(err)
+		}
+
+		if expected, got := "kataras", claims["username"]; expected != got {
+			t.Fatalf("expected username: %q but got: %v", expected, got)
+		}
+	}
+
+	// An explicit "jti" is respected.
+	var tests = []struct {
+		claims interface{}
+		opts   []SignOption
+	}{
+		{Map{"jti": "my-id"}, nil},
+		{Claims{ID: "my-id"}, nil},
+		{Map{"username": "kataras"}, []SignOption{Claims{ID: "my-id"}}},
+		{[]byte(`{"username":"kataras","jti":"my-id"}`), nil},
+	}
+
+	for i, tt := range tests {
+		token, err := Sign(testAlg, testSecret, tt.claims, append(tt.opts, WithGeneratedID())...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		verifiedToken, err := Verify(testAlg, testSecret, token)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if expected, got := "my-id", verifiedToken.StandardClaims.ID; expected != got {
+			t.Fatalf("[%d] expected id: %q but got: %q", i, expected, got)
+		}
+	}
+
+	// Empty object.
+	token, err := Sign(testAlg, testSecret, Map{}, WithGeneratedID())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if verifiedToken.StandardClaims.ID == "" {
+		t.Fatalf("expected a generated id on an empty payload")
+	}
+
+	if _, err = Sign(testAlg, testSecret, []byte("plain"), WithGeneratedID()); !errors.Is(err, errPayloadNotJSON) {
+		t.Fatalf("expected error: errPayloadNotJSON but got: %v", err)
+	}
+}
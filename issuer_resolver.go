@@ -0,0 +1,86 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownIssuer indicates that the `IssuerResolver` of the `WithIssuerResolver` option
+// does not know the issuer of the token's "iss" claim.
+var ErrUnknownIssuer = errors.New("jwt: unknown issuer")
+
+// IssuerResolver resolves the verification key and algorithm of a token issuer.
+// See `WithIssuerResolver`.
+type IssuerResolver interface {
+	// Resolve returns the public key and the algorithm
+	// which the tokens of the given "iss" are signed with.
+	// It reports false if the issuer is not trusted.
+	Resolve(iss string) (key interface{}, alg Alg, ok bool)
+}
+
+// IssuerResolverFunc completes the IssuerResolver interface.
+type IssuerResolverFunc func(iss string) (interface{}, Alg, bool)
+
+// Resolve completes the IssuerResolver interface.
+func (fn IssuerResolverFunc) Resolve(iss string) (interface{}, Alg, bool) {
+	return fn(iss)
+}
+
+// WithIssuerResolver is a VerifyOption which selects the key and the algorithm
+// of the signature verification based on the token's "iss" claim,
+// e.g. on an API gateway which accepts tokens of several issuers.
+// The "alg" and "key" arguments of the Verify function are ignored
+// and the token's "alg" header must match the resolved algorithm.
+//
+// The "iss" claim is read from the payload before the signature verification,
+// so it is untrusted: it only selects a key, the token is still rejected
+// if it's not signed by that key. Encrypted payloads are not supported.
+// It returns an `ErrUnknownIssuer` error if the resolver does not know the issuer.
+//
+// Example Code:
+//
+//	resolver := jwt.IssuerResolverFunc(func(iss string) (interface{}, jwt.Alg, bool) {
+//	  switch iss {
+//	  case "https://issuer-a.example.com":
+//	    return publicKeyA, jwt.RS256, true
+//	  case "https://issuer-b.example.com":
+//	    return publicKeyB, jwt.EdDSA, true
+//	  default:
+//	    return nil, nil, false
+//	  }
+//	})
+//
+//	verifiedToken, err := jwt.Verify(nil, nil, token, jwt.WithIssuerResolver(resolver))
+func WithIssuerResolver(resolver IssuerResolver) VerifyOption {
+	return func(c *verifyConfig) {
+		c.issuerResolver = resolver
+	}
+}
+
+// resolveIssuer returns the key and the algorithm of the unverified token's issuer.
+func resolveIssuer(resolver IssuerResolver, token []byte, base64Decode func([]byte) ([]byte, error)) (Alg, PublicKey, error) {
+	_, payload, _, ok := SplitToken(token)
+	if !ok {
+		return nil, nil, ErrMalformed
+	}
+
+	payload, err := base64Decode(payload)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: payload: %v", ErrMalformed, err)
+	}
+
+	var claims struct {
+		Issuer string `json:"iss"`
+	}
+	if err = json.Unmarshal(payload, &claims); err != nil {
+		return nil, nil, errPayloadNotJSON
+	}
+
+	key, alg, ok := resolver.Resolve(claims.Issuer)
+	if !ok || alg == nil {
+		return nil, nil, fmt.Errorf("%w: %q", ErrUnknownIssuer, claims.Issuer)
+	}
+
+	return alg, key, nil
+}
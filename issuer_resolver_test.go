@@ -0,0 +1,88 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWithIssuerResolver(t *testing.T) {
+	privateKey, err := LoadPrivateKeyECDSA("./_testfiles/ecdsa_private_key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secretA, secretC := []byte("secret-a"), []byte("secret-c")
+
+	var resolved []string
+	resolver := IssuerResolverFunc(func(iss string) (interface{}, Alg, bool) {
+		resolved = append(resolved, iss)
+
+		switch iss {
+		case "issuer-a":
+			return secretA, HS256, true
+		case "issuer-b":
+			return &privateKey.PublicKey, ES256, true
+		case "issuer-c":
+			return secretC, HS256, true
+		default:
+			return nil, nil, false
+		}
+	})
+
+	tokenA, err := Sign(HS256, secretA, Claims{Issuer: "issuer-a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokenB, err := Sign(ES256, privateKey, Claims{Issuer: "issuer-b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, token := range [][]byte{tokenA, tokenB} {
+		if _, err = Verify(nil, nil, token, WithIssuerResolver(resolver)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if expected, got := 2, len(resolved); expected != got {
+		t.Fatalf("expected: %d resolutions but got: %d", expected, got)
+	}
+
+	// The resolved key and algorithm take precedence.
+	if _, err = Verify(HS256, []byte("othersecret"), tokenA, WithIssuerResolver(resolver)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The "iss" is untrusted: a token of issuer-a which claims to be of another issuer.
+	forged, err := Sign(HS256, secretA, Claims{Issuer: "issuer-c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(nil, nil, forged, WithIssuerResolver(resolver)); !errors.Is(err, ErrTokenSignature) {
+		t.Fatalf("expected error: ErrTokenSignature but got: %v", err)
+	}
+
+	forged, err = Sign(HS256, secretA, Claims{Issuer: "issuer-b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(nil, nil, forged, WithIssuerResolver(resolver)); !errors.Is(err, ErrTokenAlg) {
+		t.Fatalf("expected error: ErrTokenAlg but got: %v", err)
+	}
+
+	unknown, err := Sign(HS256, secretA, Claims{Issuer: "issuer-d"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(nil, nil, unknown, WithIssuerResolver(resolver)); !errors.Is(err, ErrUnknownIssuer) {
+		t.Fatalf("expected error: ErrUnknownIssuer but got: %v", err)
+	}
+
+	if _, err = Verify(nil, nil, []byte("a.b"), WithIssuerResolver(resolver)); !errors.Is(err, ErrMalformed) {
+		t.Fatalf("expected error: ErrMalformed but got: %v", err)
+	}
+}
@@ -0,0 +1,193 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+)
+
+// The supported JWE (RFC 7516) key management and content encryption algorithms.
+const (
+	// JWEAlgRSAOAEP is the RSAES OAEP (SHA-1 and MGF1 with SHA-1) key management algorithm.
+	JWEAlgRSAOAEP = "RSA-OAEP"
+	// JWEEncA256GCM is the AES GCM content encryption algorithm using a 256-bit key.
+	JWEEncA256GCM = "A256GCM"
+)
+
+// ErrDecryption indicates that a JWE token could not be decrypted,
+// e.g. the content encryption key was not encrypted for the given private key
+// or the ciphertext was tampered with.
+var ErrDecryption = errors.New("jwt: jwe: decryption failed")
+
+const (
+	jweKeySize = 32 // A256GCM.
+	jweIVSize  = 12
+	jweTagSize = 16
+)
+
+type jweHeader struct {
+	Alg string `json:"alg"`
+	Enc string `json:"enc"`
+	Zip string `json:"zip,omitempty"`
+	Cty string `json:"cty,omitempty"`
+	Kid string `json:"kid,omitempty"`
+}
+
+// DecryptJWE decrypts a JWE token of the compact serialization (RFC 7516, Section 7.1),
+// encrypted with the "RSA-OAEP" key management algorithm
+// and the "A256GCM" content encryption algorithm.
+// It returns the plaintext payload, decompressed if the header contains "zip":"DEF".
+//
+// It returns `ErrTokenForm` if the token has not five dot-separated parts,
+// `ErrTokenAlg` on any other algorithm and `ErrDecryption` on a wrong key
+// or a tampered token.
+//
+// Example Code:
+//
+//	payload, err := jwt.DecryptJWE(token, privateKey)
+func DecryptJWE(token []byte, key *rsa.PrivateKey) ([]byte, error) {
+	if key == nil {
+		return nil, ErrInvalidKey
+	}
+
+	parts := bytes.Split(token, sep)
+	if len(parts) != 5 {
+		return nil, ErrTokenForm
+	}
+
+	protected := parts[0]
+	headerDecoded, err := Base64Decode(protected)
+	if err != nil {
+		return nil, fmt.Errorf("%w: header: %v", ErrTokenForm, err)
+	}
+
+	var header jweHeader
+	if err = Unmarshal(headerDecoded, &header); err != nil {
+		return nil, fmt.Errorf("%w: header: %v", ErrTokenForm, err)
+	}
+
+	if header.Alg != JWEAlgRSAOAEP || header.Enc != JWEEncA256GCM {
+		return nil, fmt.Errorf("%w: %q/%q", ErrTokenAlg, header.Alg, header.Enc)
+	}
+
+	decoded := make([][]byte, 4)
+	for i, part := range parts[1:] {
+		if decoded[i], err = Base64Decode(part); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrTokenForm, err)
+		}
+	}
+	encryptedKey, iv, ciphertext, tag := decoded[0], decoded[1], decoded[2], decoded[3]
+
+	if len(iv) != jweIVSize || len(tag) != jweTagSize {
+		return nil, ErrTokenForm
+	}
+
+	cek, err := rsa.DecryptOAEP(sha1.New(), nil, key, encryptedKey, nil)
+	if err != nil || len(cek) != jweKeySize {
+		return nil, ErrDecryption
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// The additional authenticated data is the ASCII of the encoded protected header.
+	plaintext, err := gcm.Open(nil, iv, append(ciphertext, tag...), protected)
+	if err != nil {
+		return nil, ErrDecryption
+	}
+
+	switch header.Zip {
+	case "":
+		return plaintext, nil
+	case compressionDeflate:
+		return inflate(plaintext, DefaultMaxDecompressedSize)
+	default:
+		return nil, fmt.Errorf("%w: %q", errUnsupportedCompression, header.Zip)
+	}
+}
+
+// EncryptJWE encrypts the "payload" to a JWE token of the compact serialization
+// using the "RSA-OAEP" key management algorithm with the given public key
+// and the "A256GCM" content encryption algorithm. A random content encryption key
+// and initialization vector are generated on each call.
+// The receiver decrypts it with the `DecryptJWE` function and its private key.
+//
+// See `SignAndEncrypt` to encrypt a signed token (nested JWT).
+func EncryptJWE(payload []byte, pub *rsa.PublicKey) ([]byte, error) {
+	return encryptJWE(payload, pub, jweHeader{Alg: JWEAlgRSAOAEP, Enc: JWEEncA256GCM})
+}
+
+// SignAndEncrypt signs the "claims" using the "alg" and the "signKey" (see `Sign`)
+// and encrypts the resulted token with the "encPub" public key (see `EncryptJWE`).
+// It produces a nested JWT (RFC 7519, Section 5.2): the JWE's header
+// contains the "cty":"JWT" field which indicates that its plaintext is a signed token.
+// It is useful to keep the claims confidential.
+//
+// The receiver decrypts the token and then verifies the plaintext:
+//
+//	token, err := jwt.SignAndEncrypt(jwt.RS256, signPrivateKey, encPublicKey, claims, jwt.MaxAge(15*time.Minute))
+//	[...]
+//	signedToken, err := jwt.DecryptJWE(token, encPrivateKey)
+//	verifiedToken, err := jwt.Verify(jwt.RS256, signPublicKey, signedToken)
+func SignAndEncrypt(alg Alg, signKey PrivateKey, encPub *rsa.PublicKey, claims interface{}, opts ...SignOption) ([]byte, error) {
+	signedToken, err := Sign(alg, signKey, claims, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return encryptJWE(signedToken, encPub, jweHeader{Alg: JWEAlgRSAOAEP, Enc: JWEEncA256GCM, Cty: "JWT"})
+}
+
+func encryptJWE(payload []byte, pub *rsa.PublicKey, header jweHeader) ([]byte, error) {
+	if pub == nil {
+		return nil, ErrInvalidKey
+	}
+
+	headerB, err := Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	protected := Base64Encode(headerB)
+
+	cek := make([]byte, jweKeySize)
+	if _, err = rand.Read(cek); err != nil {
+		return nil, err
+	}
+
+	iv := make([]byte, jweIVSize)
+	if _, err = rand.Read(iv); err != nil {
+		return nil, err
+	}
+
+	encryptedKey, err := rsa.EncryptOAEP(sha1.New(), rand.Reader, pub, cek, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed := gcm.Seal(nil, iv, payload, protected)
+	ciphertext, tag := sealed[:len(sealed)-jweTagSize], sealed[len(sealed)-jweTagSize:]
+
+	return joinParts(protected, Base64Encode(encryptedKey), Base64Encode(iv), Base64Encode(ciphertext), Base64Encode(tag)), nil
+}
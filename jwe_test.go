@@ -0,0 +1,271 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// testEncryptJWE produces a compact RSA-OAEP/A256GCM JWE of "plaintext", step by step.
+func testEncryptJWE(t *testing.T, header string, plaintext []byte, pub *rsa.PublicKey) []byte {
+	t.Helper()
+
+	cek, iv := MustGenerateRandom(32), MustGenerateRandom(12)
+	encryptedKey, err := rsa.EncryptOAEP(sha1.New(), rand.Reader, pub, cek, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	protected := Base64Encode([]byte(header))
+	sealed := gcm.Seal(nil, iv, plaintext, protected)
+	ciphertext, tag := sealed[:len(sealed)-16], sealed[len(sealed)-16:]
+
+	return joinParts(protected, Base64Encode(encryptedKey), Base64Encode(iv), Base64Encode(ciphertext), Base64Encode(tag))
+}
+
+func TestDecryptJWE(t *testing.T) {
+	privateKey, _ := MustLoadRSA("./_testfiles/rsa_private_key.pem", "./_testfiles/rsa_public_key.pem")
+	plaintext := []byte("The true sign of intelligence is not knowledge but imagination.")
+
+	token := testEncryptJWE(t, `{"alg":"RSA-OAEP","enc":"A256GCM"}`, plaintext, &privateKey.PublicKey)
+
+	got, err := DecryptJWE(token, privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(plaintext, got) {
+		t.Fatalf("expected plaintext: %q but got: %q", plaintext, got)
+	}
+
+	// Tampered ciphertext: GCM authentication failure.
+	parts := bytes.Split(token, sep)
+	ciphertext, _ := Base64Decode(parts[3])
+	ciphertext[0] ^= 1
+	tampered := joinParts(parts[0], parts[1], parts[2], Base64Encode(ciphertext), parts[4])
+
+	if _, err = DecryptJWE(tampered, privateKey); err != ErrDecryption {
+		t.Fatalf("expected error: ErrDecryption but got: %v", err)
+	}
+
+	// Tampered protected header (additional authenticated data).
+	parts = bytes.Split(token, sep)
+	tampered = joinParts(Base64Encode([]byte(`{"enc":"A256GCM","alg":"RSA-OAEP"}`)), parts[1], parts[2], parts[3], parts[4])
+	if _, err = DecryptJWE(tampered, privateKey); err != ErrDecryption {
+		t.Fatalf("expected error: ErrDecryption on a tampered header but got: %v", err)
+	}
+
+	// Different private key.
+	otherKey, err := GenerateRSAKeys(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = DecryptJWE(token, otherKey); err != ErrDecryption {
+		t.Fatalf("expected error: ErrDecryption but got: %v", err)
+	}
+
+	// Compressed plaintext.
+	compressed, err := deflate(plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token = testEncryptJWE(t, `{"alg":"RSA-OAEP","enc":"A256GCM","zip":"DEF"}`, compressed, &privateKey.PublicKey)
+	if got, err = DecryptJWE(token, privateKey); err != nil || !bytes.Equal(plaintext, got) {
+		t.Fatalf("expected decompressed plaintext: %q but got: %q (%v)", plaintext, got, err)
+	}
+}
+
+// The RSA-OAEP and A256GCM example of RFC 7516, Appendix A.1.
+const (
+	testRFC7516Token = "eyJhbGciOiJSU0EtT0FFUCIsImVuYyI6IkEyNTZHQ00ifQ." +
+		"OKOawDo13gRp2ojaHV7LFpZcgV7T6DVZKTyKOMTYUmKoTCVJRgckCL9kiMT03JGe" +
+		"ipsEdY3mx_etLbbWSrFr05kLzcSr4qKAq7YN7e9jwQRb23nfa6c9d-StnImGyFDb" +
+		"Sv04uVuxIp5Zms1gNxKKK2Da14B8S4rzVRltdYwam_lDp5XnZAYpQdb76FdIKLaV" +
+		"mqgfwX7XWRxv2322i-vDxRfqNzo_tETKzpVLzfiwQyeyPGLBIO56YJ7eObdv0je8" +
+		"1860ppamavo35UgoRdbYaBcoh9QcfylQr66oc6vFWXRcZ_ZT2LawVCWTIy3brGPi" +
+		"6UklfCpIMfIjf7iGdXKHzg." +
+		"48V1_ALb6US04U3b." +
+		"5eym8TW_c8SuK0ltJ3rpYIzOeDQz7TALvtu6UG9oMo4vpzs9tX_EFShS8iB7j6ji" +
+		"SdiwkIr3ajwQzaBtQD_A." +
+		"XFBoMYUZodetZdvTiFvSkQ"
+
+	testRFC7516Plaintext = "The true sign of intelligence is not knowledge but imagination."
+)
+
+// testRFC7516JWK is the RSA private key of RFC 7516, Appendix A.1.3.
+var testRFC7516JWK = map[string]string{
+	"n": "oahUIoWw0K0usKNuOR6H4wkf4oBUXHTxRvgb48E-BVvxkeDNjbC4he8rUWcJoZmds2h7M70imEVhRU5djINXtqllXI4DFqcI1DgjT9LewND8MW2Krf3Spsk_ZkoFnilakGygTwpZ3uesH-PFABNIUYpOiN15dsQRkgr0vEhxN92i2asbOenSZeyaxziK72UwxrrKoExv6kc5twXTq4h-QChLOln0_mtUZwfsRaMStPs6mS6XrgxnxbWhojf663tuEQueGC-FCMfra36C9knDFGzKsNa7LZK2djYgyD3JR_MB_4NUJW_TqOQtwHYbxevoJArm-L5StowjzGy-_bq6Gw",
+	"e": "AQAB",
+	"d": "kLdtIj6GbDks_ApCSTYQtelcNttlKiOyPzMrXHeI-yk1F7-kpDxY4-WY5NWV5KntaEeXS1j82E375xxhWMHXyvjYecPT9fpwR_M9gV8n9Hrh2anTpTD93Dt62ypW3yDsJzBnTnrYu1iwWRgBKrEYY46qAZIrA2xAwnm2X7uGR1hghkqDp0Vqj3kbSCz1XyfCs6_LehBwtxHIyh8Ripy40p24moOAbgxVw3rxT_vlt3UVe4WO3JkJOzlpUf-KTVI2Ptgm-dARxTEtE-id-4OJr0h-K-VFs3VSndVTIznSxfyrj8ILL6MG_Uv8YAu7VILSB3lOW085-4qE3DzgrTjgyQ",
+	"p": "1r52Xk46c-LsfB5P442p7atdPUrxQSy4mti_tZI3Mgf2EuFVbUoDBvaRQ-SWxkbkmoEzL7JXroSBjSrK3YIQgYdMgyAEPTPjXv_hI2_1eTSPVZfzL0lffNn03IXqWF5MDFuoUYE0hzb2vhrlN_rKrbfDIwUbTrjjgieRbwC6Cl0",
+	"q": "wLb35x7hmQWZsWJmB_vle87ihgZ19S8lBEROLIsZG4ayZVe9Hi9gDVCOBmUDdaDYVTSNx_8Fyw1YYa9XGrGnDew00J28cRUoeBB_jKI1oma0Orv1T9aXIWxKwd4gvxFImOWr3QRL9KEBRzk2RatUBnmDZJTIAfwTs0g68UZHvtc",
+}
+
+func TestDecryptJWERFC7516(t *testing.T) {
+	ints := make(map[string]*big.Int, len(testRFC7516JWK))
+	for name, value := range testRFC7516JWK {
+		n, err := decodeJWKInt(value)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		ints[name] = n
+	}
+
+	privateKey := &rsa.PrivateKey{
+		PublicKey: rsa.PublicKey{N: ints["n"], E: int(ints["e"].Int64())},
+		D:         ints["d"],
+		Primes:    []*big.Int{ints["p"], ints["q"]},
+	}
+	if err := privateKey.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	privateKey.Precompute()
+
+	got, err := DecryptJWE([]byte(testRFC7516Token), privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := testRFC7516Plaintext; expected != string(got) {
+		t.Fatalf("expected plaintext: %q but got: %q", expected, got)
+	}
+}
+
+func TestDecryptJWEInvalid(t *testing.T) {
+	privateKey, _ := MustLoadRSA("./_testfiles/rsa_private_key.pem", "./_testfiles/rsa_public_key.pem")
+
+	token := testEncryptJWE(t, `{"alg":"RSA-OAEP-256","enc":"A256GCM"}`, []byte("payload"), &privateKey.PublicKey)
+	if _, err := DecryptJWE(token, privateKey); !errors.Is(err, ErrTokenAlg) {
+		t.Fatalf("expected error: ErrTokenAlg but got: %v", err)
+	}
+
+	token = testEncryptJWE(t, `{"alg":"RSA-OAEP","enc":"A128CBC-HS256"}`, []byte("payload"), &privateKey.PublicKey)
+	if _, err := DecryptJWE(token, privateKey); !errors.Is(err, ErrTokenAlg) {
+		t.Fatalf("expected error: ErrTokenAlg but got: %v", err)
+	}
+
+	// A JWS token.
+	if _, err := DecryptJWE(testToken, privateKey); err != ErrTokenForm {
+		t.Fatalf("expected error: ErrTokenForm but got: %v", err)
+	}
+
+	if _, err := DecryptJWE([]byte("a.b.c.d.e"), privateKey); !errors.Is(err, ErrTokenForm) {
+		t.Fatalf("expected error: ErrTokenForm but got: %v", err)
+	}
+
+	if _, err := DecryptJWE(token, nil); err != ErrInvalidKey {
+		t.Fatalf("expected error: ErrInvalidKey but got: %v", err)
+	}
+}
+
+func TestEncryptJWE(t *testing.T) {
+	privateKey, _ := MustLoadRSA("./_testfiles/rsa_private_key.pem", "./_testfiles/rsa_public_key.pem")
+	plaintext := []byte(`{"username":"kataras"}`)
+
+	token, err := EncryptJWE(plaintext, &privateKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := bytes.Count(token, sep); n != 4 {
+		t.Fatalf("expected five token parts but got: %d", n+1)
+	}
+
+	header, err := peekJWEHeader(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := `{"alg":"RSA-OAEP","enc":"A256GCM"}`, string(header); expected != got {
+		t.Fatalf("expected header: %s but got: %s", expected, got)
+	}
+
+	got, err := DecryptJWE(token, privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(plaintext, got) {
+		t.Fatalf("expected plaintext: %q but got: %q", plaintext, got)
+	}
+
+	// Random key and iv on each call.
+	other, err := EncryptJWE(plaintext, &privateKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Equal(token, other) {
+		t.Fatalf("expected different tokens of the same payload")
+	}
+
+	if _, err = EncryptJWE(plaintext, nil); err != ErrInvalidKey {
+		t.Fatalf("expected error: ErrInvalidKey but got: %v", err)
+	}
+}
+
+func TestSignAndEncrypt(t *testing.T) {
+	encPrivateKey, _ := MustLoadRSA("./_testfiles/rsa_private_key.pem", "./_testfiles/rsa_public_key.pem")
+	signKey := MustGenerateRandom(32)
+
+	token, err := SignAndEncrypt(HS256, signKey, &encPrivateKey.PublicKey, Map{"username": "kataras"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header, err := peekJWEHeader(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := `{"alg":"RSA-OAEP","enc":"A256GCM","cty":"JWT"}`, string(header); expected != got {
+		t.Fatalf("expected header: %s but got: %s", expected, got)
+	}
+
+	signedToken, err := DecryptJWE(token, encPrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(HS256, signKey, signedToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var claims Map
+	if err = verifiedToken.Claims(&claims); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "kataras", claims["username"]; expected != got {
+		t.Fatalf("expected username: %q but got: %v", expected, got)
+	}
+
+	if verifiedToken.StandardClaims.Expiry == 0 {
+		t.Fatalf("expected sign options to be applied")
+	}
+}
+
+// peekJWEHeader returns the decoded protected header of a JWE token.
+func peekJWEHeader(token []byte) ([]byte, error) {
+	return Base64Decode(token[:bytes.IndexByte(token, '.')])
+}
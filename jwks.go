@@ -0,0 +1,321 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// ErrInvalidJWK indicates that a key of a JWKS document
+// is malformed, e.g. missing or invalid RSA modulus.
+var ErrInvalidJWK = errors.New("jwt: invalid JSON Web Key")
+
+type (
+	// JWK represents a single JSON Web Key (RFC 7517) of a JWKS document.
+	// Only the public key fields are declared.
+	JWK struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid,omitempty"`
+		Use string `json:"use,omitempty"`
+		Alg string `json:"alg,omitempty"`
+		// RSA public key fields.
+		N string `json:"n,omitempty"`
+		E string `json:"e,omitempty"`
+		// EC and OKP public key fields.
+		Crv string `json:"crv,omitempty"`
+		X   string `json:"x,omitempty"`
+		Y   string `json:"y,omitempty"`
+	}
+
+	// JWKS represents a JSON Web Key Set document.
+	JWKS struct {
+		Keys []JWK `json:"keys"`
+	}
+
+	// KeySet holds the public keys of a JWKS document indexed by their "kid".
+	// It is safe for concurrent use as it is read-only after `ParseJWKS`.
+	// It completes the `HeaderValidator` through its `ValidateHeader` method.
+	//
+	// Usage:
+	//  keySet, err := jwt.ParseJWKS(body)
+	//  [handle error...]
+	//  verifiedToken, err := jwt.VerifyWithHeaderValidator(nil, nil, token, keySet.ValidateHeader)
+	KeySet struct {
+		keys Keys
+	}
+)
+
+// ParseJWKS decodes a JWKS (JSON Web Key Set) document
+// and returns a KeySet of its public keys indexed by their "kid".
+//
+// Supported key types are "RSA" (n, e), "EC" (crv, x, y) for the P-256, P-384 and P-521 curves
+// and "OKP" (crv, x) for the Ed25519 curve. Keys of unknown type, curve or algorithm are ignored,
+// as RFC 7517 suggests. A malformed key of a supported type returns an `ErrInvalidJWK` error.
+//
+// The key's algorithm is resolved from its "alg" field, when it's missing then RSA keys defaults to RS256,
+// EC keys to the curve's ES algorithm and OKP keys to EdDSA. The "alg" must match the key type:
+// the RS and PS algorithms for RSA keys, the curve's ES algorithm for EC keys and EdDSA for OKP keys,
+// otherwise (e.g. "none" or an HMAC algorithm) it returns an error which wraps `ErrInvalidJWK`.
+// Keys of an unknown "alg", e.g. encryption keys, are skipped.
+//
+// A duplicated "kid" returns an `ErrInvalidJWK` error. The "kid" is optional:
+// the first key without one is indexed by the empty "kid" and the rest of them are skipped.
+func ParseJWKS(b []byte) (*KeySet, error) {
+	var set JWKS
+	if err := json.Unmarshal(b, &set); err != nil {
+		return nil, fmt.Errorf("jwt: parse jwks: %w", err)
+	}
+
+	keys := make(Keys, len(set.Keys))
+	for i, jwk := range set.Keys {
+		alg, pub, ok, err := parseJWK(jwk)
+		if err != nil {
+			return nil, fmt.Errorf("jwt: parse jwks: key[%d]: %w", i, err)
+		}
+
+		if !ok {
+			continue
+		}
+
+		if _, exists := keys[jwk.Kid]; exists {
+			if jwk.Kid == "" {
+				continue // the "kid" is optional, keep the first key without one.
+			}
+
+			return nil, fmt.Errorf("jwt: parse jwks: key[%d]: %w: duplicated kid: %q", i, ErrInvalidJWK, jwk.Kid)
+		}
+
+		keys.Register(alg, jwk.Kid, pub, nil)
+	}
+
+	return &KeySet{keys: keys}, nil
+}
+
+// Key returns the public key and its algorithm based on the given "kid".
+// It reports false if the key set does not contain a key of that id.
+func (s *KeySet) Key(kid string) (PublicKey, Alg, bool) {
+	k, ok := s.keys.Get(kid)
+	if !ok {
+		return nil, nil, false
+	}
+
+	return k.Public, k.Alg, true
+}
+
+// ValidateHeader validates the given json header value (base64 decoded) based on the key set's keys.
+// KeySet structure completes the `HeaderValidator` interface.
+func (s *KeySet) ValidateHeader(alg string, headerDecoded []byte) (Alg, PublicKey, InjectFunc, error) {
+	return s.keys.ValidateHeader(alg, headerDecoded)
+}
+
+// parseJWK returns the algorithm and the public key of the "jwk".
+// It reports false if the key is not supported.
+func parseJWK(jwk JWK) (Alg, PublicKey, bool, error) {
+	var (
+		alg Alg
+		pub PublicKey
+		err error
+	)
+
+	switch jwk.Kty {
+	case "RSA":
+		alg = RS256
+		pub, err = parseJWKRSA(jwk)
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			alg, curve = ES256, elliptic.P256()
+		case "P-384":
+			alg, curve = ES384, elliptic.P384()
+		case "P-521":
+			alg, curve = ES512, elliptic.P521()
+		default:
+			return nil, nil, false, nil
+		}
+
+		pub, err = parseJWKEC(jwk, curve)
+	case "OKP":
+		if jwk.Crv != "Ed25519" {
+			return nil, nil, false, nil
+		}
+
+		alg = EdDSA
+		pub, err = parseJWKEd25519(jwk)
+	default:
+		return nil, nil, false, nil
+	}
+
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	if jwk.Alg != "" && jwk.Alg != alg.Name() {
+		// A case-insensitive search, so a "none" is rejected below too.
+		jwkAlg, ok := findAlg(jwk.Alg)
+		if !ok {
+			return nil, nil, false, nil // e.g. an encryption key.
+		}
+
+		// The "alg" must not select an algorithm of a different key type,
+		// e.g. NONE or HMAC, through a published key.
+		if !isRSAAlg(jwkAlg) || jwk.Kty != "RSA" {
+			return nil, nil, false, fmt.Errorf("%w: alg %q does not match the %s key", ErrInvalidJWK, jwk.Alg, jwk.Kty)
+		}
+
+		alg = jwkAlg
+	}
+
+	return alg, pub, true, nil
+}
+
+// isRSAAlg reports whether "alg" is one of the RS and PS algorithms.
+func isRSAAlg(alg Alg) bool {
+	switch alg.(type) {
+	case *algRSA, *algRSAPSS:
+		return true
+	default:
+		return false
+	}
+}
+
+func parseJWKRSA(jwk JWK) (*rsa.PublicKey, error) {
+	n, err := decodeJWKInt(jwk.N)
+	if err != nil {
+		return nil, fmt.Errorf("%w: n: %v", ErrInvalidJWK, err)
+	}
+
+	e, err := decodeJWKInt(jwk.E)
+	if err != nil {
+		return nil, fmt.Errorf("%w: e: %v", ErrInvalidJWK, err)
+	}
+
+	if !e.IsInt64() || e.Int64() < 2 || e.Int64() > 1<<31-1 {
+		return nil, fmt.Errorf("%w: e: out of range", ErrInvalidJWK)
+	}
+
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+func parseJWKEC(jwk JWK, curve elliptic.Curve) (*ecdsa.PublicKey, error) {
+	x, err := decodeJWKInt(jwk.X)
+	if err != nil {
+		return nil, fmt.Errorf("%w: x: %v", ErrInvalidJWK, err)
+	}
+
+	y, err := decodeJWKInt(jwk.Y)
+	if err != nil {
+		return nil, fmt.Errorf("%w: y: %v", ErrInvalidJWK, err)
+	}
+
+	if !curve.IsOnCurve(x, y) {
+		return nil, fmt.Errorf("%w: point is not on curve %s", ErrInvalidJWK, jwk.Crv)
+	}
+
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+func parseJWKEd25519(jwk JWK) (ed25519.PublicKey, error) {
+	x, err := Base64Decode([]byte(jwk.X))
+	if err != nil {
+		return nil, fmt.Errorf("%w: x: %v", ErrInvalidJWK, err)
+	}
+
+	if len(x) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("%w: x: invalid size", ErrInvalidJWK)
+	}
+
+	return ed25519.PublicKey(x), nil
+}
+
+func decodeJWKInt(s string) (*big.Int, error) {
+	if s == "" {
+		return nil, errors.New("missing")
+	}
+
+	b, err := Base64Decode([]byte(s))
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
+
+// MarshalJWKS encodes the public "keys", indexed by their "kid", to a JWKS document,
+// e.g. to be served as the "jwks_uri" of a token issuer. The document can be parsed back through `ParseJWKS`.
+//
+// Supported key types are the *rsa.PublicKey ("RSA"), the *ecdsa.PublicKey of the P-256, P-384 and P-521 curves ("EC")
+// and the ed25519.PublicKey ("OKP"). Private keys are not accepted, see `PublicKeyOf`.
+// The keys are sorted by their "kid" so the output is stable.
+func MarshalJWKS(keys map[string]PublicKey) ([]byte, error) {
+	kids := make([]string, 0, len(keys))
+	for kid := range keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	set := JWKS{Keys: make([]JWK, 0, len(keys))}
+	for _, kid := range kids {
+		jwk, err := NewJWK(kid, keys[kid])
+		if err != nil {
+			return nil, fmt.Errorf("jwt: marshal jwks: %w", err)
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return json.Marshal(set)
+}
+
+// NewJWK returns the JSON Web Key of the public "key" and its "kid".
+// See `MarshalJWKS` for the supported key types.
+func NewJWK(kid string, key PublicKey) (JWK, error) {
+	jwk := JWK{Kid: kid}
+
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		if pub == nil || pub.N == nil {
+			break
+		}
+
+		jwk.Kty = "RSA"
+		jwk.N = string(Base64Encode(pub.N.Bytes()))
+		jwk.E = string(Base64Encode(big.NewInt(int64(pub.E)).Bytes()))
+		return jwk, nil
+	case *ecdsa.PublicKey:
+		if pub == nil || pub.Curve == nil || pub.X == nil || pub.Y == nil {
+			break
+		}
+
+		switch pub.Curve {
+		case elliptic.P256(), elliptic.P384(), elliptic.P521():
+		default:
+			return JWK{}, fmt.Errorf("%w: kid: %q: unsupported curve: %s", ErrInvalidKey, kid, pub.Curve.Params().Name)
+		}
+
+		// The coordinates are of the full curve size (RFC 7518, Section 6.2.1.2).
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		jwk.Kty = "EC"
+		jwk.Crv = pub.Curve.Params().Name
+		jwk.X = string(Base64Encode(pub.X.FillBytes(make([]byte, size))))
+		jwk.Y = string(Base64Encode(pub.Y.FillBytes(make([]byte, size))))
+		return jwk, nil
+	case ed25519.PublicKey:
+		if len(pub) != ed25519.PublicKeySize {
+			break
+		}
+
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = string(Base64Encode(pub))
+		return jwk, nil
+	}
+
+	return JWK{}, fmt.Errorf("%w: kid: %q: unsupported public key type: %T", ErrInvalidKey, kid, key)
+}
@@ -0,0 +1,276 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultRemoteKeySetRefreshInterval is the default interval
+	// which a RemoteKeySet fetches its JWKS document again.
+	DefaultRemoteKeySetRefreshInterval = time.Hour
+	// DefaultRemoteKeySetRefreshRateLimit is the default minimum duration
+	// between two fetches of a RemoteKeySet, e.g. on unknown "kid" headers.
+	DefaultRemoteKeySetRefreshRateLimit = time.Minute
+
+	// maxJWKSSize limits the size of a remote JWKS document.
+	maxJWKSSize = 1 << 20
+)
+
+// RemoteKeySet is a JWKS (JSON Web Key Set) fetched from a remote URL,
+// e.g. the "jwks_uri" of an OpenID Connect provider.
+// It caches the result and refreshes it on a configurable interval
+// or on a token of an unknown "kid" (rate limited).
+//
+// It is safe for concurrent use, simultaneous refreshes are merged into a single request.
+// It completes the `HeaderValidator` through its `ValidateHeader` method.
+// See `NewRemoteKeySet` to create a new one.
+type RemoteKeySet struct {
+	url             string
+	client          *http.Client
+	refreshInterval time.Duration
+	rateLimit       time.Duration
+	clock           func() time.Time
+
+	mu          sync.RWMutex
+	keySet      *KeySet
+	fetchedAt   time.Time // last successful fetch.
+	attemptedAt time.Time // last fetch attempt.
+	generation  uint64    // increments on each fetch attempt.
+	err         error     // last fetch error.
+
+	refreshMu sync.Mutex // allows a single fetch at a time.
+}
+
+// RemoteKeySetOption sets an option of a RemoteKeySet.
+// See `NewRemoteKeySet`.
+type RemoteKeySetOption func(*RemoteKeySet)
+
+// WithHTTPClient sets the HTTP client used to fetch the JWKS document.
+// Defaults to a client with 10 seconds timeout.
+func WithHTTPClient(client *http.Client) RemoteKeySetOption {
+	return func(r *RemoteKeySet) {
+		if client != nil {
+			r.client = client
+		}
+	}
+}
+
+// WithRefreshInterval sets the interval which the cached JWKS document is considered stale.
+// Defaults to `DefaultRemoteKeySetRefreshInterval`.
+func WithRefreshInterval(interval time.Duration) RemoteKeySetOption {
+	return func(r *RemoteKeySet) {
+		if interval > 0 {
+			r.refreshInterval = interval
+		}
+	}
+}
+
+// WithRefreshRateLimit sets the minimum duration between two fetches of the JWKS document,
+// it prevents tokens of unknown "kid" from flooding the remote server.
+// Defaults to `DefaultRemoteKeySetRefreshRateLimit`.
+func WithRefreshRateLimit(limit time.Duration) RemoteKeySetOption {
+	return func(r *RemoteKeySet) {
+		if limit >= 0 {
+			r.rateLimit = limit
+		}
+	}
+}
+
+// NewRemoteKeySet returns a new RemoteKeySet of the given JWKS "url".
+// The document is fetched lazily, on the first key lookup.
+//
+// Example Code:
+//
+//	keySet := jwt.NewRemoteKeySet("https://example.com/.well-known/jwks.json",
+//	  jwt.WithRefreshInterval(15*time.Minute))
+//	verifiedToken, err := keySet.Verify(token)
+func NewRemoteKeySet(url string, opts ...RemoteKeySetOption) *RemoteKeySet {
+	r := &RemoteKeySet{
+		url:             url,
+		client:          &http.Client{Timeout: 10 * time.Second},
+		refreshInterval: DefaultRemoteKeySetRefreshInterval,
+		rateLimit:       DefaultRemoteKeySetRefreshRateLimit,
+		clock:           Clock,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Key returns the public key and its algorithm based on the given "kid".
+// It fetches the JWKS document if it's not fetched yet, it's stale
+// or it does not contain the "kid" (rate limited).
+// It returns `ErrUnknownKid` if the key set does not contain a key of that id.
+func (r *RemoteKeySet) Key(kid string) (PublicKey, Alg, error) {
+	return r.KeyContext(context.Background(), kid)
+}
+
+// KeyContext same as `Key` but it accepts a context
+// which cancels the fetch of the JWKS document.
+func (r *RemoteKeySet) KeyContext(ctx context.Context, kid string) (PublicKey, Alg, error) {
+	keySet, err := r.keySetFor(ctx, kid)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pub, alg, ok := keySet.Key(kid)
+	if !ok {
+		return nil, nil, ErrUnknownKid
+	}
+
+	return pub, alg, nil
+}
+
+// ValidateHeader validates the given json header value (base64 decoded) based on the remote keys.
+// RemoteKeySet structure completes the `HeaderValidator` interface.
+func (r *RemoteKeySet) ValidateHeader(alg string, headerDecoded []byte) (Alg, PublicKey, InjectFunc, error) {
+	return r.validateHeader(context.Background(), alg, headerDecoded)
+}
+
+func (r *RemoteKeySet) validateHeader(ctx context.Context, alg string, headerDecoded []byte) (Alg, PublicKey, InjectFunc, error) {
+	var h HeaderWithKid
+	if err := Unmarshal(headerDecoded, &h); err != nil {
+		return nil, nil, nil, err
+	}
+
+	if h.Kid == "" {
+		return nil, nil, nil, ErrEmptyKid
+	}
+
+	keySet, err := r.keySetFor(ctx, h.Kid)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return keySet.ValidateHeader(alg, headerDecoded)
+}
+
+// Verify verifies the "token" using the public key selected by its "kid" header field.
+// See `Verify` package-level function for more.
+func (r *RemoteKeySet) Verify(token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
+	return r.VerifyContext(context.Background(), token, validators...)
+}
+
+// VerifyContext same as `Verify` but it accepts a context,
+// e.g. the request's one, which cancels the fetch of the JWKS document.
+func (r *RemoteKeySet) VerifyContext(ctx context.Context, token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
+	validateHeader := func(alg string, headerDecoded []byte) (Alg, PublicKey, InjectFunc, error) {
+		return r.validateHeader(ctx, alg, headerDecoded)
+	}
+
+	return VerifyWithHeaderValidator(nil, nil, token, validateHeader, validators...)
+}
+
+// Refresh fetches the JWKS document immediately, it does not respect the rate limit.
+func (r *RemoteKeySet) Refresh() error {
+	return r.RefreshContext(context.Background())
+}
+
+// RefreshContext same as `Refresh` but it accepts a context
+// which cancels the fetch of the JWKS document.
+func (r *RemoteKeySet) RefreshContext(ctx context.Context) error {
+	r.mu.RLock()
+	generation := r.generation
+	r.mu.RUnlock()
+
+	_, err := r.refresh(ctx, generation, true)
+	return err
+}
+
+// keySetFor returns the cached key set if it's fresh and it contains the "kid",
+// otherwise it fetches the JWKS document again.
+func (r *RemoteKeySet) keySetFor(ctx context.Context, kid string) (*KeySet, error) {
+	r.mu.RLock()
+	keySet, fetchedAt, generation := r.keySet, r.fetchedAt, r.generation
+	r.mu.RUnlock()
+
+	if keySet != nil && r.clock().Sub(fetchedAt) < r.refreshInterval {
+		if _, _, ok := keySet.Key(kid); ok {
+			return keySet, nil
+		}
+	}
+
+	return r.refresh(ctx, generation, false)
+}
+
+// refresh fetches the JWKS document, unless another goroutine
+// fetched it while waiting (the "generation" changed) or the rate limit is reached.
+// On fetch failure the previous key set, if any, is still in use.
+// A fetch canceled by the caller's context is not recorded as an attempt,
+// so it does not count to the rate limit.
+func (r *RemoteKeySet) refresh(ctx context.Context, generation uint64, force bool) (*KeySet, error) {
+	r.refreshMu.Lock()
+	defer r.refreshMu.Unlock()
+
+	r.mu.RLock()
+	keySet, attemptedAt, current, lastErr := r.keySet, r.attemptedAt, r.generation, r.err
+	r.mu.RUnlock()
+
+	if !force && current > 0 {
+		if current != generation || r.clock().Sub(attemptedAt) < r.rateLimit {
+			if keySet == nil {
+				return nil, lastErr
+			}
+
+			return keySet, nil
+		}
+	}
+
+	newKeySet, err := r.fetch(ctx)
+	if err != nil && ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.generation++
+	r.attemptedAt = r.clock()
+	r.err = err
+	if err == nil {
+		r.keySet = newKeySet
+		r.fetchedAt = r.attemptedAt
+	}
+	r.mu.Unlock()
+
+	if err != nil {
+		if keySet != nil && !force {
+			return keySet, nil
+		}
+
+		return nil, err
+	}
+
+	return newKeySet, nil
+}
+
+func (r *RemoteKeySet) fetch(ctx context.Context) (*KeySet, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("jwt: remote jwks: %w", err)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("jwt: remote jwks: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jwt: remote jwks: %s: unexpected status: %s", r.url, resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxJWKSSize))
+	if err != nil {
+		return nil, fmt.Errorf("jwt: remote jwks: read: %w", err)
+	}
+
+	return ParseJWKS(body)
+}
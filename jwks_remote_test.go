@@ -0,0 +1,233 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testECJWKS(t *testing.T, kids ...string) (*ecdsa.PrivateKey, string) {
+	t.Helper()
+
+	privateKey, err := LoadPrivateKeyECDSA("./_testfiles/ecdsa_private_key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicKey := &privateKey.PublicKey
+	size := (publicKey.Curve.Params().BitSize + 7) / 8
+	x := Base64Encode(publicKey.X.FillBytes(make([]byte, size)))
+	y := Base64Encode(publicKey.Y.FillBytes(make([]byte, size)))
+
+	jwks := `{"keys":[`
+	for i, kid := range kids {
+		if i > 0 {
+			jwks += ","
+		}
+		jwks += fmt.Sprintf(`{"kty":"EC","crv":"P-256","kid":%q,"x":"%s","y":"%s"}`, kid, x, y)
+	}
+	jwks += `]}`
+
+	return privateKey, jwks
+}
+
+type testJWKSServer struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	body   string
+	status int
+	hits   int32
+	delay  time.Duration
+}
+
+func newTestJWKSServer(body string) *testJWKSServer {
+	s := &testJWKSServer{body: body, status: http.StatusOK}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&s.hits, 1)
+
+		s.mu.Lock()
+		body, status, delay := s.body, s.status, s.delay
+		s.mu.Unlock()
+
+		time.Sleep(delay)
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+
+	return s
+}
+
+func (s *testJWKSServer) set(body string, status int) {
+	s.mu.Lock()
+	s.body, s.status = body, status
+	s.mu.Unlock()
+}
+
+func TestRemoteKeySet(t *testing.T) {
+	privateKey, jwks := testECJWKS(t, "ec-1")
+	srv := newTestJWKSServer(jwks)
+	defer srv.Close()
+
+	now := time.Now()
+	keySet := NewRemoteKeySet(srv.URL, WithRefreshInterval(time.Hour), WithRefreshRateLimit(time.Minute))
+	keySet.clock = func() time.Time { return now }
+
+	token, err := Sign(ES256, privateKey, Map{"username": "kataras"}, WithKID("ec-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err = keySet.Verify(token); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if expected, got := int32(1), atomic.LoadInt32(&srv.hits); expected != got {
+		t.Fatalf("expected cached key set: %d fetch but got: %d", expected, got)
+	}
+
+	// Unknown kid: rate limited, the last fetch was just now.
+	for i := 0; i < 3; i++ {
+		if _, _, err = keySet.Key("ec-2"); err != ErrUnknownKid {
+			t.Fatalf("expected error: ErrUnknownKid but got: %v", err)
+		}
+	}
+
+	if expected, got := int32(1), atomic.LoadInt32(&srv.hits); expected != got {
+		t.Fatalf("expected rate limited: %d fetch but got: %d", expected, got)
+	}
+
+	// Unknown kid after the rate limit: fetched once.
+	now = now.Add(2 * time.Minute)
+	for i := 0; i < 3; i++ {
+		if _, _, err = keySet.Key("ec-2"); err != ErrUnknownKid {
+			t.Fatalf("expected error: ErrUnknownKid but got: %v", err)
+		}
+	}
+
+	if expected, got := int32(2), atomic.LoadInt32(&srv.hits); expected != got {
+		t.Fatalf("expected rate limited: %d fetches but got: %d", expected, got)
+	}
+
+	// The provider rotated its keys, the new kid is fetched after the rate limit.
+	_, jwks = testECJWKS(t, "ec-1", "ec-2")
+	srv.set(jwks, http.StatusOK)
+	now = now.Add(2 * time.Minute)
+
+	if _, _, err = keySet.Key("ec-2"); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := int32(3), atomic.LoadInt32(&srv.hits); expected != got {
+		t.Fatalf("expected: %d fetches but got: %d", expected, got)
+	}
+
+	// Stale key set with a failing server: keep using the previous keys.
+	srv.set("", http.StatusInternalServerError)
+	now = now.Add(2 * time.Hour)
+
+	if _, err = keySet.Verify(token); err != nil {
+		t.Fatalf("expected previous keys to be used but got: %v", err)
+	}
+
+	if expected, got := int32(4), atomic.LoadInt32(&srv.hits); expected != got {
+		t.Fatalf("expected stale refresh: %d fetches but got: %d", expected, got)
+	}
+
+	if err = keySet.Refresh(); err == nil {
+		t.Fatalf("expected refresh error")
+	}
+}
+
+func TestRemoteKeySetConcurrentRefresh(t *testing.T) {
+	_, jwks := testECJWKS(t, "ec-1")
+	srv := newTestJWKSServer(jwks)
+	srv.delay = 50 * time.Millisecond
+	defer srv.Close()
+
+	keySet := NewRemoteKeySet(srv.URL)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, err := keySet.Key("ec-1"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if expected, got := int32(1), atomic.LoadInt32(&srv.hits); expected != got {
+		t.Fatalf("expected a single fetch but got: %d", got)
+	}
+}
+
+func TestRemoteKeySetFetchError(t *testing.T) {
+	srv := newTestJWKSServer("")
+	srv.status = http.StatusNotFound
+	defer srv.Close()
+
+	keySet := NewRemoteKeySet(srv.URL)
+
+	for i := 0; i < 3; i++ {
+		if _, _, err := keySet.Key("ec-1"); err == nil {
+			t.Fatalf("expected fetch error")
+		}
+	}
+
+	if expected, got := int32(1), atomic.LoadInt32(&srv.hits); expected != got {
+		t.Fatalf("expected rate limited: %d fetch but got: %d", expected, got)
+	}
+}
+
+func TestRemoteKeySetContext(t *testing.T) {
+	privateKey, jwks := testECJWKS(t, "ec-1")
+	srv := newTestJWKSServer(jwks)
+	srv.delay = 2 * time.Second
+	defer srv.Close()
+
+	keySet := NewRemoteKeySet(srv.URL)
+
+	token, err := Sign(ES256, privateKey, Map{"username": "kataras"}, WithKID("ec-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err = keySet.VerifyContext(ctx, token); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected error: context.DeadlineExceeded but got: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected the fetch to be aborted promptly but it took: %s", elapsed)
+	}
+
+	// The canceled fetch should not count to the rate limit.
+	srv.mu.Lock()
+	srv.delay = 0
+	srv.mu.Unlock()
+
+	if _, err = keySet.VerifyContext(context.Background(), token); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if err = keySet.RefreshContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected error: context.Canceled but got: %v", err)
+	}
+}
@@ -0,0 +1,278 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+// Test vectors from RFC 7517, Appendix A.1.
+const testJWKS = `{"keys":
+  [
+    {"kty":"EC",
+     "crv":"P-256",
+     "x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4",
+     "y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM",
+     "use":"enc",
+     "kid":"1"},
+
+    {"kty":"RSA",
+     "n": "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+     "e":"AQAB",
+     "alg":"RS256",
+     "kid":"2011-04-29"},
+
+    {"kty":"oct",
+     "k":"AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr_T-1qS0gZH75aKtMN3Yj0iPS4hcgUuTwjAzZr1Z9CAow",
+     "kid":"HMAC key"}
+  ]
+}`
+
+func TestParseJWKS(t *testing.T) {
+	keySet, err := ParseJWKS([]byte(testJWKS))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pub, alg, ok := keySet.Key("1")
+	if !ok {
+		t.Fatalf("expected EC key to be found")
+	}
+
+	if alg != ES256 {
+		t.Fatalf("expected EC key algorithm: ES256 but got: %s", alg.Name())
+	}
+
+	ecKey, ok := pub.(*ecdsa.PublicKey)
+	if !ok {
+		t.Fatalf("expected *ecdsa.PublicKey but got: %T", pub)
+	}
+
+	if !ecKey.Curve.IsOnCurve(ecKey.X, ecKey.Y) {
+		t.Fatalf("expected EC key to be on the P-256 curve")
+	}
+
+	pub, alg, ok = keySet.Key("2011-04-29")
+	if !ok {
+		t.Fatalf("expected RSA key to be found")
+	}
+
+	if alg != RS256 {
+		t.Fatalf("expected RSA key algorithm: RS256 but got: %s", alg.Name())
+	}
+
+	rsaKey, ok := pub.(*rsa.PublicKey)
+	if !ok {
+		t.Fatalf("expected *rsa.PublicKey but got: %T", pub)
+	}
+
+	if expected, got := 65537, rsaKey.E; expected != got {
+		t.Fatalf("expected RSA exponent: %d but got: %d", expected, got)
+	}
+
+	if expected, got := 2048, rsaKey.N.BitLen(); expected != got {
+		t.Fatalf("expected RSA modulus size: %d but got: %d", expected, got)
+	}
+
+	// Symmetric keys are not supported and should be ignored.
+	if _, _, ok = keySet.Key("HMAC key"); ok {
+		t.Fatalf("expected oct key to be ignored")
+	}
+
+	if _, _, ok = keySet.Key("unknown"); ok {
+		t.Fatalf("expected unknown kid to not be found")
+	}
+}
+
+func TestParseJWKSInvalid(t *testing.T) {
+	var tests = []string{
+		// not on curve.
+		`{"keys":[{"kty":"EC","crv":"P-256","kid":"1","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4"}]}`,
+		// missing modulus.
+		`{"keys":[{"kty":"RSA","kid":"1","e":"AQAB"}]}`,
+		// invalid base64.
+		`{"keys":[{"kty":"RSA","kid":"1","n":"$$","e":"AQAB"}]}`,
+		// invalid Ed25519 size.
+		`{"keys":[{"kty":"OKP","crv":"Ed25519","kid":"1","x":"AQAB"}]}`,
+		// duplicated kid.
+		`{"keys":[{"kty":"RSA","kid":"1","n":"AQAB","e":"AQAB"},{"kty":"RSA","kid":"1","n":"AQAB","e":"AQAB"}]}`,
+		// alg of a different key type.
+		`{"keys":[{"kty":"RSA","kid":"1","alg":"none","n":"AQAB","e":"AQAB"}]}`,
+		`{"keys":[{"kty":"RSA","kid":"1","alg":"NONE","n":"AQAB","e":"AQAB"}]}`,
+		`{"keys":[{"kty":"RSA","kid":"1","alg":"HS256","n":"AQAB","e":"AQAB"}]}`,
+		`{"keys":[{"kty":"RSA","kid":"1","alg":"ES256","n":"AQAB","e":"AQAB"}]}`,
+		`{"keys":[{"kty":"EC","crv":"P-256","kid":"1","alg":"ES384","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM"}]}`,
+		`{"keys":[{"kty":"EC","crv":"P-256","kid":"1","alg":"RS256","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM"}]}`,
+		`{"keys":[{"kty":"OKP","crv":"Ed25519","kid":"1","alg":"HS512","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}]}`,
+	}
+
+	for i, tt := range tests {
+		if _, err := ParseJWKS([]byte(tt)); !errors.Is(err, ErrInvalidJWK) {
+			t.Fatalf("[%d] expected error: ErrInvalidJWK but got: %v", i, err)
+		}
+	}
+
+	if _, err := ParseJWKS([]byte(`{"keys":`)); err == nil {
+		t.Fatalf("expected error on malformed JSON")
+	}
+
+	// A matching alg is selected and an unknown one is skipped.
+	keySet, err := ParseJWKS([]byte(`{"keys":[{"kty":"RSA","kid":"1","alg":"PS256","n":"AQAB","e":"AQAB"},{"kty":"RSA","kid":"2","alg":"RSA-OAEP","n":"AQAB","e":"AQAB"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, alg, ok := keySet.Key("1"); !ok || alg != PS256 {
+		t.Fatalf("expected the PS256 key")
+	}
+
+	if _, _, ok := keySet.Key("2"); ok {
+		t.Fatalf("expected the encryption key to be skipped")
+	}
+
+	// The "kid" is optional, the first key without one is kept.
+	keySet, err = ParseJWKS([]byte(`{"keys":[{"kty":"RSA","alg":"PS256","n":"AQAB","e":"AQAB"},{"kty":"RSA","alg":"RS512","n":"AQAB","e":"AQAB"},{"kty":"RSA","kid":"1","n":"AQAB","e":"AQAB"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, alg, ok := keySet.Key(""); !ok || alg != PS256 {
+		t.Fatalf("expected the first key without a kid")
+	}
+
+	if _, alg, ok := keySet.Key("1"); !ok || alg != RS256 {
+		t.Fatalf("expected the RS256 key")
+	}
+}
+
+func TestKeySetValidateHeader(t *testing.T) {
+	privateKey, err := LoadPrivateKeyECDSA("./_testfiles/ecdsa_private_key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicKey := &privateKey.PublicKey
+	size := (publicKey.Curve.Params().BitSize + 7) / 8
+	jwks := fmt.Sprintf(`{"keys":[{"kty":"EC","crv":"P-256","kid":"ec-1","x":"%s","y":"%s"}]}`,
+		Base64Encode(publicKey.X.FillBytes(make([]byte, size))),
+		Base64Encode(publicKey.Y.FillBytes(make([]byte, size))))
+
+	keySet, err := ParseJWKS([]byte(jwks))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := Sign(ES256, privateKey, Map{"username": "kataras"}, WithKID("ec-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := VerifyWithHeaderValidator(nil, nil, token, keySet.ValidateHeader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "ec-1", verifiedToken.Kid(); expected != got {
+		t.Fatalf("expected kid: %q but got: %q", expected, got)
+	}
+
+	token, err = Sign(ES256, privateKey, Map{"username": "kataras"}, WithKID("ec-2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = VerifyWithHeaderValidator(nil, nil, token, keySet.ValidateHeader); err != ErrUnknownKid {
+		t.Fatalf("expected error: ErrUnknownKid but got: %v", err)
+	}
+}
+
+func TestMarshalJWKS(t *testing.T) {
+	rsaPrivateKey, err := GenerateRSAKeys(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p256PrivateKey, err := GenerateECDSAKeys(elliptic.P256())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p521PrivateKey, err := GenerateECDSAKeys(elliptic.P521())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ed25519PrivateKey, err := GenerateEdDSAKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := map[string]PublicKey{
+		"rsa":     &rsaPrivateKey.PublicKey,
+		"p256":    &p256PrivateKey.PublicKey,
+		"p521":    &p521PrivateKey.PublicKey,
+		"ed25519": ed25519PrivateKey.Public(),
+	}
+
+	b, err := MarshalJWKS(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Stable output.
+	again, err := MarshalJWKS(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != string(again) {
+		t.Fatalf("expected a stable output but got:\n%s\n%s", b, again)
+	}
+
+	keySet, err := ParseJWKS(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		kid string
+		alg Alg
+	}{
+		{"rsa", RS256},
+		{"p256", ES256},
+		{"p521", ES512},
+		{"ed25519", EdDSA},
+	}
+
+	for _, tt := range tests {
+		pub, alg, ok := keySet.Key(tt.kid)
+		if !ok {
+			t.Fatalf("%s: expected key to be found", tt.kid)
+		}
+
+		if alg != tt.alg {
+			t.Fatalf("%s: expected algorithm: %s but got: %s", tt.kid, tt.alg.Name(), alg.Name())
+		}
+
+		if !pub.(interface{ Equal(crypto.PublicKey) bool }).Equal(keys[tt.kid]) {
+			t.Fatalf("%s: expected the parsed key to be equal to the original one", tt.kid)
+		}
+	}
+
+	if _, err = MarshalJWKS(map[string]PublicKey{"secret": testSecret}); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected error: ErrInvalidKey but got: %v", err)
+	}
+
+	if _, err = MarshalJWKS(map[string]PublicKey{"private": rsaPrivateKey}); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected error: ErrInvalidKey but got: %v", err)
+	}
+
+	if _, err = NewJWK("secp256k1", &ecdsa.PublicKey{Curve: newTestSecp256k1(), X: big.NewInt(1), Y: big.NewInt(1)}); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected error: ErrInvalidKey but got: %v", err)
+	}
+}
@@ -12,9 +12,17 @@ import (
 type Map = map[string]interface{}
 
 // Clock is used to validate tokens expiration if the "exp" (expiration) exists in the payload.
+// It is also used by the `MaxAge` sign option to set the "iat" and "exp" claims.
 // It can be overridden to use any other time value, useful for testing.
 //
+// Note that modifying it is not safe while tokens are signed or verified concurrently,
+// e.g. by parallel tests. Prefer the per-call `WithClock` verify option
+// and `WithSignClock` sign option instead, which override this package-level variable
+// for that verification or signing only.
+//
 // Usage: now := Clock()
+//
+// Deprecated: use the WithClock verify option and the WithSignClock sign option instead.
 var Clock = time.Now
 
 // CompareHeader is the function which compares and validates
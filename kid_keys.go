@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
-	"strings"
 	"time"
 )
 
@@ -73,7 +72,9 @@ type (
 		//  * ES256
 		//  * ES384
 		//  * ES512
+		//  * ES256K
 		//  * EdDSA
+		//  * or any custom one registered through `RegisterAlg`.
 		Alg     string `json:"alg" yaml:"Alg" toml:"Alg" ini:"alg"`
 		Private string `json:"private" yaml:"Private" toml:"Private" ini:"private"`
 		Public  string `json:"public" yaml:"Public" toml:"Public" ini:"public"`
@@ -109,13 +110,9 @@ func (c KeysConfiguration) Load() (Keys, error) {
 	parsedKeys := make(Keys, len(c))
 
 	for _, entry := range c {
-		alg := RS256
-
-		for _, algo := range allAlgs {
-			if strings.EqualFold(algo.Name(), entry.Alg) {
-				alg = algo
-				break
-			}
+		alg, ok := findAlg(entry.Alg)
+		if !ok {
+			alg = RS256
 		}
 
 		p := &Key{
@@ -10,11 +10,12 @@ import (
 // this "leeway" and the token's "exp" one is expected to pass instead (now+leeway > exp).
 // Example of use case: disallow tokens that are going to be expired in 3 seconds from now,
 // this is useful to make sure that the token is valid when the when the user fires a database call for example.
+// The "now" is the current time of the verification, see `WithClock`.
 func Leeway(leeway time.Duration) TokenValidatorFunc {
 	return func(_ []byte, standardClaims Claims, err error) error {
 		if err == nil {
-			if Clock().Add(leeway).Round(time.Second).Unix() > standardClaims.Expiry {
-				return ErrExpired
+			if now := standardClaims.currentTime(); now.Add(leeway).Round(time.Second).Unix() > standardClaims.Expiry {
+				return newValidationError(ErrExpired, "exp", standardClaims.Expiry, now, leeway)
 			}
 		}
 
@@ -24,14 +25,15 @@ func Leeway(leeway time.Duration) TokenValidatorFunc {
 
 // Future adds a validation for the "iat" claim.
 // It checks if the token was issued in the future based on now+dur < iat.
+// The "now" is the current time of the verification, see `WithClock`.
 //
 // Example of use case: allow tokens that are going to be issued in the future,
 // for example a token that is going to be issued in 10 seconds from now.
 func Future(dur time.Duration) TokenValidatorFunc {
 	return func(_ []byte, standardClaims Claims, err error) error {
 		if errors.Is(err, ErrIssuedInTheFuture) {
-			if Clock().Add(dur).Round(time.Second).Unix() < standardClaims.IssuedAt {
-				return ErrIssuedInTheFuture
+			if now := standardClaims.currentTime(); now.Add(dur).Round(time.Second).Unix() < standardClaims.IssuedAt {
+				return newValidationError(ErrIssuedInTheFuture, "iat", standardClaims.IssuedAt, now, dur)
 			}
 
 			return nil
@@ -40,3 +42,89 @@ func Future(dur time.Duration) TokenValidatorFunc {
 		return err
 	}
 }
+
+// WithLeeway is a VerifyOption which allows a clock skew tolerance
+// between the issuer and the verifier. The "exp", "nbf" and "iat" checks
+// are all relaxed by the given "leeway" duration, e.g. a token
+// that was expired one second ago is still valid with a leeway of 5 seconds.
+//
+// Note that this is the opposite of what the `Leeway` token validator does.
+//
+// Usage:
+//
+//	verifiedToken, err := Verify(..., WithLeeway(5*time.Second))
+func WithLeeway(leeway time.Duration) VerifyOption {
+	return func(c *verifyConfig) {
+		if leeway < 0 {
+			leeway = -leeway
+		}
+
+		c.leeway = leeway
+	}
+}
+
+// WithClock is a VerifyOption which sets the current time source of a single verification,
+// the "exp", "nbf" and "iat" checks are performed against its result instead of the `Clock` package-level variable.
+// Unlike modifying `Clock`, it is safe for concurrent use, e.g. each issuer of a service
+// or each parallel test can pass a different clock (and leeway).
+// The `Leeway` and `Future` token validators and the `VerifiedToken.TimeLeft` method use it too.
+// See the `WithSignClock` option for the sign functions.
+//
+// Usage:
+//
+//	verifiedToken, err := Verify(..., WithClock(func() time.Time { return frozenTime }))
+func WithClock(clock func() time.Time) VerifyOption {
+	return func(c *verifyConfig) {
+		c.clock = clock
+	}
+}
+
+// WithoutIssuedAtCheck is a VerifyOption which disables the "iat" check,
+// a token issued in the future (e.g. by an issuer's clock which runs ahead)
+// does not fail with `ErrIssuedInTheFuture`. The "exp" and "nbf" checks are not affected.
+// See `WithLeeway` to relax all the time checks instead.
+//
+// Usage:
+//
+//	verifiedToken, err := Verify(..., WithoutIssuedAtCheck())
+func WithoutIssuedAtCheck() VerifyOption {
+	return func(c *verifyConfig) {
+		c.skipIssuedAt = true
+	}
+}
+
+// WithReturnOnExpired is a VerifyOption which makes the verify functions
+// to return the token along with the error when the token is expired,
+// e.g. to read the "sub" claim of an expired token and decide whether to offer a re-authentication.
+// The signature is still verified and the rest of the validations still run
+// (e.g. `WithBlocklist`, `WithRequiredClaims` and the token validators):
+// any other failure, including `ErrInvalidSignature`, returns a nil token along with that error.
+//
+// Usage:
+//
+//	verifiedToken, err := Verify(..., WithReturnOnExpired())
+//	if errors.Is(err, ErrExpired) && verifiedToken != nil {
+//	  subject := verifiedToken.StandardClaims.Subject
+//	}
+func WithReturnOnExpired() VerifyOption {
+	return func(c *verifyConfig) {
+		c.returnOnExpired = true
+	}
+}
+
+// isExpiredOnly reports whether "err" is an ErrExpired one,
+// or a list of ValidationErrors of ErrExpired ones.
+func isExpiredOnly(err error) bool {
+	errs, ok := err.(ValidationErrors)
+	if !ok {
+		return errors.Is(err, ErrExpired)
+	}
+
+	for _, err := range errs {
+		if !errors.Is(err, ErrExpired) {
+			return false
+		}
+	}
+
+	return len(errs) > 0
+}
@@ -1,6 +1,8 @@
 package jwt
 
 import (
+	"errors"
+	"sync"
 	"testing"
 	"time"
 )
@@ -10,7 +12,7 @@ func TestLeeway(t *testing.T) {
 	err := l.ValidateToken(nil, Claims{
 		Expiry: Clock().Add(8 * time.Second).Unix(),
 	}, nil)
-	if err != ErrExpired {
+	if !errors.Is(err, ErrExpired) {
 		t.Fatalf("expected ErrExpired error but got: %v", err)
 	}
 
@@ -20,3 +22,223 @@ func TestLeeway(t *testing.T) {
 		t.Fatalf("expected to respect previous error 'ErrInvalidKey' but got: %v", err)
 	}
 }
+
+func TestWithLeeway(t *testing.T) {
+	now := Clock()
+
+	var tests = []struct {
+		claims      Claims
+		expectedErr error
+	}{
+		{Claims{Expiry: now.Add(-1 * time.Second).Unix()}, nil},
+		{Claims{Expiry: now.Add(-10 * time.Second).Unix()}, ErrExpired},
+		{Claims{NotBefore: now.Add(3 * time.Second).Unix()}, nil},
+		{Claims{NotBefore: now.Add(10 * time.Second).Unix()}, ErrNotValidYet},
+		{Claims{IssuedAt: now.Add(3 * time.Second).Unix()}, nil},
+		{Claims{IssuedAt: now.Add(10 * time.Second).Unix()}, ErrIssuedInTheFuture},
+	}
+
+	for i, tt := range tests {
+		token, err := Sign(testAlg, testSecret, tt.claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Without leeway it should always fail.
+		if _, err = Verify(testAlg, testSecret, token); err == nil {
+			t.Fatalf("[%d] expected error without leeway", i)
+		}
+
+		if _, err = Verify(testAlg, testSecret, token, WithLeeway(5*time.Second)); !errors.Is(err, tt.expectedErr) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, tt.expectedErr, err)
+		}
+	}
+
+	// Test that a leeway option does not affect other verifications.
+	token, err := Sign(testAlg, testSecret, Claims{Expiry: now.Add(-2 * time.Second).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, WithLeeway(5*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token); !errors.Is(err, ErrExpired) {
+		t.Fatalf("expected error: ErrExpired but got: %v", err)
+	}
+}
+
+// The per-call clock does not change the original signatures.
+var (
+	_ TokenValidatorFunc = Leeway(time.Second)
+	_ TokenValidatorFunc = Future(time.Second)
+	_ SignOptionFunc     = MaxAge(time.Minute)
+)
+
+func TestLeewayAndFutureWithClock(t *testing.T) {
+	past := time.Date(2020, 10, 26, 1, 1, 1, 0, time.UTC)
+
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"}, MaxAge(time.Minute), WithSignClock(func() time.Time {
+		return past.Add(10 * time.Second)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		leeway time.Duration
+		err    error
+	}{
+		{30 * time.Second, nil},
+		{2 * time.Minute, ErrExpired},
+	}
+
+	// Many concurrent verifications of different leeways,
+	// the "iat" and "exp" are both checked against the frozen clock,
+	// run with -race to detect shared state.
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		for _, tt := range tests {
+			wg.Add(1)
+			go func(leeway time.Duration, expected error) {
+				defer wg.Done()
+
+				clock := func() time.Time { return past }
+				_, err := Verify(testAlg, testSecret, token, Future(time.Minute), Leeway(leeway), WithClock(clock))
+				if !errors.Is(err, expected) {
+					t.Errorf("leeway: %s: expected error: %v but got: %v", leeway, expected, err)
+				}
+			}(tt.leeway, tt.err)
+		}
+	}
+	wg.Wait()
+
+	// Without the Future validator the "iat" is in the future of the clock.
+	if _, err = Verify(testAlg, testSecret, token, WithClock(func() time.Time { return past }), Leeway(time.Second)); !errors.Is(err, ErrIssuedInTheFuture) {
+		t.Fatalf("expected error: ErrIssuedInTheFuture but got: %v", err)
+	}
+}
+
+func TestWithoutIssuedAtCheck(t *testing.T) {
+	now := Clock()
+
+	var tests = []struct {
+		name    string
+		claims  Claims
+		options []TokenValidator
+		err     error
+	}{
+		{"enabled", Claims{IssuedAt: now.Add(time.Minute).Unix()}, nil, ErrIssuedInTheFuture},
+		{"leeway", Claims{IssuedAt: now.Add(time.Minute).Unix()}, []TokenValidator{WithLeeway(2 * time.Minute)}, nil},
+		{"short leeway", Claims{IssuedAt: now.Add(time.Minute).Unix()}, []TokenValidator{WithLeeway(time.Second)}, ErrIssuedInTheFuture},
+		{"disabled", Claims{IssuedAt: now.Add(time.Hour).Unix()}, []TokenValidator{WithoutIssuedAtCheck()}, nil},
+		{"disabled all errors", Claims{IssuedAt: now.Add(time.Hour).Unix()}, []TokenValidator{WithoutIssuedAtCheck(), WithAllValidationErrors()}, nil},
+		{"disabled time only", Claims{IssuedAt: now.Add(time.Hour).Unix()}, []TokenValidator{WithoutIssuedAtCheck(), WithTimeOnlyValidation()}, nil},
+		{"disabled expired", Claims{IssuedAt: now.Add(time.Hour).Unix(), Expiry: now.Add(-time.Minute).Unix()}, []TokenValidator{WithoutIssuedAtCheck()}, ErrExpired},
+		{"disabled not valid yet", Claims{NotBefore: now.Add(time.Hour).Unix()}, []TokenValidator{WithoutIssuedAtCheck()}, ErrNotValidYet},
+	}
+
+	for _, tt := range tests {
+		token, err := Sign(testAlg, testSecret, tt.claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = Verify(testAlg, testSecret, token, tt.options...); !errors.Is(err, tt.err) {
+			t.Fatalf("[%s] expected error: %v but got: %v", tt.name, tt.err, err)
+		}
+	}
+}
+
+func TestWithReturnOnExpired(t *testing.T) {
+	claims := Claims{Subject: "kataras", Expiry: Clock().Add(-time.Minute).Unix()}
+	token, err := Sign(testAlg, testSecret, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token)
+	if !errors.Is(err, ErrExpired) || verifiedToken != nil {
+		t.Fatalf("expected error: ErrExpired and a nil token by default but got: %v, %v", err, verifiedToken)
+	}
+
+	for _, options := range [][]TokenValidator{
+		{WithReturnOnExpired()},
+		{WithReturnOnExpired(), WithAllValidationErrors()},
+	} {
+		verifiedToken, err = Verify(testAlg, testSecret, token, options...)
+		if !errors.Is(err, ErrExpired) {
+			t.Fatalf("expected error: ErrExpired but got: %v", err)
+		}
+
+		if verifiedToken == nil {
+			t.Fatalf("expected the token to be returned")
+		}
+
+		if expected, got := "kataras", verifiedToken.StandardClaims.Subject; expected != got {
+			t.Fatalf("expected subject: %q but got: %q", expected, got)
+		}
+	}
+
+	// The signature is still enforced.
+	verifiedToken, err = Verify(testAlg, []byte("other"), token, WithReturnOnExpired())
+	if !errors.Is(err, ErrInvalidSignature) || verifiedToken != nil {
+		t.Fatalf("expected error: ErrInvalidSignature and a nil token but got: %v, %v", err, verifiedToken)
+	}
+
+	// Other validation failures are not relaxed.
+	verifiedToken, err = Verify(testAlg, testSecret, token, WithReturnOnExpired(), WithAllValidationErrors(), Expected{Issuer: "my-app"})
+	if !errors.Is(err, ErrExpired) || !errors.Is(err, ErrExpected) || verifiedToken != nil {
+		t.Fatalf("expected errors: ErrExpired, ErrExpected and a nil token but got: %v, %v", err, verifiedToken)
+	}
+
+	// The rest of the checks and validators run on an expired token too.
+	expiredToken, err := Sign(testAlg, testSecret, Claims{Subject: "kataras", Issuer: "my-app", ID: "token-1", Expiry: claims.Expiry})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blocklist := NewMemoryBlocklist()
+	defer blocklist.Close()
+
+	if err = blocklist.Set("token-1", Clock().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		name     string
+		options  []TokenValidator
+		expected error
+	}{
+		{"blocklisted", []TokenValidator{WithBlocklist(blocklist)}, ErrBlocked},
+		{"wrong issuer", []TokenValidator{WithExpectedIssuer("other-app")}, ErrInvalidIssuer},
+		{"missing required claim", []TokenValidator{WithRequiredClaims("username")}, ErrMissingRequiredClaim},
+	}
+
+	for _, tt := range tests {
+		verifiedToken, err = Verify(testAlg, testSecret, expiredToken, append(tt.options, WithReturnOnExpired())...)
+		if !errors.Is(err, tt.expected) || verifiedToken != nil {
+			t.Fatalf("[%s] expected error: %v and a nil token but got: %v, %v", tt.name, tt.expected, err, verifiedToken)
+		}
+	}
+
+	// Passing checks return the expired token.
+	emptyBlocklist := NewMemoryBlocklist()
+	defer emptyBlocklist.Close()
+
+	verifiedToken, err = Verify(testAlg, testSecret, expiredToken, WithReturnOnExpired(), WithBlocklist(emptyBlocklist), WithExpectedIssuer("my-app"))
+	if !errors.Is(err, ErrExpired) || verifiedToken == nil {
+		t.Fatalf("expected error: ErrExpired and the token but got: %v, %v", err, verifiedToken)
+	}
+
+	// A valid token is returned without an error.
+	token, err = Sign(testAlg, testSecret, Claims{Subject: "kataras"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if verifiedToken, err = Verify(testAlg, testSecret, token, WithReturnOnExpired()); err != nil || verifiedToken == nil {
+		t.Fatalf("expected a valid token but got: %v", err)
+	}
+}
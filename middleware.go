@@ -0,0 +1,325 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type middlewareContextKey uint8
+
+const (
+	verifiedTokenContextKey middlewareContextKey = iota
+	blocklistContextKey
+)
+
+// TokenExtractor extracts a token from an HTTP request,
+// it reports false if the request does not contain a token.
+// See `FromHeader`, `FromHeaderNamed`, `FromWebSocketProtocol`, `FromCookie` and `FromQuery`.
+//
+// A TokenExtractor can be passed to the `Middleware` function,
+// mixed with any other TokenValidator.
+type TokenExtractor func(r *http.Request) (string, bool)
+
+var _ TokenValidator = TokenExtractor(nil)
+
+// ValidateToken completes the TokenValidator interface,
+// so extractors can be passed to the `Middleware` function.
+// It respects the previous error.
+func (e TokenExtractor) ValidateToken(_ []byte, _ Claims, err error) error {
+	return err
+}
+
+// FromHeader returns a TokenExtractor which extracts the token
+// of the "Authorization: Bearer <token>" request header.
+// This is the default extractor of the `Middleware`.
+func FromHeader() TokenExtractor {
+	return fromAuthorizationHeader
+}
+
+// FromHeaderNamed returns a TokenExtractor which extracts the token
+// of the request header of the given "name", e.g. when a proxy
+// forwards it through a non-standard "X-Access-Token" header.
+// The "scheme" is the expected prefix of the header value, e.g. "Bearer" or "Token",
+// compared case-insensitively. An empty "scheme" uses the whole header value as the token.
+//
+// Example Code:
+//
+//	verify := jwt.Middleware(jwt.HS256, sharedKey, jwt.FromHeaderNamed("X-Access-Token", ""))
+func FromHeaderNamed(name, scheme string) TokenExtractor {
+	return func(r *http.Request) (string, bool) {
+		return headerToken(r.Header.Get(name), scheme)
+	}
+}
+
+// webSocketProtocolMarker is the subprotocol which precedes the token
+// at the "Sec-WebSocket-Protocol" request header, see `FromWebSocketProtocol`.
+const webSocketProtocolMarker = "access_token"
+
+// FromWebSocketProtocol returns a TokenExtractor which extracts the token
+// of the "Sec-WebSocket-Protocol" header of a WebSocket upgrade request.
+// Browsers can not set the "Authorization" header of a WebSocket connection,
+// a common workaround is to send the token as a subprotocol,
+// right after the "access_token" marker:
+//
+//	new WebSocket(url, ["access_token", token])
+//
+// which results to the "Sec-WebSocket-Protocol: access_token, <token>" request header.
+// The upgrade response should select the "access_token" subprotocol, never the token itself.
+func FromWebSocketProtocol() TokenExtractor {
+	return func(r *http.Request) (string, bool) {
+		var protocols []string
+		for _, value := range r.Header.Values("Sec-WebSocket-Protocol") {
+			protocols = append(protocols, strings.Split(value, ",")...)
+		}
+
+		for i := 0; i < len(protocols)-1; i++ {
+			if strings.TrimSpace(protocols[i]) == webSocketProtocolMarker {
+				token := strings.TrimSpace(protocols[i+1])
+				return token, token != ""
+			}
+		}
+
+		return "", false
+	}
+}
+
+// FromCookie returns a TokenExtractor which extracts the token
+// from the value of the request cookie of the given "name".
+func FromCookie(name string) TokenExtractor {
+	return func(r *http.Request) (string, bool) {
+		cookie, err := r.Cookie(name)
+		if err != nil || cookie.Value == "" {
+			return "", false
+		}
+
+		return cookie.Value, true
+	}
+}
+
+// FromQuery returns a TokenExtractor which extracts the token
+// from the URL query parameter of the given "param" name.
+func FromQuery(param string) TokenExtractor {
+	return func(r *http.Request) (string, bool) {
+		token := r.URL.Query().Get(param)
+		return token, token != ""
+	}
+}
+
+// MiddlewareOption sets an option of the `Middleware`, e.g. `WithErrorHandler`.
+// It can be passed to the `Middleware` function, mixed with any other TokenValidator.
+type MiddlewareOption func(*middlewareConfig)
+
+var _ TokenValidator = MiddlewareOption(nil)
+
+// ValidateToken completes the TokenValidator interface,
+// so options can be passed to the `Middleware` function.
+// It respects the previous error.
+func (opt MiddlewareOption) ValidateToken(_ []byte, _ Claims, err error) error {
+	return err
+}
+
+type middlewareConfig struct {
+	extractors   []TokenExtractor
+	errorHandler func(w http.ResponseWriter, r *http.Request, err error)
+	blocklist    BlocklistStore
+}
+
+// WithErrorHandler is a MiddlewareOption which sets the handler
+// of a missing token or a verification failure.
+// The "err" is the `Verify` function's error as it's,
+// so the handler can branch on it, e.g. errors.Is(err, jwt.ErrExpired).
+// It is `ErrMissing` when the request does not contain a token.
+//
+// Defaults to a 401 Unauthorized JSON response.
+func WithErrorHandler(handler func(w http.ResponseWriter, r *http.Request, err error)) MiddlewareOption {
+	return func(c *middlewareConfig) {
+		if handler != nil {
+			c.errorHandler = handler
+		}
+	}
+}
+
+// WithRevocation is a MiddlewareOption which rejects tokens
+// whose "jti" claim is blocked by the given "store", like the `WithBlocklist` verify option does,
+// and enables the `Invalidate` function, e.g. on a logout handler,
+// to block the token of the current request.
+//
+// Example Code:
+//
+//	blocklist := jwt.NewMemoryBlocklist()
+//	defer blocklist.Close()
+//	verify := jwt.Middleware(jwt.HS256, sharedKey, jwt.WithRevocation(blocklist))
+//
+//	func logout(w http.ResponseWriter, r *http.Request) {
+//	  if err := jwt.Invalidate(r); err != nil { [...] }
+//	}
+func WithRevocation(store BlocklistStore) MiddlewareOption {
+	return func(c *middlewareConfig) {
+		c.blocklist = store
+	}
+}
+
+// Middleware returns a net/http middleware which verifies the
+// request's token using the given algorithm and key.
+//
+// The token is extracted by the TokenExtractors passed to the "validators",
+// tried in the given order, the first one that returns a token is used.
+// Defaults to the "Authorization: Bearer <token>" request header, see `FromHeader`.
+// The rest of "validators" are passed to the `Verify` function,
+// e.g. `WithLeeway` or `WithRequiredClaims` options.
+//
+// On failure it stops the request with a 401 Unauthorized JSON response
+// (see `WithErrorHandler`), otherwise it stores the verified token in the request's context
+// and executes the next handler. Use the `Get` package-level function
+// to retrieve the verified token inside the next handlers.
+//
+// Example Code:
+//
+//	verify := jwt.Middleware(jwt.HS256, sharedKey, jwt.FromCookie("token"), jwt.FromHeader())
+//	http.Handle("/protected", verify(protectedHandler))
+//
+//	func protectedHandler(w http.ResponseWriter, r *http.Request) {
+//	  verifiedToken := jwt.Get(r)
+//	  [...]
+//	}
+func Middleware(alg Alg, key PublicKey, validators ...TokenValidator) func(http.Handler) http.Handler {
+	cfg := middlewareConfig{errorHandler: unauthorized}
+	verifyValidators := make([]TokenValidator, 0, len(validators))
+	for _, validator := range validators {
+		switch v := validator.(type) {
+		case TokenExtractor:
+			if v != nil {
+				cfg.extractors = append(cfg.extractors, v)
+			}
+		case MiddlewareOption:
+			if v != nil {
+				v(&cfg)
+			}
+		default:
+			verifyValidators = append(verifyValidators, validator)
+		}
+	}
+
+	if len(cfg.extractors) == 0 {
+		cfg.extractors = []TokenExtractor{FromHeader()}
+	}
+
+	if cfg.blocklist != nil {
+		verifyValidators = append(verifyValidators, WithBlocklist(cfg.blocklist))
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := extractToken(r, cfg.extractors)
+			if !ok {
+				cfg.errorHandler(w, r, ErrMissing)
+				return
+			}
+
+			verifiedToken, err := Verify(alg, key, []byte(token), verifyValidators...)
+			if err != nil {
+				cfg.errorHandler(w, r, err)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), verifiedTokenContextKey, verifiedToken)
+			if cfg.blocklist != nil {
+				ctx = context.WithValue(ctx, blocklistContextKey, cfg.blocklist)
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Get returns the verified token stored by the `Middleware`
+// in the request's context. It returns nil if the request
+// was not passed through the middleware.
+func Get(r *http.Request) *VerifiedToken {
+	verifiedToken, _ := r.Context().Value(verifiedTokenContextKey).(*VerifiedToken)
+	return verifiedToken
+}
+
+var errNoRevocation = errors.New("jwt: Invalidate: missing blocklist, register the Middleware with the WithRevocation option")
+
+// Invalidate blocks the verified token of the request, e.g. on a logout handler,
+// the next requests of the same token are rejected by the `Middleware`.
+// The token's "jti" claim is blocked until the token's expiration time,
+// see the `WithGeneratedID` sign option to set one.
+//
+// It returns `ErrMissing` if the request was not passed through the middleware
+// and an error if the middleware is not registered with the `WithRevocation` option.
+// Tokens without a "jti" claim can not be blocked, they result to an
+// error which wraps the `ErrMissingRequiredClaim`.
+func Invalidate(r *http.Request) error {
+	verifiedToken := Get(r)
+	if verifiedToken == nil {
+		return ErrMissing
+	}
+
+	store, _ := r.Context().Value(blocklistContextKey).(BlocklistStore)
+	if store == nil {
+		return errNoRevocation
+	}
+
+	claims := verifiedToken.StandardClaims
+	if claims.ID == "" {
+		return fmt.Errorf("%w: jti", ErrMissingRequiredClaim)
+	}
+
+	var exp time.Time
+	if claims.Expiry > 0 {
+		exp = claims.ExpiresAt()
+	}
+
+	return store.Set(claims.ID, exp)
+}
+
+func extractToken(r *http.Request, extractors []TokenExtractor) (string, bool) {
+	for _, extractor := range extractors {
+		if token, ok := extractor(r); ok {
+			return token, true
+		}
+	}
+
+	return "", false
+}
+
+// fromAuthorizationHeader extracts the token of an "Authorization: Bearer <token>" header.
+func fromAuthorizationHeader(r *http.Request) (string, bool) {
+	return headerToken(r.Header.Get("Authorization"), "Bearer")
+}
+
+// headerToken returns the token of a "<scheme> <token>" header value.
+func headerToken(value, scheme string) (string, bool) {
+	if scheme != "" {
+		scheme += " "
+		if len(value) <= len(scheme) || !strings.EqualFold(value[:len(scheme)], scheme) {
+			return "", false
+		}
+
+		value = value[len(scheme):]
+	}
+
+	token := strings.TrimSpace(value)
+	return token, token != ""
+}
+
+func unauthorized(w http.ResponseWriter, _ *http.Request, _ error) {
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	writeJSONError(w, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+}
+
+func writeJSONError(w http.ResponseWriter, statusCode int, message string) {
+	body, _ := json.Marshal(struct {
+		Error string `json:"error"`
+	}{message})
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+	w.Write(body)
+}
@@ -0,0 +1,350 @@
+package jwt
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func testMiddlewareHandler(t *testing.T, validators ...TokenValidator) http.Handler {
+	t.Helper()
+
+	return Middleware(testAlg, testSecret, validators...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		verifiedToken := Get(r)
+		if verifiedToken == nil {
+			t.Fatalf("expected a verified token in the request context")
+		}
+
+		var claims Map
+		if err := verifiedToken.Claims(&claims); err != nil {
+			t.Fatal(err)
+		}
+
+		w.Write([]byte(claims["username"].(string)))
+	}))
+}
+
+func serveTestRequest(h http.Handler, configure func(r *http.Request)) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if configure != nil {
+		configure(r)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func withAuthorization(value string) func(r *http.Request) {
+	return func(r *http.Request) {
+		r.Header.Set("Authorization", value)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	h := testMiddlewareHandler(t)
+
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expiredToken, err := Sign(testAlg, testSecret, Map{"username": "kataras"}, Claims{Expiry: Clock().Add(-time.Minute).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		name           string
+		configure      func(r *http.Request)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{"valid", withAuthorization("Bearer " + string(token)), http.StatusOK, "kataras"},
+		{"case insensitive scheme", withAuthorization("bearer " + string(token)), http.StatusOK, "kataras"},
+		{"missing header", nil, http.StatusUnauthorized, `{"error":"Unauthorized"}`},
+		{"malformed header", withAuthorization(string(token)), http.StatusUnauthorized, `{"error":"Unauthorized"}`},
+		{"empty bearer", withAuthorization("Bearer "), http.StatusUnauthorized, `{"error":"Unauthorized"}`},
+		{"malformed token", withAuthorization("Bearer abc.def"), http.StatusUnauthorized, `{"error":"Unauthorized"}`},
+		{"expired token", withAuthorization("Bearer " + string(expiredToken)), http.StatusUnauthorized, `{"error":"Unauthorized"}`},
+	}
+
+	for _, tt := range tests {
+		w := serveTestRequest(h, tt.configure)
+		if w.Code != tt.expectedStatus {
+			t.Fatalf("[%s] expected status code: %d but got: %d", tt.name, tt.expectedStatus, w.Code)
+		}
+
+		if got := w.Body.String(); got != tt.expectedBody {
+			t.Fatalf("[%s] expected body: %q but got: %q", tt.name, tt.expectedBody, got)
+		}
+	}
+
+	if got := Get(httptest.NewRequest(http.MethodGet, "/", nil)); got != nil {
+		t.Fatalf("expected nil verified token outside of the middleware")
+	}
+}
+
+func withHeader(key, value string) func(r *http.Request) {
+	return func(r *http.Request) {
+		r.Header.Set(key, value)
+	}
+}
+
+func TestTokenExtractors(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	withCookie := func(r *http.Request) {
+		r.AddCookie(&http.Cookie{Name: "jwt", Value: string(token)})
+	}
+	withQuery := func(r *http.Request) {
+		r.URL.RawQuery = "token=" + string(token)
+	}
+
+	var tests = []struct {
+		name      string
+		extractor TokenExtractor
+		configure func(r *http.Request)
+		expected  bool
+	}{
+		{"header", FromHeader(), withAuthorization("Bearer " + string(token)), true},
+		{"header missing", FromHeader(), nil, false},
+		{"named header", FromHeaderNamed("X-Access-Token", ""), withHeader("X-Access-Token", string(token)), true},
+		{"named header spaces", FromHeaderNamed("X-Access-Token", ""), withHeader("X-Access-Token", " "+string(token)+" "), true},
+		{"named header empty", FromHeaderNamed("X-Access-Token", ""), withHeader("X-Access-Token", " "), false},
+		{"named header missing", FromHeaderNamed("X-Access-Token", ""), withAuthorization(string(token)), false},
+		{"named header scheme", FromHeaderNamed("X-Access-Token", "Token"), withHeader("X-Access-Token", "token "+string(token)), true},
+		{"named header wrong scheme", FromHeaderNamed("X-Access-Token", "Token"), withHeader("X-Access-Token", "Bearer "+string(token)), false},
+		{"named header no scheme", FromHeaderNamed("X-Access-Token", "Token"), withHeader("X-Access-Token", string(token)), false},
+		{"named authorization", FromHeaderNamed("Authorization", "Bearer"), withAuthorization("Bearer " + string(token)), true},
+		{"websocket protocol", FromWebSocketProtocol(), withHeader("Sec-WebSocket-Protocol", "access_token, "+string(token)), true},
+		{"websocket protocol chat", FromWebSocketProtocol(), withHeader("Sec-WebSocket-Protocol", "chat,access_token,"+string(token)+", v2.chat"), true},
+		{"websocket protocol values", FromWebSocketProtocol(), func(r *http.Request) {
+			r.Header.Add("Sec-WebSocket-Protocol", "access_token")
+			r.Header.Add("Sec-WebSocket-Protocol", string(token))
+		}, true},
+		{"websocket protocol no marker", FromWebSocketProtocol(), withHeader("Sec-WebSocket-Protocol", "chat, "+string(token)), false},
+		{"websocket protocol no token", FromWebSocketProtocol(), withHeader("Sec-WebSocket-Protocol", "chat, access_token"), false},
+		{"websocket protocol empty token", FromWebSocketProtocol(), withHeader("Sec-WebSocket-Protocol", "access_token, "), false},
+		{"websocket protocol missing", FromWebSocketProtocol(), nil, false},
+		{"cookie", FromCookie("jwt"), withCookie, true},
+		{"cookie missing", FromCookie("other"), withCookie, false},
+		{"query", FromQuery("token"), withQuery, true},
+		{"query missing", FromQuery("other"), withQuery, false},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.configure != nil {
+			tt.configure(r)
+		}
+
+		got, ok := tt.extractor(r)
+		if ok != tt.expected {
+			t.Fatalf("[%s] expected extracted: %v but got: %v", tt.name, tt.expected, ok)
+		}
+
+		if ok && got != string(token) {
+			t.Fatalf("[%s] expected token: %q but got: %q", tt.name, token, got)
+		}
+	}
+
+	// Fallthrough: the cookie is missing, use the query parameter.
+	h := testMiddlewareHandler(t, FromCookie("jwt"), FromQuery("token"))
+	if w := serveTestRequest(h, withQuery); w.Code != http.StatusOK {
+		t.Fatalf("expected status code: %d but got: %d", http.StatusOK, w.Code)
+	}
+
+	// The Authorization header is not used when other extractors are given.
+	if w := serveTestRequest(h, withAuthorization("Bearer "+string(token))); w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status code: %d but got: %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestMiddlewareErrorHandler(t *testing.T) {
+	var lastErr error
+	h := testMiddlewareHandler(t, WithErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+		lastErr = err
+
+		status := http.StatusUnauthorized
+		if errors.Is(err, ErrExpired) {
+			status = http.StatusForbidden
+		}
+
+		w.WriteHeader(status)
+		w.Write([]byte(err.Error()))
+	}))
+
+	expiredToken, err := Sign(testAlg, testSecret, Map{"username": "kataras"}, Claims{Expiry: Clock().Add(-time.Minute).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherKeyToken, err := Sign(testAlg, []byte("other"), Map{"username": "kataras"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		configure      func(r *http.Request)
+		expectedErr    error
+		expectedStatus int
+	}{
+		{nil, ErrMissing, http.StatusUnauthorized},
+		{withAuthorization("Bearer " + string(expiredToken)), ErrExpired, http.StatusForbidden},
+		{withAuthorization("Bearer " + string(otherKeyToken)), ErrTokenSignature, http.StatusUnauthorized},
+	}
+
+	for i, tt := range tests {
+		w := serveTestRequest(h, tt.configure)
+		if !errors.Is(lastErr, tt.expectedErr) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, tt.expectedErr, lastErr)
+		}
+
+		if w.Code != tt.expectedStatus {
+			t.Fatalf("[%d] expected status code: %d but got: %d", i, tt.expectedStatus, w.Code)
+		}
+
+		if expected, got := lastErr.Error(), w.Body.String(); expected != got {
+			t.Fatalf("[%d] expected body: %q but got: %q", i, expected, got)
+		}
+	}
+}
+
+func TestMiddlewareInvalidate(t *testing.T) {
+	blocklist := NewMemoryBlocklist()
+	defer blocklist.Close()
+
+	verify := Middleware(testAlg, testSecret, WithRevocation(blocklist))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		token, err := Sign(testAlg, testSecret, Map{"username": "kataras"}, MaxAge(time.Minute), WithGeneratedID())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		w.Write(token)
+	})
+	mux.Handle("/protected", verify(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("OK"))
+	})))
+	mux.Handle("/logout", verify(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := Invalidate(r); err != nil {
+			t.Fatal(err)
+		}
+	})))
+
+	serve := func(path string, token []byte) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != nil {
+			r.Header.Set("Authorization", "Bearer "+string(token))
+		}
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		return w
+	}
+
+	token := serve("/login", nil).Body.Bytes()
+	otherToken := serve("/login", nil).Body.Bytes()
+
+	if w := serve("/protected", token); w.Code != http.StatusOK {
+		t.Fatalf("expected status code: %d but got: %d", http.StatusOK, w.Code)
+	}
+
+	if w := serve("/logout", token); w.Code != http.StatusOK {
+		t.Fatalf("expected status code: %d but got: %d", http.StatusOK, w.Code)
+	}
+
+	if w := serve("/protected", token); w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status code: %d but got: %d", http.StatusUnauthorized, w.Code)
+	}
+
+	// Other tokens are not affected.
+	if w := serve("/protected", otherToken); w.Code != http.StatusOK {
+		t.Fatalf("expected status code: %d but got: %d", http.StatusOK, w.Code)
+	}
+
+	// Not passed through the middleware.
+	if err := Invalidate(httptest.NewRequest(http.MethodGet, "/", nil)); err != ErrMissing {
+		t.Fatalf("expected error: ErrMissing but got: %v", err)
+	}
+
+	var invalidateErr error
+	invalidate := func(w http.ResponseWriter, r *http.Request) {
+		invalidateErr = Invalidate(r)
+	}
+
+	// Without the WithRevocation option.
+	serveTestRequest(Middleware(testAlg, testSecret)(http.HandlerFunc(invalidate)), withAuthorization("Bearer "+string(otherToken)))
+	if invalidateErr == nil {
+		t.Fatalf("expected an error without the WithRevocation option")
+	}
+
+	// Without a "jti" claim.
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serveTestRequest(verify(http.HandlerFunc(invalidate)), withAuthorization("Bearer "+string(token)))
+	if !errors.Is(invalidateErr, ErrMissingRequiredClaim) {
+		t.Fatalf("expected error: ErrMissingRequiredClaim but got: %v", invalidateErr)
+	}
+
+	// Without an "exp" claim: blocked forever, it survives the GC.
+	token, err = Sign(testAlg, testSecret, Map{"username": "kataras"}, WithGeneratedID())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if w := serve("/logout", token); w.Code != http.StatusOK {
+		t.Fatalf("expected status code: %d but got: %d", http.StatusOK, w.Code)
+	}
+
+	if n := blocklist.GC(); n != 0 {
+		t.Fatalf("expected no entries to be removed by the GC but got: %d", n)
+	}
+
+	if w := serve("/protected", token); w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status code: %d after the GC but got: %d", http.StatusUnauthorized, w.Code)
+	}
+
+	// A validator which skips an error (e.g. the "iat" is slightly in the future)
+	// does not skip the revocation check.
+	futureVerify := Middleware(testAlg, testSecret, Future(time.Minute), WithRevocation(blocklist))
+	mux.Handle("/future/protected", futureVerify(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("OK"))
+	})))
+	mux.Handle("/future/logout", futureVerify(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := Invalidate(r); err != nil {
+			t.Fatal(err)
+		}
+	})))
+
+	token, err = Sign(testAlg, testSecret, Claims{
+		IssuedAt: Clock().Add(10 * time.Second).Unix(),
+		Expiry:   Clock().Add(time.Minute).Unix(),
+	}, WithGeneratedID())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if w := serve("/future/protected", token); w.Code != http.StatusOK {
+		t.Fatalf("expected status code: %d but got: %d", http.StatusOK, w.Code)
+	}
+
+	if w := serve("/future/logout", token); w.Code != http.StatusOK {
+		t.Fatalf("expected status code: %d but got: %d", http.StatusOK, w.Code)
+	}
+
+	if w := serve("/future/protected", token); w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status code: %d but got: %d", http.StatusUnauthorized, w.Code)
+	}
+}
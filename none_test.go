@@ -1,8 +1,60 @@
 package jwt
 
-import "testing"
+import (
+	"errors"
+	"testing"
+)
 
 func TestEncodeDecodeTokenNONE(t *testing.T) {
 	expectedToken := []byte("eyJhbGciOiJOT05FIiwidHlwIjoiSldUIn0.eyJ1c2VybmFtZSI6ImthdGFyYXMifQ.")
 	testEncodeDecodeToken(t, NONE, nil, nil, expectedToken)
 }
+
+func TestVerifyNONEExplicitOptIn(t *testing.T) {
+	claims := Map{"username": "kataras"}
+
+	signedToken, err := Sign(HS256, testSecret, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A signed token should never be accepted as an unsigned one.
+	if _, err = Verify(NONE, nil, signedToken); !errors.Is(err, ErrTokenAlg) {
+		t.Fatalf("expected error: ErrTokenAlg but got: %v", err)
+	}
+
+	unsignedToken, err := Sign(NONE, nil, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An unsigned token should never be accepted by a signing algorithm.
+	if _, err = Verify(HS256, testSecret, unsignedToken); !errors.Is(err, ErrTokenAlg) {
+		t.Fatalf("expected error: ErrTokenAlg but got: %v", err)
+	}
+
+	// The RFC's lowercase "none" should not be accepted either.
+	lowercaseToken := joinParts(Base64Encode([]byte(`{"alg":"none","typ":"JWT"}`)), Base64Encode([]byte(`{"username":"kataras"}`)), nil)
+	if _, err = Verify(HS256, testSecret, lowercaseToken); !errors.Is(err, ErrTokenAlg) {
+		t.Fatalf("expected error: ErrTokenAlg but got: %v", err)
+	}
+
+	// Unsigned header but with a signature part.
+	forgedToken := append(unsignedToken, signedToken[len(signedToken)-43:]...)
+	if _, err = Verify(NONE, nil, forgedToken); !errors.Is(err, ErrTokenSignature) {
+		t.Fatalf("expected error: ErrTokenSignature but got: %v", err)
+	}
+
+	// A header validator which does not select an algorithm
+	// should not let the token pass.
+	noAlgValidator := func(alg string, headerDecoded []byte) (Alg, PublicKey, InjectFunc, error) {
+		return nil, nil, nil, nil
+	}
+	if _, err = VerifyWithHeaderValidator(nil, nil, unsignedToken, noAlgValidator); !errors.Is(err, ErrTokenAlg) {
+		t.Fatalf("expected error: ErrTokenAlg but got: %v", err)
+	}
+
+	if _, err = Verify(NONE, nil, unsignedToken); err != nil {
+		t.Fatalf("expected explicit NONE verification to pass but got: %v", err)
+	}
+}
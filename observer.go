@@ -0,0 +1,74 @@
+package jwt
+
+import "time"
+
+// Observer is notified on each sign and verify operation,
+// e.g. to record the latency and the failure rate of the token verifications as metrics.
+// Its methods are called on success and failure, the "err" is nil on success.
+// They should not block as they are called synchronously.
+// See `WithObserver`.
+type Observer interface {
+	// OnSign is called when a token is signed with the "alg" algorithm,
+	// "d" is the time spent to build and sign the token.
+	OnSign(alg string, d time.Duration, err error)
+	// OnVerify is called when a token is verified, "d" is the time spent on its verification.
+	// The "alg" is the token's "alg" header field on success. On failure it's
+	// the algorithm given by the caller, or empty when the algorithm is selected per token,
+	// e.g. by a header validator, as the header of an invalid token is not trusted.
+	OnVerify(alg string, d time.Duration, err error)
+}
+
+// ObserverOption is both a SignOption and a TokenValidator
+// which reports the sign and verify operations to an Observer.
+// See `WithObserver`.
+type ObserverOption struct {
+	observer Observer
+}
+
+var (
+	_ SignOption     = ObserverOption{}
+	_ TokenValidator = ObserverOption{}
+)
+
+// ApplyClaims completes the `SignOption` interface.
+// It does nothing as the option is applied to the signing process.
+func (opt ObserverOption) ApplyClaims(*Claims) {}
+
+// ValidateToken completes the TokenValidator interface.
+// It respects the previous error.
+func (opt ObserverOption) ValidateToken(_ []byte, _ Claims, err error) error {
+	return err
+}
+
+// WithObserver returns an option which reports the operation to the given "observer".
+// It can be passed to the `Sign` and `Verify` families of functions,
+// and the `Middleware` and `Verifier` too.
+// The observer is called on the failures too.
+//
+// Example Code:
+//
+//	type metrics struct{}
+//
+//	func (metrics) OnSign(alg string, d time.Duration, err error) { /* [...] */ }
+//	func (metrics) OnVerify(alg string, d time.Duration, err error) {
+//	  verifyDuration.WithLabelValues(alg, strconv.FormatBool(err == nil)).Observe(d.Seconds())
+//	}
+//
+//	observe := jwt.WithObserver(metrics{})
+//	token, err := jwt.Sign(jwt.HS256, sharedKey, claims, observe)
+//	verifiedToken, err := jwt.Verify(jwt.HS256, sharedKey, token, observe)
+func WithObserver(observer Observer) ObserverOption {
+	return ObserverOption{observer: observer}
+}
+
+// signObserver returns the observer of the sign options, if any.
+func signObserver(opts []SignOption) Observer {
+	var observer Observer
+	for _, opt := range opts {
+		if o, ok := opt.(ObserverOption); ok && o.observer != nil {
+			observer = o.observer
+		}
+	}
+
+	return observer
+}
@@ -0,0 +1,150 @@
+package jwt
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testObservation struct {
+	op  string
+	alg string
+	d   time.Duration
+	err error
+}
+
+type testRecordingObserver struct {
+	mu           sync.Mutex
+	observations []testObservation
+}
+
+func (o *testRecordingObserver) OnSign(alg string, d time.Duration, err error) {
+	o.record(testObservation{"sign", alg, d, err})
+}
+
+func (o *testRecordingObserver) OnVerify(alg string, d time.Duration, err error) {
+	o.record(testObservation{"verify", alg, d, err})
+}
+
+func (o *testRecordingObserver) record(observation testObservation) {
+	o.mu.Lock()
+	o.observations = append(o.observations, observation)
+	o.mu.Unlock()
+}
+
+func (o *testRecordingObserver) last(t *testing.T) testObservation {
+	t.Helper()
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if len(o.observations) == 0 {
+		t.Fatalf("expected an observation")
+	}
+
+	return o.observations[len(o.observations)-1]
+}
+
+func TestWithObserver(t *testing.T) {
+	observer := new(testRecordingObserver)
+	observe := WithObserver(observer)
+
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"}, MaxAge(time.Minute), observe)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := observer.last(t); got.op != "sign" || got.alg != testAlg.Name() || got.err != nil || got.d < 0 {
+		t.Fatalf("unexpected sign observation: %#+v", got)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token, observe)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The option does not set any claim.
+	var claims Map
+	if err = verifiedToken.Claims(&claims); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := 3, len(claims); expected != got { // username, iat, exp.
+		t.Fatalf("expected: %d claims but got: %d: %v", expected, got, claims)
+	}
+
+	if got := observer.last(t); got.op != "verify" || got.alg != testAlg.Name() || got.err != nil || got.d < 0 {
+		t.Fatalf("unexpected verify observation: %#+v", got)
+	}
+
+	// Error paths.
+	failures := []struct {
+		token    []byte
+		alg      Alg
+		expected error
+	}{
+		{nil, testAlg, ErrMissing},
+		{[]byte("a.b"), testAlg, ErrTokenForm},
+		{token, HS512, ErrTokenAlg},
+	}
+
+	for _, tt := range failures {
+		if _, err = Verify(tt.alg, testSecret, tt.token, observe); err == nil {
+			t.Fatalf("expected error: %v", tt.expected)
+		}
+
+		if got := observer.last(t); got.op != "verify" || got.alg != tt.alg.Name() || !errors.Is(got.err, err) {
+			t.Fatalf("unexpected verify observation: %#+v", got)
+		}
+	}
+
+	if _, err = Verify(testAlg, []byte("othersecret"), token, observe); !errors.Is(observer.last(t).err, ErrTokenSignature) {
+		t.Fatalf("expected observed error: ErrTokenSignature but got: %v", observer.last(t).err)
+	}
+
+	// The algorithm is selected by a header validator:
+	// reported on success only.
+	keys := make(Keys)
+	keys.Register(HS384, "api-1", testSecret, testSecret)
+
+	token, err = keys.SignToken("api-1", Map{"username": "kataras"}, observe)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := observer.last(t); got.op != "sign" || got.alg != HS384.Name() {
+		t.Fatalf("unexpected sign observation: %#+v", got)
+	}
+
+	if _, err = VerifyWithHeaderValidator(nil, nil, token, keys.ValidateHeader, observe); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := observer.last(t); got.alg != HS384.Name() || got.err != nil {
+		t.Fatalf("unexpected verify observation: %#+v", got)
+	}
+
+	if _, err = VerifyWithHeaderValidator(nil, nil, token[:len(token)-5], keys.ValidateHeader, observe); err == nil {
+		t.Fatalf("expected error")
+	}
+
+	if got := observer.last(t); got.alg != "" || got.err == nil {
+		t.Fatalf("unexpected verify observation: %#+v", got)
+	}
+
+	// Sign failure.
+	if _, err = Sign(testAlg, invalidKey, Map{"username": "kataras"}, observe); err == nil {
+		t.Fatalf("expected error")
+	}
+
+	if got := observer.last(t); got.op != "sign" || !errors.Is(got.err, ErrInvalidKey) {
+		t.Fatalf("unexpected sign observation: %#+v", got)
+	}
+
+	observer.mu.Lock()
+	if expected, got := 10, len(observer.observations); expected != got {
+		t.Fatalf("expected: %d observations but got: %d", expected, got)
+	}
+	observer.mu.Unlock()
+}
@@ -0,0 +1,90 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidNonce indicates that the "nonce" claim of an ID token
+// is missing or it does not match the one expected by the `WithExpectedNonce` option.
+var ErrInvalidNonce = errors.New("jwt: invalid nonce")
+
+// WithExpectedNonce is a VerifyOption which makes the verification
+// to fail with an `ErrInvalidNonce` error if the "nonce" claim of an OpenID Connect ID token
+// is missing or it does not match the "nonce" the client sent on its authentication request.
+// It protects against replay attacks, it's required by the implicit and hybrid flows.
+// An empty "nonce" skips the check.
+//
+// Example Code:
+//
+//	verifiedToken, err := jwt.Verify(jwt.RS256, publicKey, idToken, jwt.WithExpectedNonce(session.Nonce))
+func WithExpectedNonce(nonce string) VerifyOption {
+	return func(c *verifyConfig) {
+		c.expectedNonce = nonce
+	}
+}
+
+func validateNonce(payload []byte, expected string) error {
+	var claims struct {
+		Nonce *string `json:"nonce"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return errPayloadNotJSON
+	}
+
+	if claims.Nonce == nil {
+		return fmt.Errorf("%w: missing nonce claim", ErrInvalidNonce)
+	}
+
+	if *claims.Nonce != expected {
+		return ErrInvalidNonce
+	}
+
+	return nil
+}
+
+// ErrInvalidAuthorizedParty indicates that the "azp" claim of an ID token
+// does not match the client ID given by the `WithExpectedAuthorizedParty` option,
+// or that it is missing from a token of multiple audiences.
+var ErrInvalidAuthorizedParty = errors.New("jwt: invalid authorized party")
+
+// WithExpectedAuthorizedParty is a VerifyOption which validates the "azp" (authorized party) claim
+// of an OpenID Connect ID token against the "clientID", as the OpenID Connect Core 1.0, Section 3.1.3.7 describes:
+// a token of multiple audiences must contain the "azp" claim and,
+// when present, the "azp" claim must be equal to the "clientID".
+// A token of a single audience and no "azp" claim is valid.
+// It returns an `ErrInvalidAuthorizedParty` error on failure.
+// An empty "clientID" skips the check.
+//
+// Example Code:
+//
+//	verifiedToken, err := jwt.Verify(jwt.RS256, publicKey, idToken, jwt.WithExpectedAuthorizedParty("my-client-id"))
+func WithExpectedAuthorizedParty(clientID string) VerifyOption {
+	return func(c *verifyConfig) {
+		c.expectedAuthorizedParty = clientID
+	}
+}
+
+func validateAuthorizedParty(payload []byte, audience Audience, clientID string) error {
+	var claims struct {
+		AuthorizedParty *string `json:"azp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return errPayloadNotJSON
+	}
+
+	if claims.AuthorizedParty == nil {
+		if len(audience) > 1 {
+			return fmt.Errorf("%w: missing azp claim of multiple audiences", ErrInvalidAuthorizedParty)
+		}
+
+		return nil
+	}
+
+	if *claims.AuthorizedParty != clientID {
+		return ErrInvalidAuthorizedParty
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrInvalidAtHash indicates that the "at_hash" claim of an ID token
+	// does not match its access token, see `ValidateAtHash`.
+	ErrInvalidAtHash = errors.New("jwt: invalid at_hash")
+	// ErrInvalidCHash indicates that the "c_hash" claim of an ID token
+	// does not match its authorization code, see `ValidateCHash`.
+	ErrInvalidCHash = errors.New("jwt: invalid c_hash")
+)
+
+// ValidateAtHash validates the "at_hash" claim of an ID token against the "accessToken"
+// which was issued along with it, as the OpenID Connect Core 1.0, Section 3.2.2.9 describes:
+// the base64url encoding of the left-most half of the hash of the access token,
+// its hash function is the one of the ID token's "alg" algorithm.
+//
+// The "idTokenClaims" is the verified ID token's payload, e.g. `verifiedToken.Payload`.
+// It returns an error which wraps `ErrInvalidAtHash` on a missing or mismatched claim.
+//
+// Example Code:
+//
+//	verifiedToken, err := jwt.Verify(jwt.RS256, publicKey, idToken)
+//	[handle error...]
+//	err = jwt.ValidateAtHash(verifiedToken.Payload, accessToken, jwt.RS256)
+func ValidateAtHash(idTokenClaims, accessToken []byte, alg Alg) error {
+	return validateOIDCHash(idTokenClaims, "at_hash", accessToken, alg, ErrInvalidAtHash)
+}
+
+// ValidateCHash same as `ValidateAtHash` but it validates the "c_hash" claim
+// against the authorization "code" of the hybrid flow (OpenID Connect Core 1.0, Section 3.3.2.11).
+// It returns an error which wraps `ErrInvalidCHash` on a missing or mismatched claim.
+func ValidateCHash(idTokenClaims, code []byte, alg Alg) error {
+	return validateOIDCHash(idTokenClaims, "c_hash", code, alg, ErrInvalidCHash)
+}
+
+// LeftHalfHash returns the base64url encoding of the left-most half of the hash of "value",
+// the hash function is the one of the "alg" algorithm, e.g. SHA-256 for RS256.
+// It is the value of the "at_hash" and "c_hash" claims of an OpenID Connect ID token.
+func LeftHalfHash(alg Alg, value []byte) (string, error) {
+	if alg == nil {
+		return "", ErrTokenAlg
+	}
+
+	hasher, ok := algHash(alg.Name())
+	if !ok {
+		return "", fmt.Errorf("%w: no hash function of: %q", ErrTokenAlg, alg.Name())
+	}
+
+	sum := sumHash(hasher, value)
+	return string(Base64Encode(sum[:len(sum)/2])), nil
+}
+
+// algHash returns the hash function of the JWA algorithm "name",
+// e.g. SHA-256 for HS256, RS256, PS256 and ES256.
+// EdDSA uses SHA-512, as the Ed25519 signature does.
+func algHash(name string) (crypto.Hash, bool) {
+	if name == EdDSA.Name() {
+		return crypto.SHA512, true
+	}
+
+	if len(name) == len("RS256") || (len(name) == len("ES256K") && strings.HasSuffix(name, "K")) {
+		switch name[2:5] {
+		case "256":
+			return crypto.SHA256, true
+		case "384":
+			return crypto.SHA384, true
+		case "512":
+			return crypto.SHA512, true
+		}
+	}
+
+	return 0, false
+}
+
+func validateOIDCHash(claims []byte, claim string, value []byte, alg Alg, errInvalid error) error {
+	var c map[string]json.RawMessage
+	if err := json.Unmarshal(claims, &c); err != nil {
+		return fmt.Errorf("%w: %v", errInvalid, err)
+	}
+
+	var got string
+	if raw, ok := c[claim]; !ok || json.Unmarshal(raw, &got) != nil || got == "" {
+		return fmt.Errorf("%w: missing %s claim", errInvalid, claim)
+	}
+
+	expected, err := LeftHalfHash(alg, value)
+	if err != nil {
+		return err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(got)) != 1 {
+		return errInvalid
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateAtHash(t *testing.T) {
+	// OpenID Connect Core 1.0, Appendix A.3 and A.4.
+	var (
+		accessToken = []byte("jHkWEdUXMU1BwAsC4vtUsZwnNvTIxEl0z9K3vx5KF0Y")
+		code        = []byte("Qcb0Orv1zh30vL1MPRsbm-diHiMwcLyZvn1arpZv-Jxf_11jnpEX3Tgfvk")
+		claims      = []byte(`{"iss":"https://server.example.com","at_hash":"77QmUPtjPfzWtF2AnpK9RQ","c_hash":"LDktKdoQak3Pk0cnXxCltA"}`)
+	)
+
+	if hash, err := LeftHalfHash(RS256, accessToken); err != nil || hash != "77QmUPtjPfzWtF2AnpK9RQ" {
+		t.Fatalf("unexpected at_hash: %q: %v", hash, err)
+	}
+
+	if err := ValidateAtHash(claims, accessToken, RS256); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ValidateCHash(claims, code, RS256); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ValidateAtHash(claims, []byte("other"), RS256); !errors.Is(err, ErrInvalidAtHash) {
+		t.Fatalf("expected error: ErrInvalidAtHash but got: %v", err)
+	}
+
+	// Same input, different hash function.
+	if err := ValidateAtHash(claims, accessToken, RS512); !errors.Is(err, ErrInvalidAtHash) {
+		t.Fatalf("expected error: ErrInvalidAtHash but got: %v", err)
+	}
+
+	if err := ValidateCHash([]byte(`{"iss":"https://server.example.com"}`), code, RS256); !errors.Is(err, ErrInvalidCHash) {
+		t.Fatalf("expected error: ErrInvalidCHash but got: %v", err)
+	}
+
+	if err := ValidateAtHash(claims, accessToken, NONE); !errors.Is(err, ErrTokenAlg) {
+		t.Fatalf("expected error: ErrTokenAlg but got: %v", err)
+	}
+
+	for _, alg := range []Alg{HS256, HS384, HS512, RS384, PS256, ES512, ES256K, EdDSA} {
+		hash, err := LeftHalfHash(alg, accessToken)
+		if err != nil {
+			t.Fatalf("%s: %v", alg.Name(), err)
+		}
+
+		if err = ValidateAtHash([]byte(`{"at_hash":"`+hash+`"}`), accessToken, alg); err != nil {
+			t.Fatalf("%s: %v", alg.Name(), err)
+		}
+	}
+}
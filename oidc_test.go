@@ -0,0 +1,84 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithExpectedNonce(t *testing.T) {
+	var tests = []struct {
+		name    string
+		claims  Map
+		options []TokenValidator
+		err     error
+	}{
+		{"match", Map{"nonce": "n-0S6_WzA2Mj"}, []TokenValidator{WithExpectedNonce("n-0S6_WzA2Mj")}, nil},
+		{"mismatch", Map{"nonce": "other"}, []TokenValidator{WithExpectedNonce("n-0S6_WzA2Mj")}, ErrInvalidNonce},
+		{"missing", Map{"sub": "kataras"}, []TokenValidator{WithExpectedNonce("n-0S6_WzA2Mj")}, ErrInvalidNonce},
+		{"all errors", Map{"nonce": "other"}, []TokenValidator{WithExpectedNonce("n-0S6_WzA2Mj"), WithAllValidationErrors()}, ErrInvalidNonce},
+		{"not expected", Map{"nonce": "other"}, []TokenValidator{WithExpectedNonce("")}, nil},
+		{"not expected and missing", Map{"sub": "kataras"}, nil, nil},
+		// The Future validator skips the "iat" error, the nonce must still be checked.
+		{"mismatch after future", Map{"nonce": "other", "iat": Clock().Add(10 * time.Second).Unix()}, []TokenValidator{Future(time.Minute), WithExpectedNonce("n-0S6_WzA2Mj")}, ErrInvalidNonce},
+		{"match after future", Map{"nonce": "n-0S6_WzA2Mj", "iat": Clock().Add(10 * time.Second).Unix()}, []TokenValidator{Future(time.Minute), WithExpectedNonce("n-0S6_WzA2Mj")}, nil},
+	}
+
+	for _, tt := range tests {
+		token, err := Sign(testAlg, testSecret, tt.claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = Verify(testAlg, testSecret, token, tt.options...); !errors.Is(err, tt.err) {
+			t.Fatalf("[%s] expected error: %v but got: %v", tt.name, tt.err, err)
+		}
+	}
+}
+
+func TestWithExpectedAuthorizedParty(t *testing.T) {
+	const clientID = "s6BhdRkqt3"
+
+	var tests = []struct {
+		name   string
+		claims Map
+		err    error
+	}{
+		{"single aud without azp", Map{"aud": clientID}, nil},
+		{"single aud with azp", Map{"aud": []string{clientID}, "azp": clientID}, nil},
+		{"single aud with other azp", Map{"aud": clientID, "azp": "other"}, ErrInvalidAuthorizedParty},
+		{"multi aud with azp", Map{"aud": []string{clientID, "other-api"}, "azp": clientID}, nil},
+		{"multi aud without azp", Map{"aud": []string{clientID, "other-api"}}, ErrInvalidAuthorizedParty},
+		{"multi aud with other azp", Map{"aud": []string{clientID, "other-api"}, "azp": "other-api"}, ErrInvalidAuthorizedParty},
+	}
+
+	for _, tt := range tests {
+		token, err := Sign(testAlg, testSecret, tt.claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = Verify(testAlg, testSecret, token, WithExpectedAuthorizedParty(clientID)); !errors.Is(err, tt.err) {
+			t.Fatalf("[%s] expected error: %v but got: %v", tt.name, tt.err, err)
+		}
+
+		if _, err = Verify(testAlg, testSecret, token, WithExpectedAuthorizedParty(clientID), WithAllValidationErrors()); !errors.Is(err, tt.err) {
+			t.Fatalf("[%s] all errors: expected error: %v but got: %v", tt.name, tt.err, err)
+		}
+
+		// The Future validator skips the "iat" error, the authorized party must still be checked.
+		futureToken, err := Sign(testAlg, testSecret, Merge(tt.claims, Map{"iat": Clock().Add(10 * time.Second).Unix()}))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = Verify(testAlg, testSecret, futureToken, Future(time.Minute), WithExpectedAuthorizedParty(clientID)); !errors.Is(err, tt.err) {
+			t.Fatalf("[%s] future: expected error: %v but got: %v", tt.name, tt.err, err)
+		}
+
+		// Skipped when the client ID is not given.
+		if _, err = Verify(testAlg, testSecret, token); err != nil {
+			t.Fatalf("[%s] %v", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+// decodePEM returns the DER-encoded bytes of the first PEM block of "key".
+// If "key" is not PEM-encoded then it is returned as it is,
+// so the caller can try to parse it as a raw DER-encoded key.
+func decodePEM(key []byte) (der []byte, isPEM bool) {
+	block, _ := pem.Decode(key)
+	if block == nil {
+		return key, false
+	}
+
+	return block.Bytes, true
+}
+
+// ErrIncorrectPassword indicates that a passphrase-protected
+// private key could not be decrypted with the given password.
+var ErrIncorrectPassword = errors.New("jwt: incorrect private key password")
+
+var errPEMEncryption = errors.New("unsupported PEM encryption")
+
+// decryptPEM decrypts the first PEM block of "key" using the "password".
+// Both the PKCS #8 ("ENCRYPTED PRIVATE KEY", PBES2) and
+// the legacy RFC 1423 ("Proc-Type: 4,ENCRYPTED") encryptions are supported.
+// It returns the DER-encoded private key.
+func decryptPEM(key, password []byte) ([]byte, error) {
+	block, _ := pem.Decode(key)
+	if block == nil {
+		return nil, errPEMMalformed
+	}
+
+	// The legacy encryption is insecure by design but it is still
+	// produced by tools like "openssl rsa -aes256 -traditional".
+	if x509.IsEncryptedPEMBlock(block) {
+		der, err := x509.DecryptPEMBlock(block, password)
+		if err != nil {
+			if errors.Is(err, x509.IncorrectPasswordError) {
+				return nil, ErrIncorrectPassword
+			}
+
+			return nil, err
+		}
+
+		return der, nil
+	}
+
+	if block.Type != "ENCRYPTED PRIVATE KEY" {
+		return nil, fmt.Errorf("%w: %s", errPEMEncryption, block.Type)
+	}
+
+	return decryptPKCS8(block.Bytes, password)
+}
+
+var (
+	oidPBES2  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACWithSHA384 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 10}
+	oidHMACWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+
+	oidAES128CBC = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidDESEDE3   = asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 7}
+)
+
+type (
+	algorithmIdentifier struct {
+		Algorithm  asn1.ObjectIdentifier
+		Parameters asn1.RawValue `asn1:"optional"`
+	}
+
+	encryptedPrivateKeyInfo struct {
+		EncryptionAlgorithm algorithmIdentifier
+		EncryptedData       []byte
+	}
+
+	pbes2Params struct {
+		KeyDerivationFunc algorithmIdentifier
+		EncryptionScheme  algorithmIdentifier
+	}
+
+	pbkdf2Params struct {
+		Salt           []byte
+		IterationCount int
+		KeyLength      int                 `asn1:"optional"`
+		PRF            algorithmIdentifier `asn1:"optional"`
+	}
+)
+
+// decryptPKCS8 decrypts a DER-encoded EncryptedPrivateKeyInfo (RFC 5958)
+// of the PBES2 scheme (RFC 8018) with a PBKDF2 key derivation.
+func decryptPKCS8(der, password []byte) ([]byte, error) {
+	var info encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, err
+	}
+
+	if !info.EncryptionAlgorithm.Algorithm.Equal(oidPBES2) {
+		return nil, fmt.Errorf("%w: %s", errPEMEncryption, info.EncryptionAlgorithm.Algorithm)
+	}
+
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(info.EncryptionAlgorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, err
+	}
+
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, fmt.Errorf("%w: %s", errPEMEncryption, params.KeyDerivationFunc.Algorithm)
+	}
+
+	var kdfParams pbkdf2Params
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdfParams); err != nil {
+		return nil, err
+	}
+
+	var prf func() hash.Hash
+	switch algo := kdfParams.PRF.Algorithm; {
+	case len(algo) == 0, algo.Equal(oidHMACWithSHA1):
+		prf = sha1.New
+	case algo.Equal(oidHMACWithSHA256):
+		prf = sha256.New
+	case algo.Equal(oidHMACWithSHA384):
+		prf = sha512.New384
+	case algo.Equal(oidHMACWithSHA512):
+		prf = sha512.New
+	default:
+		return nil, fmt.Errorf("%w: %s", errPEMEncryption, algo)
+	}
+
+	var (
+		keyLen   int
+		newBlock func([]byte) (cipher.Block, error)
+	)
+	switch algo := params.EncryptionScheme.Algorithm; {
+	case algo.Equal(oidAES128CBC):
+		keyLen, newBlock = 16, aes.NewCipher
+	case algo.Equal(oidAES192CBC):
+		keyLen, newBlock = 24, aes.NewCipher
+	case algo.Equal(oidAES256CBC):
+		keyLen, newBlock = 32, aes.NewCipher
+	case algo.Equal(oidDESEDE3):
+		keyLen, newBlock = 24, des.NewTripleDESCipher
+	default:
+		return nil, fmt.Errorf("%w: %s", errPEMEncryption, algo)
+	}
+
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		return nil, err
+	}
+
+	if kdfParams.IterationCount <= 0 {
+		return nil, fmt.Errorf("%w: invalid iteration count", errPEMEncryption)
+	}
+
+	block, err := newBlock(pbkdf2(prf, password, kdfParams.Salt, kdfParams.IterationCount, keyLen))
+	if err != nil {
+		return nil, err
+	}
+
+	data := info.EncryptedData
+	if len(iv) != block.BlockSize() || len(data) == 0 || len(data)%block.BlockSize() != 0 {
+		return nil, fmt.Errorf("%w: invalid encrypted data size", errPEMEncryption)
+	}
+
+	decrypted := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(decrypted, data)
+
+	// A wrong password results to an invalid padding or an invalid key structure.
+	n := int(decrypted[len(decrypted)-1])
+	if n == 0 || n > block.BlockSize() || !bytes.Equal(decrypted[len(decrypted)-n:], bytes.Repeat([]byte{byte(n)}, n)) {
+		return nil, ErrIncorrectPassword
+	}
+	decrypted = decrypted[:len(decrypted)-n]
+
+	var raw asn1.RawValue
+	if rest, err := asn1.Unmarshal(decrypted, &raw); err != nil || len(rest) > 0 {
+		return nil, ErrIncorrectPassword
+	}
+
+	return decrypted, nil
+}
+
+// pbkdf2 derives a key from the password as defined at RFC 8018, section 5.2.
+func pbkdf2(prf func() hash.Hash, password, salt []byte, iter, keyLen int) []byte {
+	h := hmac.New(prf, password)
+	hashLen := h.Size()
+	numBlocks := (keyLen + hashLen - 1) / hashLen
+
+	var buf [4]byte
+	dk := make([]byte, 0, numBlocks*hashLen)
+	u := make([]byte, hashLen)
+	for block := 1; block <= numBlocks; block++ {
+		h.Reset()
+		h.Write(salt)
+		binary.BigEndian.PutUint32(buf[:], uint32(block))
+		h.Write(buf[:4])
+		dk = h.Sum(dk)
+		t := dk[len(dk)-hashLen:]
+		copy(u, t)
+
+		for n := 2; n <= iter; n++ {
+			h.Reset()
+			h.Write(u)
+			u = u[:0]
+			u = h.Sum(u)
+			for x := range u {
+				t[x] ^= u[x]
+			}
+		}
+	}
+
+	return dk[:keyLen]
+}
@@ -0,0 +1,42 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"fmt"
+)
+
+// PublicKeyOf returns the public key of the given "privateKey",
+// e.g. to publish it through a JWKS document.
+// The result of an *rsa.PrivateKey is an *rsa.PublicKey,
+// of an *ecdsa.PrivateKey is an *ecdsa.PublicKey
+// and of an ed25519.PrivateKey is an ed25519.PublicKey.
+// Any other crypto.Signer (e.g. a hardware key) results to its Public() key.
+//
+// It returns an error which wraps `ErrInvalidKey` on a nil or an unsupported key,
+// including the HMAC secrets which have no public key.
+func PublicKeyOf(privateKey PrivateKey) (PublicKey, error) {
+	switch key := privateKey.(type) {
+	case *rsa.PrivateKey:
+		if key == nil {
+			break
+		}
+		return &key.PublicKey, nil
+	case *ecdsa.PrivateKey:
+		if key == nil {
+			break
+		}
+		return &key.PublicKey, nil
+	case ed25519.PrivateKey:
+		if len(key) != ed25519.PrivateKeySize {
+			return nil, fmt.Errorf("ed25519 private key: bad length: %d: %w", len(key), ErrInvalidKey)
+		}
+		return key.Public(), nil
+	case crypto.Signer:
+		return key.Public(), nil
+	}
+
+	return nil, fmt.Errorf("public key of %T: %w", privateKey, ErrInvalidKey)
+}
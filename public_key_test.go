@@ -0,0 +1,69 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"errors"
+	"testing"
+)
+
+func TestPublicKeyOf(t *testing.T) {
+	rsaPrivateKey, err := GenerateRSAKeys(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ecdsaPrivateKey, err := GenerateECDSAKeys(elliptic.P256())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ed25519PrivateKey, err := GenerateEdDSAKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		alg        Alg
+		privateKey PrivateKey
+		expected   PublicKey
+	}{
+		{RS256, rsaPrivateKey, &rsaPrivateKey.PublicKey},
+		{ES256, ecdsaPrivateKey, &ecdsaPrivateKey.PublicKey},
+		{EdDSA, ed25519PrivateKey, ed25519PrivateKey.Public()},
+	}
+
+	for _, tt := range tests {
+		publicKey, err := PublicKeyOf(tt.privateKey)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.alg.Name(), err)
+		}
+
+		switch key := publicKey.(type) {
+		case *rsa.PublicKey:
+			if !key.Equal(tt.expected) {
+				t.Fatalf("%s: unexpected public key", tt.alg.Name())
+			}
+		case *ecdsa.PublicKey:
+			if !key.Equal(tt.expected) {
+				t.Fatalf("%s: unexpected public key", tt.alg.Name())
+			}
+		case ed25519.PublicKey:
+			if !key.Equal(tt.expected) {
+				t.Fatalf("%s: unexpected public key", tt.alg.Name())
+			}
+		default:
+			t.Fatalf("%s: unexpected public key type: %T", tt.alg.Name(), publicKey)
+		}
+
+		testEncodeDecodeToken(t, tt.alg, tt.privateKey, publicKey, nil)
+	}
+
+	for _, key := range []PrivateKey{testSecret, "secret", nil, (*rsa.PrivateKey)(nil), (*ecdsa.PrivateKey)(nil), ed25519PrivateKey[:32]} {
+		if _, err = PublicKeyOf(key); !errors.Is(err, ErrInvalidKey) {
+			t.Fatalf("%T: expected error: ErrInvalidKey but got: %v", key, err)
+		}
+	}
+}
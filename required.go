@@ -1,6 +1,7 @@
 package jwt
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
@@ -11,6 +12,61 @@ import (
 // Check with errors.Is.
 var ErrMissingKey = errors.New("jwt: token is missing a required field")
 
+// ErrMissingRequiredClaim indicates that the token's payload
+// does not contain a claim required by the `WithRequiredClaims` option.
+// Check with errors.Is.
+var ErrMissingRequiredClaim = errors.New("jwt: token is missing a required claim")
+
+// MissingClaimError is the error type of a missing required claim,
+// see `WithRequiredClaims` and `VerifyAccessToken`.
+// It wraps the `ErrMissingRequiredClaim`, check with errors.Is
+// or with errors.As to read the claim's name.
+type MissingClaimError struct {
+	Claim string // The name of the missing claim, e.g. "sub".
+}
+
+// Error implements the error interface.
+func (e *MissingClaimError) Error() string {
+	return fmt.Sprintf("%v: %q", ErrMissingRequiredClaim, e.Claim)
+}
+
+// Unwrap returns the `ErrMissingRequiredClaim`.
+func (e *MissingClaimError) Unwrap() error {
+	return ErrMissingRequiredClaim
+}
+
+// WithRequiredClaims is a VerifyOption which makes the verification
+// to fail with a `*MissingClaimError` error (wrapping the `ErrMissingRequiredClaim`)
+// if any of the claim "names" is missing from the token's payload.
+//
+// It checks for the claim's presence only,
+// a claim with an empty value (e.g. "" or null) is present.
+// Multiple WithRequiredClaims options are combined.
+//
+// Example Code:
+//
+//	verifiedToken, err := jwt.Verify(jwt.HS256, key, token, jwt.WithRequiredClaims("sub", "scope"))
+func WithRequiredClaims(names ...string) VerifyOption {
+	return func(c *verifyConfig) {
+		c.requiredClaims = append(c.requiredClaims, names...)
+	}
+}
+
+func validateRequiredClaims(payload []byte, names []string) error {
+	var claims map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return errPayloadNotJSON
+	}
+
+	for _, name := range names {
+		if _, ok := claims[name]; !ok {
+			return &MissingClaimError{Claim: name}
+		}
+	}
+
+	return nil
+}
+
 // HasRequiredJSONTag reports whether a specific value of "i"
 // contains one or more `json:"xxx,required"` struct fields tags.
 //
@@ -3,6 +3,7 @@ package jwt
 import (
 	"errors"
 	"testing"
+	"time"
 )
 
 func TestUnmarshalWithRequired(t *testing.T) {
@@ -50,3 +51,47 @@ func TestUnmarshalWithRequired(t *testing.T) {
 		t.Fatalf("expected error: ErrMissingKey but got: %v", err)
 	}
 }
+
+func TestWithRequiredClaims(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"sub": "user-1", "scope": "", "role": nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Present but empty values are present.
+	if _, err = Verify(testAlg, testSecret, token, WithRequiredClaims("sub", "scope"), WithRequiredClaims("role")); err != nil {
+		t.Fatalf("expected present claims to pass but got: %v", err)
+	}
+
+	_, err = Verify(testAlg, testSecret, token, WithRequiredClaims("sub", "tenant"))
+	if !errors.Is(err, ErrMissingRequiredClaim) {
+		t.Fatalf("expected error: ErrMissingRequiredClaim but got: %v", err)
+	}
+
+	if expected, got := `jwt: token is missing a required claim: "tenant"`, err.Error(); expected != got {
+		t.Fatalf("expected error message: %q but got: %q", expected, got)
+	}
+
+	// Standard claims validation errors come first.
+	expiredToken, err := Sign(testAlg, testSecret, Map{"exp": Clock().Add(-time.Minute).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, expiredToken, WithRequiredClaims("sub")); !errors.Is(err, ErrExpired) {
+		t.Fatalf("expected error: ErrExpired but got: %v", err)
+	}
+}
+
+func TestWithRequiredClaimsAfterValidators(t *testing.T) {
+	// The "iat" is slightly in the future and the Future validator skips that error,
+	// the required claims must still be checked.
+	token, err := Sign(testAlg, testSecret, Map{"iat": Clock().Add(10 * time.Second).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, Future(time.Minute), WithRequiredClaims("sub")); !errors.Is(err, ErrMissingRequiredClaim) {
+		t.Fatalf("expected error: ErrMissingRequiredClaim but got: %v", err)
+	}
+}
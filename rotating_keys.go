@@ -0,0 +1,205 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// DefaultRotatingKeySetRetention is the default number of the previous keys
+// which a RotatingKeySet keeps for verification after a rotation.
+const DefaultRotatingKeySetRetention = 1
+
+// ErrDuplicateKid indicates that a key of the same "kid" is already part of a RotatingKeySet.
+var ErrDuplicateKid = errors.New("jwt: duplicate kid")
+
+// RotatingKeySet holds a primary signing key and a number of previous keys,
+// so the signing key can be rotated with zero downtime:
+// new tokens are signed with the primary key (the "kid" header is set to its id),
+// while tokens signed with a previous key are still accepted until that key is retired.
+//
+// It is safe for concurrent use, tokens can be signed and verified during a rotation.
+// It completes the `HeaderValidator` through its `ValidateHeader` method.
+// See `NewRotatingKeySet` to create a new one.
+type RotatingKeySet struct {
+	alg       Alg
+	retention int
+
+	mu   sync.RWMutex
+	keys []*Key // the first one is the primary key.
+}
+
+// RotatingKeySetOption sets an option of a RotatingKeySet.
+// See `NewRotatingKeySet`.
+type RotatingKeySetOption func(*RotatingKeySet)
+
+// WithRetention sets the number of the previous keys which are kept for verification
+// after a rotation, the oldest ones are retired. Zero retires the previous key immediately.
+// Defaults to `DefaultRotatingKeySetRetention`.
+func WithRetention(n int) RotatingKeySetOption {
+	return func(r *RotatingKeySet) {
+		if n >= 0 {
+			r.retention = n
+		}
+	}
+}
+
+// NewRotatingKeySet returns a new RotatingKeySet of the given algorithm
+// and its initial primary "key" of the "kid" id.
+// The "key" is the private key of the algorithm, its public key is derived from it,
+// or the shared secret of an HMAC algorithm.
+//
+// Example Code:
+//
+//	keySet, err := jwt.NewRotatingKeySet(jwt.HS256, []byte("secret-1"), "key-1", jwt.WithRetention(2))
+//	[...]
+//	token, err := keySet.Sign(claims, jwt.MaxAge(15*time.Minute))
+//	[...]
+//	err = keySet.Rotate([]byte("secret-2"), "key-2")
+//	[...]
+//	verifiedToken, err := keySet.Verify(token) // verified by "key-1".
+func NewRotatingKeySet(alg Alg, key PrivateKey, kid string, opts ...RotatingKeySetOption) (*RotatingKeySet, error) {
+	if alg == nil {
+		return nil, ErrTokenAlg
+	}
+
+	r := &RotatingKeySet{
+		alg:       alg,
+		retention: DefaultRotatingKeySetRetention,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if err := r.Rotate(key, kid); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Rotate promotes the given "key" of the "kid" id to the primary signing key.
+// The previous primary key is kept for verification,
+// the oldest keys beyond the retention are retired.
+// It returns an `ErrDuplicateKid` error if a key of the same "kid" is still in use.
+func (r *RotatingKeySet) Rotate(key PrivateKey, kid string) error {
+	if kid == "" {
+		return ErrEmptyKid
+	}
+
+	pub, err := r.publicKeyOf(key)
+	if err != nil {
+		return err
+	}
+
+	k := &Key{
+		ID:      kid,
+		Alg:     r.alg,
+		Public:  pub,
+		Private: key,
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, prev := range r.keys {
+		if prev.ID == kid {
+			return fmt.Errorf("%w: %q", ErrDuplicateKid, kid)
+		}
+	}
+
+	n := len(r.keys)
+	if n > r.retention {
+		n = r.retention
+	}
+
+	// A new slice, so the readers of the previous one are not affected.
+	keys := make([]*Key, 0, n+1)
+	keys = append(keys, k)
+	r.keys = append(keys, r.keys[:n]...)
+	return nil
+}
+
+func (r *RotatingKeySet) publicKeyOf(key PrivateKey) (PublicKey, error) {
+	if _, ok := r.alg.(*algHMAC); ok {
+		secret, ok := key.([]byte)
+		if !ok || len(secret) == 0 {
+			return nil, fmt.Errorf("%s: secret of %T: %w", r.alg.Name(), key, ErrInvalidKey)
+		}
+
+		return secret, nil
+	}
+
+	return PublicKeyOf(key)
+}
+
+// Primary returns the id of the primary signing key.
+func (r *RotatingKeySet) Primary() string {
+	r.mu.RLock()
+	kid := r.keys[0].ID
+	r.mu.RUnlock()
+
+	return kid
+}
+
+// PublicKeys returns the public keys of the set, by their ids,
+// e.g. to publish them through the `MarshalJWKS` function.
+func (r *RotatingKeySet) PublicKeys() map[string]PublicKey {
+	r.mu.RLock()
+	keys := make(map[string]PublicKey, len(r.keys))
+	for _, k := range r.keys {
+		keys[k.ID] = k.Public
+	}
+	r.mu.RUnlock()
+
+	return keys
+}
+
+// Sign signs the "claims" with the primary key and sets the "kid" header to its id.
+// See `Sign` package-level function for more.
+func (r *RotatingKeySet) Sign(claims interface{}, opts ...SignOption) ([]byte, error) {
+	r.mu.RLock()
+	k := r.keys[0]
+	r.mu.RUnlock()
+
+	signOpts := make([]SignOption, 0, len(opts)+1)
+	signOpts = append(signOpts, opts...)
+	return Sign(k.Alg, k.Private, claims, append(signOpts, WithKID(k.ID))...)
+}
+
+// ValidateHeader validates the given json header value (base64 decoded) based on the keys of the set.
+// RotatingKeySet structure completes the `HeaderValidator` interface.
+func (r *RotatingKeySet) ValidateHeader(alg string, headerDecoded []byte) (Alg, PublicKey, InjectFunc, error) {
+	var h HeaderWithKid
+	if err := Unmarshal(headerDecoded, &h); err != nil {
+		return nil, nil, nil, err
+	}
+
+	if h.Kid == "" {
+		return nil, nil, nil, ErrEmptyKid
+	}
+
+	if h.Alg != r.alg.Name() || (alg != "" && alg != h.Alg) {
+		return nil, nil, nil, ErrTokenAlg
+	}
+
+	r.mu.RLock()
+	keys := r.keys
+	r.mu.RUnlock()
+
+	for _, k := range keys {
+		if k.ID == h.Kid {
+			return k.Alg, k.Public, nil, nil
+		}
+	}
+
+	return nil, nil, nil, ErrUnknownKid
+}
+
+// Verify verifies the "token" using the key selected by its "kid" header field,
+// the primary key or any of the previous ones.
+// See `Verify` package-level function for more.
+func (r *RotatingKeySet) Verify(token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
+	return VerifyWithHeaderValidator(nil, nil, token, r.ValidateHeader, validators...)
+}
@@ -0,0 +1,225 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRotatingKeySet(t *testing.T) {
+	keySet, err := NewRotatingKeySet(HS256, []byte("secret-1"), "key-1", WithRetention(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token1, err := keySet.Sign(Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := keySet.Verify(token1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "key-1", verifiedToken.Kid(); expected != got {
+		t.Fatalf("expected kid: %q but got: %q", expected, got)
+	}
+
+	if err = keySet.Rotate([]byte("secret-2"), "key-2"); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "key-2", keySet.Primary(); expected != got {
+		t.Fatalf("expected primary kid: %q but got: %q", expected, got)
+	}
+
+	token2, err := keySet.Sign(Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if kid := mustVerifyKid(t, keySet, token2); kid != "key-2" {
+		t.Fatalf("expected kid: %q but got: %q", "key-2", kid)
+	}
+
+	// The previous key is still accepted.
+	if kid := mustVerifyKid(t, keySet, token1); kid != "key-1" {
+		t.Fatalf("expected kid: %q but got: %q", "key-1", kid)
+	}
+
+	if err = keySet.Rotate([]byte("secret-2"), "key-2"); !errors.Is(err, ErrDuplicateKid) {
+		t.Fatalf("expected error: ErrDuplicateKid but got: %v", err)
+	}
+
+	// The oldest key is retired beyond the retention.
+	if err = keySet.Rotate([]byte("secret-3"), "key-3"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = keySet.Verify(token1); err != ErrUnknownKid {
+		t.Fatalf("expected error: ErrUnknownKid but got: %v", err)
+	}
+
+	mustVerifyKid(t, keySet, token2)
+
+	if expected, got := 2, len(keySet.PublicKeys()); expected != got {
+		t.Fatalf("expected: %d public keys but got: %d", expected, got)
+	}
+
+	// A token of a known kid but signed by another key.
+	forged, err := Sign(HS256, []byte("other"), Map{"username": "kataras"}, WithKID("key-3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = keySet.Verify(forged); !errors.Is(err, ErrTokenSignature) {
+		t.Fatalf("expected error: ErrTokenSignature but got: %v", err)
+	}
+
+	// A token of a different algorithm.
+	forged, err = Sign(HS512, []byte("secret-3"), Map{"username": "kataras"}, WithKID("key-3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = keySet.Verify(forged); !errors.Is(err, ErrTokenAlg) {
+		t.Fatalf("expected error: ErrTokenAlg but got: %v", err)
+	}
+
+	if err = keySet.Rotate([]byte("secret-4"), ""); err != ErrEmptyKid {
+		t.Fatalf("expected error: ErrEmptyKid but got: %v", err)
+	}
+
+	if err = keySet.Rotate([]byte{}, "key-4"); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected error: ErrInvalidKey but got: %v", err)
+	}
+}
+
+func TestRotatingKeySetAsymmetric(t *testing.T) {
+	privateKey1, err := GenerateEdDSAKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privateKey2, err := GenerateEdDSAKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keySet, err := NewRotatingKeySet(EdDSA, privateKey1, "ed-1", WithRetention(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := keySet.Sign(Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Verify by the published public keys.
+	b, err := MarshalJWKS(keySet.PublicKeys())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwks, err := ParseJWKS(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = VerifyWithHeaderValidator(nil, nil, token, jwks.ValidateHeader); err != nil {
+		t.Fatal(err)
+	}
+
+	// Zero retention: the previous key is retired immediately.
+	if err = keySet.Rotate(privateKey2, "ed-2"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = keySet.Verify(token); err != ErrUnknownKid {
+		t.Fatalf("expected error: ErrUnknownKid but got: %v", err)
+	}
+
+	if _, err = NewRotatingKeySet(EdDSA, testSecret, "ed-1"); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected error: ErrInvalidKey but got: %v", err)
+	}
+}
+
+// Run with -race.
+func TestRotatingKeySetConcurrentRotation(t *testing.T) {
+	keySet, err := NewRotatingKeySet(HS256, []byte("secret-0"), "key-0", WithRetention(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const rotations = 20
+
+	var (
+		wg       sync.WaitGroup
+		done     = make(chan struct{})
+		verified int64
+	)
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				token, err := keySet.Sign(Map{"username": "kataras"})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				// The key may be retired by the rotations in the meantime.
+				if _, err = keySet.Verify(token); err != nil && err != ErrUnknownKid {
+					t.Error(err)
+					return
+				}
+
+				atomic.AddInt64(&verified, 1)
+			}
+		}()
+	}
+
+	for i := 1; i <= rotations; i++ {
+		// Let the signers and verifiers run between the rotations.
+		for atomic.LoadInt64(&verified) < int64(i*10) {
+			runtime.Gosched()
+		}
+
+		if err := keySet.Rotate([]byte(fmt.Sprintf("secret-%d", i)), fmt.Sprintf("key-%d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	close(done)
+	wg.Wait()
+
+	if expected, got := fmt.Sprintf("key-%d", rotations), keySet.Primary(); expected != got {
+		t.Fatalf("expected primary kid: %q but got: %q", expected, got)
+	}
+
+}
+
+func mustVerifyKid(t *testing.T, keySet *RotatingKeySet, token []byte) string {
+	t.Helper()
+
+	verifiedToken, err := keySet.Verify(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return verifiedToken.Kid()
+}
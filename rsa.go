@@ -5,7 +5,7 @@ import (
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
-	"encoding/pem"
+	"errors"
 	"fmt"
 )
 
@@ -36,21 +36,25 @@ func (a *algRSA) Name() string {
 	return a.name
 }
 
+// Sign accepts an *rsa.PrivateKey or any crypto.Signer of an RSA public key,
+// e.g. a key parsed by x509.ParsePKCS8PrivateKey as a crypto.PrivateKey or a hardware-backed key.
 func (a *algRSA) Sign(key PrivateKey, headerAndPayload []byte) ([]byte, error) {
-	privateKey, ok := key.(*rsa.PrivateKey)
-	if !ok {
-		return nil, ErrInvalidKey
-	}
-
-	h := a.hasher.New()
 	// header.payload
-	_, err := h.Write(headerAndPayload)
-	if err != nil {
-		return nil, err
-	}
+	switch privateKey := key.(type) {
+	case *rsa.PrivateKey:
+		hashed := sumHash(a.hasher, headerAndPayload)
+		return rsa.SignPKCS1v15(rand.Reader, privateKey, a.hasher, hashed)
+	case crypto.Signer:
+		if _, ok := privateKey.Public().(*rsa.PublicKey); !ok {
+			return nil, ErrInvalidKey
+		}
 
-	hashed := h.Sum(nil)
-	return rsa.SignPKCS1v15(rand.Reader, privateKey, a.hasher, hashed)
+		// A crypto.Hash as the signer options results to a PKCS #1 v1.5 signature.
+		hashed := sumHash(a.hasher, headerAndPayload)
+		return privateKey.Sign(rand.Reader, hashed, a.hasher)
+	default:
+		return nil, ErrInvalidKey
+	}
 }
 
 func (a *algRSA) Verify(key PublicKey, headerAndPayload []byte, signature []byte) error {
@@ -63,15 +67,9 @@ func (a *algRSA) Verify(key PublicKey, headerAndPayload []byte, signature []byte
 		}
 	}
 
-	h := a.hasher.New()
 	// header.payload
-	_, err := h.Write(headerAndPayload)
-	if err != nil {
-		return err
-	}
-
-	hashed := h.Sum(nil)
-	if err = rsa.VerifyPKCS1v15(publicKey, a.hasher, hashed, signature); err != nil {
+	hashed := sumHash(a.hasher, headerAndPayload)
+	if err := rsa.VerifyPKCS1v15(publicKey, a.hasher, hashed, signature); err != nil {
 		return fmt.Errorf("%w: %v", ErrTokenSignature, err)
 	}
 
@@ -80,6 +78,16 @@ func (a *algRSA) Verify(key PublicKey, headerAndPayload []byte, signature []byte
 
 // Key Helpers.
 
+// GenerateRSAKeys generates a random RSA private key of the given bit size, e.g. 2048.
+// The private key can be passed to the RS and PS algorithms for signing
+// and its `PublicKey` field (pointer) for verification.
+//
+// Useful for development and tests, production keys should be
+// loaded through the `LoadPrivateKeyRSA` and `LoadPublicKeyRSA` functions.
+func GenerateRSAKeys(bits int) (*rsa.PrivateKey, error) {
+	return rsa.GenerateKey(rand.Reader, bits)
+}
+
 // MustLoadRSA accepts private and public PEM file paths
 // and returns a pair of private and public RSA keys.
 // Pass the returned private key to the `Token` (signing) function
@@ -136,16 +144,15 @@ func LoadPublicKeyRSA(filename string) (*rsa.PublicKey, error) {
 
 // ParsePrivateKeyRSA decodes and parses the
 // PEM-encoded RSA private key's raw contents.
+// Both PKCS #1 ("RSA PRIVATE KEY") and PKCS #8 ("PRIVATE KEY") encodings are supported,
+// the PEM armor is optional (raw DER-encoded keys are accepted too).
 // Pass the result to the `Token` (signing) function.
 func ParsePrivateKeyRSA(key []byte) (*rsa.PrivateKey, error) {
-	block, _ := pem.Decode(key)
-	if block == nil {
-		return nil, fmt.Errorf("private key: malformed or missing PEM format (RSA)")
-	}
+	der, isPEM := decodePEM(key)
 
-	privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	privateKey, err := x509.ParsePKCS1PrivateKey(der)
 	if err != nil {
-		if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
 			pKey, ok := key.(*rsa.PrivateKey)
 			if !ok {
 				return nil, fmt.Errorf("private key: expected a type of *rsa.PrivateKey")
@@ -153,6 +160,10 @@ func ParsePrivateKeyRSA(key []byte) (*rsa.PrivateKey, error) {
 
 			privateKey = pKey
 		} else {
+			if !isPEM {
+				return nil, fmt.Errorf("private key: malformed or missing PEM format (RSA)")
+			}
+
 			return nil, err
 		}
 	}
@@ -160,20 +171,47 @@ func ParsePrivateKeyRSA(key []byte) (*rsa.PrivateKey, error) {
 	return privateKey, nil
 }
 
+// ParsePrivateKeyRSAWithPassword same as `ParsePrivateKeyRSA` but it decrypts
+// a passphrase-protected PEM-encoded RSA private key first.
+// Both the PKCS #8 ("ENCRYPTED PRIVATE KEY") and the legacy
+// OpenSSL ("Proc-Type: 4,ENCRYPTED") encryptions are supported.
+//
+// It returns the `ErrIncorrectPassword` error if the "password" is wrong,
+// any other error means that the key is malformed or its encryption is not supported.
+func ParsePrivateKeyRSAWithPassword(key, password []byte) (*rsa.PrivateKey, error) {
+	der, err := decryptPEM(key, password)
+	if err != nil {
+		if errors.Is(err, ErrIncorrectPassword) {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("private key: %w (RSA)", err)
+	}
+
+	return ParsePrivateKeyRSA(der)
+}
+
 // ParsePublicKeyRSA decodes and parses the
 // PEM-encoded RSA public key's raw contents.
+// PKIX ("PUBLIC KEY"), PKCS #1 ("RSA PUBLIC KEY") and certificate encodings are supported,
+// the PEM armor is optional (raw DER-encoded keys are accepted too).
 // Pass the result to the `Verify` function.
 func ParsePublicKeyRSA(key []byte) (*rsa.PublicKey, error) {
-	block, _ := pem.Decode(key)
-	if block == nil {
-		return nil, fmt.Errorf("public key: malformed or missing PEM format (RSA)")
-	}
+	der, isPEM := decodePEM(key)
 
-	parsedKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	parsedKey, err := x509.ParsePKIXPublicKey(der)
 	if err != nil {
-		if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+		if publicKey, err := x509.ParsePKCS1PublicKey(der); err == nil {
+			return publicKey, nil
+		}
+
+		if cert, err := x509.ParseCertificate(der); err == nil {
 			parsedKey = cert.PublicKey
 		} else {
+			if !isPEM {
+				return nil, fmt.Errorf("public key: malformed or missing PEM format (RSA)")
+			}
+
 			return nil, err
 		}
 	}
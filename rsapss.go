@@ -39,14 +39,8 @@ func (a *algRSAPSS) Sign(key PrivateKey, headerAndPayload []byte) ([]byte, error
 		return nil, ErrInvalidKey
 	}
 
-	h := a.opts.Hash.New()
 	// header.payload
-	_, err := h.Write(headerAndPayload)
-	if err != nil {
-		return nil, err
-	}
-
-	hashed := h.Sum(nil)
+	hashed := sumHash(a.opts.Hash, headerAndPayload)
 	return rsa.SignPSS(rand.Reader, privateKey, a.opts.Hash, hashed, a.opts)
 }
 
@@ -60,16 +54,9 @@ func (a *algRSAPSS) Verify(key PublicKey, headerAndPayload []byte, signature []b
 		}
 	}
 
-	h := a.opts.Hash.New()
 	// header.payload
-	_, err := h.Write(headerAndPayload)
-	if err != nil {
-		return err
-	}
-
-	hashed := h.Sum(nil)
-
-	if err = rsa.VerifyPSS(publicKey, a.opts.Hash, hashed, signature, a.opts); err != nil {
+	hashed := sumHash(a.opts.Hash, headerAndPayload)
+	if err := rsa.VerifyPSS(publicKey, a.opts.Hash, hashed, signature, a.opts); err != nil {
 		return fmt.Errorf("%w: %v", ErrTokenSignature, err)
 	}
 
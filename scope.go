@@ -0,0 +1,143 @@
+package jwt
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// RequireScopes returns a net/http middleware which allows
+// requests of verified tokens that contain all the given "scopes".
+// The scopes are read from the space-delimited "scope" claim (OAuth 2.0 style),
+// e.g. {"scope": "read:users write:users"}.
+//
+// It responds with 403 Forbidden if any of the required scopes is missing.
+// It depends on the `Middleware` to verify the token first,
+// it responds with 500 Internal Server Error if the request's context
+// does not contain a verified token.
+//
+// Example Code:
+//
+//	verify := jwt.Middleware(jwt.HS256, sharedKey)
+//	requireAdmin := jwt.RequireScopes("admin")
+//	http.Handle("/admin", verify(requireAdmin(adminHandler)))
+func RequireScopes(scopes ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			verifiedToken := Get(r)
+			if verifiedToken == nil {
+				writeJSONError(w, http.StatusInternalServerError, "jwt: RequireScopes: missing verified token, register the Middleware first")
+				return
+			}
+
+			if !hasScopes(verifiedToken, scopes) {
+				writeJSONError(w, http.StatusForbidden, http.StatusText(http.StatusForbidden))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func hasScopes(verifiedToken *VerifiedToken, required []string) bool {
+	if len(required) == 0 {
+		return true
+	}
+
+	scopes := verifiedToken.Scopes()
+
+	for _, s := range required {
+		if !containsString(scopes, s) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Scopes returns the scopes of the space-delimited "scope" claim (OAuth 2.0 style),
+// e.g. {"scope": "read:users write:users"} results to ["read:users", "write:users"].
+// It returns nil if the claim is missing, empty or it's not a string.
+func (t *VerifiedToken) Scopes() []string {
+	var claims struct {
+		Scope string `json:"scope"`
+	}
+	if err := t.Claims(&claims); err != nil {
+		return nil
+	}
+
+	scopes := strings.Fields(claims.Scope)
+	if len(scopes) == 0 {
+		return nil
+	}
+
+	return scopes
+}
+
+// RolesOption sets an option of the `VerifiedToken.Roles` method.
+type RolesOption func(*rolesConfig)
+
+type rolesConfig struct {
+	realmAccess bool
+}
+
+// WithRealmAccessRoles is a RolesOption which makes the `VerifiedToken.Roles` method
+// to read the roles of the Keycloak's nested "realm_access" claim as well,
+// e.g. {"realm_access": {"roles": ["admin"]}}.
+func WithRealmAccessRoles() RolesOption {
+	return func(c *rolesConfig) {
+		c.realmAccess = true
+	}
+}
+
+// Roles returns the roles of the "roles" JSON array claim,
+// e.g. {"roles": ["admin", "editor"]}.
+// Pass the `WithRealmAccessRoles` option to include the roles of
+// the Keycloak's "realm_access.roles" claim too.
+// It returns nil if the claims are missing or they are not arrays of strings.
+//
+// Example Code:
+//
+//	for _, role := range verifiedToken.Roles(jwt.WithRealmAccessRoles()) {
+//	  if role == "admin" { ... }
+//	}
+func (t *VerifiedToken) Roles(opts ...RolesOption) []string {
+	var cfg rolesConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var claims struct {
+		Roles       json.RawMessage `json:"roles"`
+		RealmAccess json.RawMessage `json:"realm_access"`
+	}
+	if err := t.Claims(&claims); err != nil {
+		return nil
+	}
+
+	roles := parseRoles(claims.Roles)
+	if cfg.realmAccess && len(claims.RealmAccess) > 0 {
+		var realmAccess struct {
+			Roles json.RawMessage `json:"roles"`
+		}
+		if err := json.Unmarshal(claims.RealmAccess, &realmAccess); err == nil {
+			roles = append(roles, parseRoles(realmAccess.Roles)...)
+		}
+	}
+
+	return roles
+}
+
+func parseRoles(b json.RawMessage) []string {
+	if len(b) == 0 {
+		return nil
+	}
+
+	var roles []string
+	if err := json.Unmarshal(b, &roles); err != nil {
+		return nil
+	}
+
+	return roles
+}
@@ -0,0 +1,130 @@
+package jwt
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRequireScopes(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("OK"))
+	})
+	verify := Middleware(testAlg, testSecret)
+	h := verify(RequireScopes("read:users", "write:users")(ok))
+
+	var tests = []struct {
+		scope          interface{}
+		expectedStatus int
+	}{
+		{"read:users write:users", http.StatusOK},
+		{"  write:users   read:users ", http.StatusOK},
+		{"admin read:users write:users delete:users", http.StatusOK}, // superset.
+		{"read:users", http.StatusForbidden},
+		{"read:users,write:users", http.StatusForbidden},
+		{"read:userswrite:users", http.StatusForbidden},
+		{"", http.StatusForbidden},
+		{nil, http.StatusForbidden},
+		{[]string{"read:users", "write:users"}, http.StatusForbidden}, // not a space-delimited string.
+	}
+
+	for i, tt := range tests {
+		claims := Map{"username": "kataras"}
+		if tt.scope != nil {
+			claims["scope"] = tt.scope
+		}
+
+		token, err := Sign(testAlg, testSecret, claims, MaxAge(time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		w := serveTestRequest(h, withAuthorization("Bearer "+string(token)))
+		if w.Code != tt.expectedStatus {
+			t.Fatalf("[%d] expected status code: %d but got: %d", i, tt.expectedStatus, w.Code)
+		}
+	}
+
+	// Without the Middleware.
+	w := httptest.NewRecorder()
+	RequireScopes("read:users")(ok).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status code: %d but got: %d", http.StatusInternalServerError, w.Code)
+	}
+
+	if expected, got := `{"error":"jwt: RequireScopes: missing verified token, register the Middleware first"}`, w.Body.String(); expected != got {
+		t.Fatalf("expected body: %q but got: %q", expected, got)
+	}
+}
+
+func TestVerifiedTokenScopes(t *testing.T) {
+	var tests = []struct {
+		scope    interface{}
+		expected []string
+	}{
+		{"read:users write:users", []string{"read:users", "write:users"}},
+		{"  admin  ", []string{"admin"}},
+		{"", nil},
+		{nil, nil},
+		{[]string{"read:users"}, nil}, // not a space-delimited string.
+	}
+
+	for i, tt := range tests {
+		claims := Map{"username": "kataras"}
+		if tt.scope != nil {
+			claims["scope"] = tt.scope
+		}
+
+		if got := testVerifiedToken(t, claims).Scopes(); !reflect.DeepEqual(tt.expected, got) {
+			t.Fatalf("[%d] expected scopes: %q but got: %q", i, tt.expected, got)
+		}
+	}
+}
+
+func TestVerifiedTokenRoles(t *testing.T) {
+	keycloak := Map{
+		"roles":        []string{"editor"},
+		"realm_access": Map{"roles": []string{"admin", "offline_access"}},
+	}
+
+	var tests = []struct {
+		claims   Map
+		opts     []RolesOption
+		expected []string
+	}{
+		{Map{"roles": []string{"admin", "editor"}}, nil, []string{"admin", "editor"}},
+		{Map{"roles": []string{}}, nil, []string{}},
+		{Map{"roles": "admin editor"}, nil, nil}, // not an array.
+		{Map{"roles": []int{1}}, nil, nil},
+		{Map{"username": "kataras"}, nil, nil},
+		// Opt-in Keycloak structure.
+		{keycloak, nil, []string{"editor"}},
+		{keycloak, []RolesOption{WithRealmAccessRoles()}, []string{"editor", "admin", "offline_access"}},
+		{Map{"realm_access": Map{"roles": []string{"admin"}}}, []RolesOption{WithRealmAccessRoles()}, []string{"admin"}},
+		{Map{"roles": []string{"editor"}, "realm_access": "admin"}, []RolesOption{WithRealmAccessRoles()}, []string{"editor"}},
+	}
+
+	for i, tt := range tests {
+		if got := testVerifiedToken(t, tt.claims).Roles(tt.opts...); !reflect.DeepEqual(tt.expected, got) {
+			t.Fatalf("[%d] expected roles: %q but got: %q", i, tt.expected, got)
+		}
+	}
+}
+
+func testVerifiedToken(t *testing.T, claims interface{}) *VerifiedToken {
+	t.Helper()
+
+	token, err := Sign(testAlg, testSecret, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return verifiedToken
+}
@@ -1,5 +1,12 @@
 package jwt
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
 // Sign signs and generates a new token based on the algorithm and a secret key.
 // The claims is the payload, the actual body of the token, should
 // contain information about a specific authorized client.
@@ -7,6 +14,7 @@ package jwt
 // therefore it should NOT contain any private information
 // (unless Encrypt/Decrypt functions are set, see GCM function too).
 // See the `Verify` function to decode and verify the result token.
+// The "key" can be an external `Signer` too, e.g. a cloud KMS one.
 //
 // Example Code to pass only standard Claims:
 //
@@ -32,7 +40,24 @@ package jwt
 //	type User struct { Username string `json:"username"` }
 //	token, err := jwt.Sign(jwt.HS256, []byte("secret"), User{Username: "kataras"}, jwt.MaxAge(15 * time.Minute))
 func Sign(alg Alg, key PrivateKey, claims interface{}, opts ...SignOption) ([]byte, error) {
-	return signToken(alg, key, nil, claims, nil, opts...)
+	return SignContext(context.Background(), alg, key, claims, opts...)
+}
+
+// MustSign same as `Sign` but it panics on errors,
+// e.g. for a token signed on startup with a known-good key.
+func MustSign(alg Alg, key PrivateKey, claims interface{}, opts ...SignOption) []byte {
+	token, err := Sign(alg, key, claims, opts...)
+	if err != nil {
+		panicHandler(err)
+	}
+
+	return token
+}
+
+// SignContext same as `Sign` but it accepts a context, e.g. a request-scoped one,
+// which can cancel a slow external signer (see `ContextSigner`).
+func SignContext(ctx context.Context, alg Alg, key PrivateKey, claims interface{}, opts ...SignOption) ([]byte, error) {
+	return appendSignedToken(ctx, nil, alg, key, nil, claims, nil, opts...)
 }
 
 // SignEncrypted same as `Sign` but it encrypts the payload part with the given "encrypt" function.
@@ -52,39 +77,273 @@ func SignEncryptedWithHeader(alg Alg, key PrivateKey, encrypt InjectFunc, claims
 	return signToken(alg, key, encrypt, claims, customHeader, opts...)
 }
 
+// EncodeTo same as `Sign` but it appends the token to "dst"
+// and returns the extended buffer, like the standard library's append-style functions
+// (e.g. time.AppendFormat). The result is byte-identical to the `Sign` one.
+//
+// It is useful to reuse a buffer across requests on high-throughput token issuance.
+//
+// Example Code:
+//
+//	buf := make([]byte, 0, 512)
+//	buf, err := jwt.EncodeTo(buf[:0], jwt.HS256, sharedKey, claims)
+func EncodeTo(dst []byte, alg Alg, key PrivateKey, claims interface{}, opts ...SignOption) ([]byte, error) {
+	return appendSignedToken(context.Background(), dst, alg, key, nil, claims, nil, opts...)
+}
+
 func signToken(alg Alg, key PrivateKey, encrypt InjectFunc, claims interface{}, customHeader interface{}, opts ...SignOption) ([]byte, error) {
+	return appendSignedToken(context.Background(), nil, alg, key, encrypt, claims, customHeader, opts...)
+}
+
+func appendSignedToken(ctx context.Context, dst []byte, alg Alg, key PrivateKey, encrypt InjectFunc, claims interface{}, customHeader interface{}, opts ...SignOption) ([]byte, error) {
+	if observer := signObserver(opts); observer != nil {
+		start := time.Now()
+		token, err := appendUnobservedSignedToken(ctx, dst, alg, key, encrypt, claims, customHeader, opts...)
+		observer.OnSign(alg.Name(), time.Since(start), err)
+		return token, err
+	}
+
+	return appendUnobservedSignedToken(ctx, dst, alg, key, encrypt, claims, customHeader, opts...)
+}
+
+func appendUnobservedSignedToken(ctx context.Context, dst []byte, alg Alg, key PrivateKey, encrypt InjectFunc, claims interface{}, customHeader interface{}, opts ...SignOption) ([]byte, error) {
+	payload, header, err := buildPayload(alg, encrypt, claims, customHeader, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return appendToken(ctx, dst, alg, key, payload, header)
+}
+
+// buildPayload applies the sign options to the "claims" and returns the payload to be encoded
+// and the header, nil for the default one.
+func buildPayload(alg Alg, encrypt InjectFunc, claims interface{}, customHeader interface{}, opts ...SignOption) ([]byte, interface{}, error) {
+	var cfg signConfig
+
 	if len(opts) > 0 {
-		var standardClaims Claims
+		var (
+			standardClaims Claims
+			hasClaims      bool
+		)
+
+		// The config options are applied first, so the claims options
+		// can read the configured clock regardless of their order.
+		for _, opt := range opts {
+			if configOpt, ok := opt.(SignConfigOption); ok && configOpt != nil {
+				configOpt(&cfg)
+			}
+		}
+
+		if cfg.clock != nil {
+			standardClaims.now = cfg.clock()
+		}
+
 		for _, opt := range opts {
 			if opt == nil {
 				continue
 			}
+
+			switch opt.(type) {
+			case SignConfigOption, ObserverOption:
+				continue // see above and appendSignedToken.
+			}
+
 			opt.ApplyClaims(&standardClaims)
+			hasClaims = true
 		}
 
-		claims = Merge(claims, standardClaims)
+		if hasClaims {
+			claims = Merge(claims, standardClaims)
+		}
 	}
 
 	payload, err := Marshal(claims)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	if len(cfg.claimsTransforms) > 0 {
+		payload, err = transformClaims(payload, cfg.claimsTransforms)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if cfg.strictClaims {
+		if err = validateStrictClaims(payload); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if cfg.generateID {
+		payload, err = withGeneratedID(payload)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if cfg.scalarAudience {
+		payload = scalarAudience(payload)
+	}
+
+	if cfg.compress {
+		payload, err = deflate(payload)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
 	if encrypt != nil {
 		payload, err = encrypt(payload)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+	}
+
+	if cfg.err != nil {
+		return nil, nil, cfg.err
+	}
+
+	if len(cfg.header) > 0 {
+		if customHeader == nil {
+			if _, ok := cfg.header["typ"]; ok {
+				customHeader = createHeaderWithoutTyp(alg.Name())
+			} else {
+				customHeader = createHeaderRaw(alg.Name())
+			}
+		}
+
+		header := Merge(customHeader, cfg.header)
+		if header == nil {
+			return nil, nil, errInvalidHeader
 		}
+		customHeader = header
+	}
+
+	return payload, customHeader, nil
+}
+
+var errInvalidHeader = errors.New("jwt: invalid header")
+
+// signConfig holds the configuration of a single signing,
+// it is built through the passed SignConfigOptions.
+type signConfig struct {
+	// header holds extra header fields, e.g. "kid".
+	header map[string]interface{}
+	// err is reported by `Sign` on an invalid option, e.g. WithHeader("alg", ...).
+	err error
+	// compress deflates the payload, see `WithCompression`.
+	compress bool
+	// scalarAudience emits a single-element "aud" as a string, see `WithScalarAudience`.
+	scalarAudience bool
+	// generateID sets a random "jti" claim, see `WithGeneratedID`.
+	generateID bool
+	// strictClaims rejects inconsistent time claims, see `WithStrictClaims`.
+	strictClaims bool
+	// clock overrides the `Clock` package-level variable, see `WithSignClock`.
+	clock func() time.Time
+	// claimsTransforms modify the assembled claims, see `WithClaimsTransform`.
+	claimsTransforms []func(map[string]interface{}) map[string]interface{}
+}
+
+func (c *signConfig) setHeader(key string, value interface{}) {
+	if c.header == nil {
+		c.header = make(map[string]interface{})
+	}
+
+	c.header[key] = value
+}
+
+// SignConfigOption is a SignOption which modifies the signing process itself
+// instead of setting standard claims, e.g. `WithKID`.
+// It can be passed at the last variadic input argument of the `Sign` function,
+// mixed with any other SignOption.
+type SignConfigOption func(*signConfig)
+
+var _ SignOption = SignConfigOption(nil)
+
+// ApplyClaims completes the `SignOption` interface.
+// It does nothing as the option is applied to the signing process.
+func (opt SignConfigOption) ApplyClaims(*Claims) {}
+
+// WithClaims is a SignOption which merges the given standard claims
+// to the token's payload, it's the explicit form of passing a `Claims` value.
+//
+// It composes with any other SignOption, e.g. `MaxAge`.
+// Precedence: the sign options are applied in order and a non-zero field
+// of a later option overrides the same field of a previous one,
+// e.g. WithClaims(Claims{Expiry: x}) after MaxAge(d) keeps the "x" expiration.
+// The resulting standard claims are appended to the base claims (see `Merge`),
+// so a field should not be set to both of them; when it does, the option's value,
+// which comes last, is the one this package (and most JSON decoders) reads.
+// Pass the claims through `MergeUnique` to guarantee a single key per field.
+//
+// Example Code:
+//
+//	token, err := jwt.Sign(jwt.HS256, key, claims, jwt.MaxAge(15*time.Minute), jwt.WithClaims(jwt.Claims{
+//	  Issuer:  "my-app",
+//	  Subject: "user-id",
+//	}))
+func WithClaims(claims Claims) SignOption {
+	return claims
+}
+
+// WithKID is a SignOption which sets the "kid" (key ID) header field
+// of the generated token. The verifier can read it through
+// the `VerifiedToken.Kid` method or before verification through `PeekHeader`.
+//
+// Example Code:
+//
+//	token, err := jwt.Sign(jwt.RS256, privateKey, claims, jwt.WithKID("my-key-id"))
+func WithKID(id string) SignConfigOption {
+	return func(c *signConfig) {
+		c.setHeader("kid", id)
+	}
+}
+
+// WithSignClock is a SignOption which sets the current time source of a single signing,
+// the `MaxAge` option computes the "iat" and "exp" claims against its result
+// instead of the `Clock` package-level variable.
+// Unlike modifying `Clock`, it is safe for concurrent use. See `WithClock` for the verify functions.
+//
+// Example Code:
+//
+//	token, err := jwt.Sign(jwt.HS256, sharedKey, claims, jwt.MaxAge(15*time.Minute), jwt.WithSignClock(frozenClock))
+func WithSignClock(clock func() time.Time) SignConfigOption {
+	return func(c *signConfig) {
+		c.clock = clock
 	}
+}
+
+// WithHeader is a SignOption which sets a custom header field of the generated token,
+// e.g. "cty" or "x5t". A "typ" field replaces the default "JWT" value.
+// The "alg" field can not be set, the `Sign` function returns an error instead.
+// The verifier can read the fields through the `VerifiedToken.Headers` method.
+//
+// Example Code:
+//
+//	token, err := jwt.Sign(jwt.RS256, privateKey, claims, jwt.WithHeader("x5t", thumbprint))
+func WithHeader(key string, value interface{}) SignConfigOption {
+	return func(c *signConfig) {
+		if key == "alg" {
+			c.err = fmt.Errorf("%w: the alg field can not be overridden", errInvalidHeader)
+			return
+		}
 
-	return encodeToken(alg, key, payload, customHeader)
+		c.setHeader(key, value)
+	}
 }
 
 // SignOption is just a helper which sets the standard claims at the `Sign` function.
+// The options are applied in order and they can be mixed freely.
 //
 // Available SignOptions:
 // - MaxAge(time.Duration)
 // - Claims{}
+// - WithClaims(Claims)
+// - WithKID(string)
+// - WithSignClock(func() time.Time)
+// - any other SignConfigOption, e.g. WithHeader, WithCompression and WithGeneratedID.
 type SignOption interface {
 	// ApplyClaims should apply standard claims.
 	// Accepts the destination claims.
@@ -1,6 +1,8 @@
 package jwt
 
 import (
+	"bytes"
+	"errors"
 	"reflect"
 	"testing"
 	"time"
@@ -59,3 +61,328 @@ func TestSignOption(t *testing.T) {
 		t.Fatalf("expected custom claims:\n%#+v\n\nbut got:\n%#+v", expectedCustomClaims, got)
 	}
 }
+
+func TestSignWithClaims(t *testing.T) {
+	prevClock := Clock
+	defer func() {
+		Clock = prevClock
+	}()
+
+	now := time.Date(2020, 10, 26, 1, 1, 1, 0, time.UTC)
+	Clock = func() time.Time {
+		return now
+	}
+
+	var tests = []struct {
+		opts     []SignOption
+		expected Claims
+	}{
+		{
+			opts: []SignOption{WithClaims(Claims{Issuer: "my-app", Subject: "user-1"}), MaxAge(time.Minute)},
+			expected: Claims{
+				Issuer:   "my-app",
+				Subject:  "user-1",
+				IssuedAt: now.Unix(),
+				Expiry:   now.Add(time.Minute).Unix(),
+			},
+		},
+		{ // later option overrides a previous one.
+			opts: []SignOption{MaxAge(time.Minute), WithClaims(Claims{Expiry: now.Add(time.Hour).Unix()})},
+			expected: Claims{
+				IssuedAt: now.Unix(),
+				Expiry:   now.Add(time.Hour).Unix(),
+			},
+		},
+		{ // zero fields do not override.
+			opts: []SignOption{WithClaims(Claims{Issuer: "my-app"}), WithClaims(Claims{Subject: "user-1"})},
+			expected: Claims{
+				Issuer:  "my-app",
+				Subject: "user-1",
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		token, err := Sign(testAlg, testSecret, Map{"username": "kataras"}, tt.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		verifiedToken, err := Verify(testAlg, testSecret, token)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := verifiedToken.StandardClaims; !reflect.DeepEqual(got, tt.expected) {
+			t.Fatalf("[%d] expected standard claims:\n%#+v\n\nbut got:\n%#+v", i, tt.expected, got)
+		}
+	}
+
+	// A field set to both the base claims and the option: the option's value is read.
+	token, err := Sign(testAlg, testSecret, Map{"iss": "base"}, WithClaims(Claims{Issuer: "option"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "option", verifiedToken.StandardClaims.Issuer; expected != got {
+		t.Fatalf("expected issuer: %q but got: %q", expected, got)
+	}
+}
+
+func TestSignWithKID(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"}, WithKID("key-1"), MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	headerDecoded, err := Base64Decode(bytes.Split(token, sep)[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []byte(`"kid":"key-1"`); !bytes.Contains(headerDecoded, expected) {
+		t.Fatalf("expected header: %s to contain: %s", headerDecoded, expected)
+	}
+
+	header, err := PeekHeader(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "key-1", header["kid"]; expected != got {
+		t.Fatalf("expected kid: %q but got: %v", expected, got)
+	}
+
+	if expected, got := testAlg.Name(), header["alg"]; expected != got {
+		t.Fatalf("expected alg: %q but got: %v", expected, got)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "key-1", verifiedToken.Kid(); expected != got {
+		t.Fatalf("expected verified token kid: %q but got: %q", expected, got)
+	}
+
+	if verifiedToken.StandardClaims.Expiry == 0 {
+		t.Fatalf("expected exp claim to be set")
+	}
+
+	// Only sign config options, the claims should be kept as they are.
+	token, err = Sign(testAlg, testSecret, Map{"username": "kataras"}, WithKID("key-2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err = Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := `{"username":"kataras"}`, string(verifiedToken.Payload); expected != got {
+		t.Fatalf("expected payload: %s but got: %s", expected, got)
+	}
+
+	if expected, got := "key-2", verifiedToken.Kid(); expected != got {
+		t.Fatalf("expected verified token kid: %q but got: %q", expected, got)
+	}
+
+	// Without kid.
+	verifiedToken, err = Verify(testAlg, testSecret, testToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := verifiedToken.Kid(); got != "" {
+		t.Fatalf("expected empty kid but got: %q", got)
+	}
+}
+
+func TestSignWithHeader(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"},
+		WithHeader("cty", "example"), WithHeader("x5t", "dGh1bWJwcmludA"), WithKID("key-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	headerDecoded, err := Base64Decode(bytes.Split(token, sep)[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []byte(`{"alg":"HS256","typ":"JWT",`); !bytes.HasPrefix(headerDecoded, expected) {
+		t.Fatalf("expected header: %s to start with: %s", headerDecoded, expected)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header, err := verifiedToken.Headers()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedHeader := Map{"alg": "HS256", "typ": "JWT", "cty": "example", "x5t": "dGh1bWJwcmludA", "kid": "key-1"}
+	if !reflect.DeepEqual(expectedHeader, header) {
+		t.Fatalf("expected header: %#v but got: %#v", expectedHeader, header)
+	}
+
+	// The "typ" replaces the default one.
+	token, err = Sign(testAlg, testSecret, Map{"username": "kataras"}, WithHeader("typ", "at+jwt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err = Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := `{"alg":"HS256","typ":"at+jwt"}`, string(verifiedToken.Header); expected != got {
+		t.Fatalf("expected header: %s but got: %s", expected, got)
+	}
+
+	// The "alg" can not be overridden.
+	if _, err = Sign(testAlg, testSecret, Map{"username": "kataras"}, WithHeader("alg", "none")); !errors.Is(err, errInvalidHeader) {
+		t.Fatalf("expected error: errInvalidHeader but got: %v", err)
+	}
+}
+
+func TestComposeOptions(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"typ": TokenTypeAccess},
+		Claims{Issuer: "my-app"}, MaxAge(time.Minute), WithKID("key-1"), WithGeneratedID())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blocklist := NewBlocklist(0)
+	verifiedToken, err := Verify(testAlg, testSecret, token,
+		WithLeeway(time.Second), WithExpectedType(TokenTypeAccess), WithBlocklist(blocklist), Expected{Issuer: "my-app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "key-1", verifiedToken.Kid(); expected != got {
+		t.Fatalf("expected kid: %q but got: %q", expected, got)
+	}
+
+	claims := verifiedToken.StandardClaims
+	if claims.ID == "" || claims.Expiry == 0 || claims.Issuer != "my-app" {
+		t.Fatalf("expected all the sign options to be applied but got: %#+v", claims)
+	}
+
+	if err = blocklist.InvalidateToken(token, claims); err != nil {
+		t.Fatal(err)
+	}
+
+	// A failure of any composed option fails the verification.
+	if _, err = Verify(testAlg, testSecret, token,
+		WithLeeway(time.Second), WithExpectedType(TokenTypeAccess), WithBlocklist(blocklist)); err != ErrBlocked {
+		t.Fatalf("expected error: ErrBlocked but got: %v", err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token,
+		WithLeeway(time.Second), WithExpectedType(TokenTypeRefresh), Expected{Issuer: "my-app"}); err != ErrInvalidType {
+		t.Fatalf("expected error: ErrInvalidType but got: %v", err)
+	}
+}
+
+func TestEncodeTo(t *testing.T) {
+	claims := Map{"username": "kataras"}
+
+	expected, err := Sign(testAlg, testSecret, claims, WithKID("kid-1"), Claims{Expiry: 1893456000})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prefix := []byte("Bearer ")
+	buf := make([]byte, 0, 512)
+	for i := 0; i < 3; i++ {
+		buf = append(buf[:0], prefix...)
+
+		buf, err = EncodeTo(buf, testAlg, testSecret, claims, WithKID("kid-1"), Claims{Expiry: 1893456000})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.HasPrefix(buf, prefix) {
+			t.Fatalf("expected dst content to be kept")
+		}
+
+		if got := buf[len(prefix):]; !bytes.Equal(expected, got) {
+			t.Fatalf("expected token:\n%s\nbut got:\n%s", expected, got)
+		}
+	}
+
+	// Nil dst.
+	token, err := EncodeTo(nil, testAlg, testSecret, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(testToken, token) {
+		t.Fatalf("expected token:\n%s\nbut got:\n%s", testToken, token)
+	}
+
+	if _, err = EncodeTo(nil, testAlg, invalidKey, claims); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected error: ErrInvalidKey but got: %v", err)
+	}
+}
+
+func BenchmarkSign(b *testing.B) {
+	claims := Map{"username": "kataras"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Sign(testAlg, testSecret, claims); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncodeTo(b *testing.B) {
+	claims := Map{"username": "kataras"}
+	buf := make([]byte, 0, 512)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var err error
+		if buf, err = EncodeTo(buf[:0], testAlg, testSecret, claims); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestMustSign(t *testing.T) {
+	var token []byte
+	catchPanic(t, false, func() {
+		token = MustSign(testAlg, testSecret, Map{"username": "kataras"})
+	})
+
+	if _, err := Verify(testAlg, testSecret, token); err != nil {
+		t.Fatal(err)
+	}
+
+	catchPanic(t, true, func() {
+		MustSign(testAlg, invalidKey, Map{"username": "kataras"})
+	})
+
+	defer func() {
+		if err, ok := recover().(error); !ok || !errors.Is(err, ErrInvalidKey) {
+			t.Fatalf("expected a panic of ErrInvalidKey but got: %v", err)
+		}
+	}()
+
+	MustSign(testAlg, invalidKey, Map{"username": "kataras"})
+}
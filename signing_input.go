@@ -0,0 +1,86 @@
+package jwt
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+)
+
+// SigningInput returns the base64url encoded header and payload of a token,
+// separated by a dot (RFC 7515, Section 5.1), the input to be signed.
+// The "alg" sets the "alg" header field, the "claims" and "opts" are the same as `Sign`.
+//
+// It is useful when the private key never leaves a device (e.g. an HSM or a cloud KMS):
+// the input is signed externally and the token is assembled through `AssembleToken`.
+// Note that the external signature must be of the JWS format of the algorithm,
+// e.g. R || S for ECDSA (RFC 7518, Section 3.4) instead of ASN.1 DER.
+//
+// Example Code:
+//
+//	input, err := jwt.SigningInput(jwt.RS256, claims, jwt.MaxAge(15*time.Minute))
+//	signature, err := kms.Sign(sha256(input))
+//	token := jwt.AssembleToken(input, signature)
+func SigningInput(alg Alg, claims interface{}, opts ...SignOption) ([]byte, error) {
+	payload, header, err := buildPayload(alg, nil, claims, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return appendSigningInput(nil, alg, payload, header)
+}
+
+// AssembleToken returns the token of compact form of the signing "input"
+// (see `SigningInput`) and its raw "signature", which is base64url encoded.
+func AssembleToken(input, signature []byte) []byte {
+	token := make([]byte, 0, len(input)+1+base64.RawURLEncoding.EncodedLen(len(signature)))
+	token = append(token, input...)
+	token = append(token, sep[0])
+	return appendBase64(token, signature)
+}
+
+// Signer is an external signer of tokens, e.g. a cloud KMS or a PKCS #11 device,
+// which holds the private key. It can be passed as the key of the `Sign` functions.
+// The verification still uses the matching public key.
+type Signer interface {
+	// Sign returns the signature of the "input" (see `SigningInput`),
+	// of the JWS format of its algorithm, and the algorithm's name, e.g. "RS256".
+	// The name must match the algorithm passed to `Sign`.
+	Sign(input []byte) (signature []byte, alg string, err error)
+}
+
+// ContextSigner is a `Signer` which accepts a context,
+// e.g. to cancel a network call to a remote KMS. See `SignContext`.
+type ContextSigner interface {
+	Signer
+	// SignContext same as `Sign` but it accepts a context.
+	SignContext(ctx context.Context, input []byte) (signature []byte, alg string, err error)
+}
+
+// sign signs the "headerPayload" through the "key" if it's a Signer,
+// otherwise through the "alg".
+func sign(ctx context.Context, alg Alg, key PrivateKey, headerPayload []byte) ([]byte, error) {
+	signer, ok := key.(Signer)
+	if !ok {
+		return alg.Sign(key, headerPayload)
+	}
+
+	var (
+		signature []byte
+		signerAlg string
+		err       error
+	)
+	if ctxSigner, ok := signer.(ContextSigner); ok {
+		signature, signerAlg, err = ctxSigner.SignContext(ctx, headerPayload)
+	} else if err = ctx.Err(); err == nil {
+		signature, signerAlg, err = signer.Sign(headerPayload)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if signerAlg != alg.Name() {
+		return nil, fmt.Errorf("%w: signer algorithm: %q", ErrTokenAlg, signerAlg)
+	}
+
+	return signature, nil
+}
@@ -0,0 +1,186 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSigningInput(t *testing.T) {
+	privateKey, publicKey := MustLoadRSA("./_testfiles/rsa_private_key.pem", "./_testfiles/rsa_public_key.pem")
+
+	input, err := SigningInput(RS256, Map{"username": "kataras"}, MaxAge(time.Minute), WithKID("key-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := bytes.Count(input, sep); n != 1 {
+		t.Fatalf("expected header.payload but got: %d dots", n)
+	}
+
+	// An external signer, e.g. a cloud KMS, which receives the digest only.
+	digest := sha256.Sum256(input)
+	signature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := AssembleToken(input, signature)
+
+	verifiedToken, err := Verify(RS256, publicKey, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "key-1", verifiedToken.Kid(); expected != got {
+		t.Fatalf("expected kid: %q but got: %q", expected, got)
+	}
+
+	var claims Map
+	if err = verifiedToken.Claims(&claims); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "kataras", claims["username"]; expected != got {
+		t.Fatalf("expected username: %q but got: %v", expected, got)
+	}
+
+	// Same result as Sign for deterministic algorithms.
+	input, err = SigningInput(HS256, Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signature, err = HS256.Sign(testSecret, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedToken, err := Sign(HS256, testSecret, Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := AssembleToken(input, signature); !bytes.Equal(expectedToken, got) {
+		t.Fatalf("expected token:\n%s\nbut got:\n%s", expectedToken, got)
+	}
+
+	// A signature of a wrong key.
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input, err = SigningInput(RS256, Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	digest = sha256.Sum256(input)
+	signature, err = rsa.SignPKCS1v15(rand.Reader, otherKey, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(RS256, publicKey, AssembleToken(input, signature)); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected error: ErrInvalidSignature but got: %v", err)
+	}
+}
+
+// testKMSSigner is a mock of an external signer which delegates to an in-process RSA key.
+type testKMSSigner struct {
+	privateKey *rsa.PrivateKey
+	alg        string
+	calls      int
+}
+
+func (s *testKMSSigner) Sign(input []byte) ([]byte, string, error) {
+	s.calls++
+	digest := sha256.Sum256(input)
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.privateKey, crypto.SHA256, digest[:])
+	return signature, s.alg, err
+}
+
+func TestSignWithSigner(t *testing.T) {
+	privateKey, publicKey := MustLoadRSA("./_testfiles/rsa_private_key.pem", "./_testfiles/rsa_public_key.pem")
+	signer := &testKMSSigner{privateKey: privateKey, alg: "RS256"}
+
+	token, err := Sign(RS256, signer, Map{"username": "kataras"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := 1, signer.calls; expected != got {
+		t.Fatalf("expected signer to be called: %d time but got: %d", expected, got)
+	}
+
+	if _, err = Verify(RS256, publicKey, token); err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := EncodeTo(nil, RS256, signer, Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(RS256, publicKey, buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// The signer's algorithm must match.
+	signer.alg = "RS512"
+	if _, err = Sign(RS256, signer, Map{"username": "kataras"}); !errors.Is(err, ErrTokenAlg) {
+		t.Fatalf("expected error: ErrTokenAlg but got: %v", err)
+	}
+}
+
+type testContextKMSSigner struct {
+	*testKMSSigner
+}
+
+func (s testContextKMSSigner) SignContext(ctx context.Context, input []byte) ([]byte, string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
+
+	return s.Sign(input)
+}
+
+func TestSignContext(t *testing.T) {
+	privateKey, publicKey := MustLoadRSA("./_testfiles/rsa_private_key.pem", "./_testfiles/rsa_public_key.pem")
+	signer := &testKMSSigner{privateKey: privateKey, alg: "RS256"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	token, err := SignContext(ctx, RS256, testContextKMSSigner{signer}, Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = VerifyContext(ctx, RS256, publicKey, token); err != nil {
+		t.Fatal(err)
+	}
+
+	cancel()
+	if _, err = SignContext(ctx, RS256, testContextKMSSigner{signer}, Map{"username": "kataras"}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected error: context.Canceled but got: %v", err)
+	}
+
+	// A Signer without context support is not called on a canceled context.
+	if _, err = SignContext(ctx, RS256, signer, Map{"username": "kataras"}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected error: context.Canceled but got: %v", err)
+	}
+
+	if expected, got := 1, signer.calls; expected != got {
+		t.Fatalf("expected signer to be called: %d time but got: %d", expected, got)
+	}
+
+	if _, err = VerifyContext(ctx, RS256, publicKey, token); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected error: context.Canceled but got: %v", err)
+	}
+}
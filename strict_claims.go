@@ -0,0 +1,68 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrInconsistentClaims indicates that the time claims of a token to be signed
+// can never be valid together, e.g. its "nbf" is after its "exp".
+// See `WithStrictClaims`.
+var ErrInconsistentClaims = errors.New("jwt: inconsistent claims")
+
+// WithStrictClaims is a SignOption which makes `Sign` to fail with an
+// `ErrInconsistentClaims` error if the token would never be valid:
+// its "nbf" claim is not before its "exp" one or its "iat" claim is after its "exp" one.
+// It catches miscomputed durations at sign time instead of at verification.
+// Missing (zero) claims are not checked.
+//
+// Example Code:
+//
+//	token, err := jwt.Sign(jwt.HS256, sharedKey, claims, jwt.WithStrictClaims())
+func WithStrictClaims() SignConfigOption {
+	return func(c *signConfig) {
+		c.strictClaims = true
+	}
+}
+
+// validateStrictClaims checks the consistency of the time claims of the JSON "payload".
+// A non-JSON payload has no claims to check.
+func validateStrictClaims(payload []byte) error {
+	claims, ok := scanTimeClaims(payload)
+	if !ok {
+		var raw struct {
+			NotBefore json.RawMessage `json:"nbf"`
+			IssuedAt  json.RawMessage `json:"iat"`
+			Expiry    json.RawMessage `json:"exp"`
+		}
+		if err := json.Unmarshal(payload, &raw); err != nil {
+			return nil
+		}
+
+		var err error
+		if claims.NotBefore, err = parseNumericDate("nbf", raw.NotBefore, true); err != nil {
+			return err
+		}
+		if claims.IssuedAt, err = parseNumericDate("iat", raw.IssuedAt, true); err != nil {
+			return err
+		}
+		if claims.Expiry, err = parseNumericDate("exp", raw.Expiry, true); err != nil {
+			return err
+		}
+	}
+
+	if claims.Expiry == 0 {
+		return nil
+	}
+
+	if claims.NotBefore != 0 && claims.NotBefore >= claims.Expiry {
+		return fmt.Errorf("%w: nbf (%d) is not before exp (%d)", ErrInconsistentClaims, claims.NotBefore, claims.Expiry)
+	}
+
+	if claims.IssuedAt > claims.Expiry {
+		return fmt.Errorf("%w: iat (%d) is after exp (%d)", ErrInconsistentClaims, claims.IssuedAt, claims.Expiry)
+	}
+
+	return nil
+}
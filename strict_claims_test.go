@@ -0,0 +1,41 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithStrictClaims(t *testing.T) {
+	now := time.Now().Unix()
+
+	var tests = []struct {
+		name   string
+		claims interface{}
+		err    error
+	}{
+		{"valid", Claims{NotBefore: now, IssuedAt: now, Expiry: now + 60}, nil},
+		{"no expiry", Claims{NotBefore: now + 60, IssuedAt: now}, nil},
+		{"no time claims", Map{"username": "kataras"}, nil},
+		{"nbf after exp", Claims{NotBefore: now + 120, Expiry: now + 60}, ErrInconsistentClaims},
+		{"nbf equals exp", Claims{NotBefore: now + 60, Expiry: now + 60}, ErrInconsistentClaims},
+		{"iat after exp", Claims{IssuedAt: now + 120, Expiry: now + 60}, ErrInconsistentClaims},
+		{"float nbf after exp", Map{"nbf": float64(now) + 120.5, "exp": float64(now) + 60.5}, ErrInconsistentClaims},
+		{"string iat after exp", Map{"iat": "9999999999", "exp": now}, ErrInconsistentClaims},
+	}
+
+	for _, tt := range tests {
+		if _, err := Sign(testAlg, testSecret, tt.claims); err != nil {
+			t.Fatalf("[%s] expected lenient sign by default but got: %v", tt.name, err)
+		}
+
+		if _, err := Sign(testAlg, testSecret, tt.claims, WithStrictClaims()); !errors.Is(err, tt.err) {
+			t.Fatalf("[%s] expected error: %v but got: %v", tt.name, tt.err, err)
+		}
+	}
+
+	// Sign options are merged before the check.
+	if _, err := Sign(testAlg, testSecret, Map{"nbf": now + 3600}, MaxAge(time.Minute), WithStrictClaims()); !errors.Is(err, ErrInconsistentClaims) {
+		t.Fatalf("expected error: ErrInconsistentClaims but got: %v", err)
+	}
+}
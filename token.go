@@ -2,9 +2,11 @@ package jwt
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"sync"
 )
 
 var (
@@ -12,6 +14,11 @@ var (
 	ErrMissing = errors.New("jwt: token is empty")
 	// ErrTokenForm indicates that the extracted token has not the expected form .
 	ErrTokenForm = errors.New("jwt: invalid token form")
+	// ErrMalformed indicates that the token is structurally invalid:
+	// it has not three dot-separated parts or a part is not valid base64.
+	// It is an alias of ErrTokenForm, compare errors with errors.Is
+	// as the base64 errors are wrapped.
+	ErrMalformed = ErrTokenForm
 	// ErrTokenAlg indicates that the given algorithm does not match the extracted one.
 	ErrTokenAlg = errors.New("jwt: unexpected token algorithm")
 )
@@ -24,6 +31,44 @@ type (
 )
 
 func encodeToken(alg Alg, key PrivateKey, payload []byte, customHeader interface{}) ([]byte, error) {
+	return appendToken(context.Background(), nil, alg, key, payload, customHeader)
+}
+
+// appendToken appends the token of compact form to "dst" and returns the extended buffer.
+func appendToken(ctx context.Context, dst []byte, alg Alg, key PrivateKey, payload []byte, customHeader interface{}) ([]byte, error) {
+	buf := acquireEncodeBuffer()
+	defer releaseEncodeBuffer(buf)
+
+	// header.payload
+	headerPayload, err := appendSigningInput(*buf, alg, payload, customHeader)
+	if err != nil {
+		return nil, err
+	}
+	*buf = headerPayload // keep the grown buffer.
+
+	signature, err := sign(ctx, alg, key, headerPayload)
+	if err != nil {
+		return nil, fmt.Errorf("encodeToken: signature: %w", err)
+	}
+
+	// header.payload.signature, the only allocation which is returned to the caller (if any).
+	n := len(headerPayload) + 1 + base64.RawURLEncoding.EncodedLen(len(signature))
+	if cap(dst)-len(dst) < n {
+		grown := make([]byte, len(dst), len(dst)+n)
+		copy(grown, dst)
+		dst = grown
+	}
+
+	dst = append(dst, headerPayload...)
+	dst = append(dst, sep[0])
+	dst = appendBase64(dst, signature)
+
+	return dst, nil
+}
+
+// appendSigningInput appends the base64url encoded header and "payload"
+// separated by a dot, the input of the signature (RFC 7515, Section 5.1).
+func appendSigningInput(dst []byte, alg Alg, payload []byte, customHeader interface{}) ([]byte, error) {
 	var header []byte
 	if customHeader != nil {
 		h, err := createCustomHeader(customHeader)
@@ -35,19 +80,56 @@ func encodeToken(alg Alg, key PrivateKey, payload []byte, customHeader interface
 		header = createHeader(alg.Name())
 	}
 
-	payload = Base64Encode(payload)
+	dst = append(dst, header...)
+	dst = append(dst, sep[0])
+	return appendBase64(dst, payload), nil
+}
 
-	headerPayload := joinParts(header, payload)
+// maxEncodeBufferSize is the maximum capacity of a buffer
+// which is returned to the encodeBufferPool, larger ones are left to the GC.
+const maxEncodeBufferSize = 64 << 10
+
+// encodeBufferPool holds the buffers of the base64-encoded header and payload
+// used by the encodeToken to calculate the signature.
+var encodeBufferPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 512)
+		return &b
+	},
+}
 
-	signature, err := createSignature(alg, key, headerPayload)
-	if err != nil {
-		return nil, fmt.Errorf("encodeToken: signature: %w", err)
+func acquireEncodeBuffer() *[]byte {
+	return encodeBufferPool.Get().(*[]byte)
+}
+
+func releaseEncodeBuffer(b *[]byte) {
+	if cap(*b) > maxEncodeBufferSize {
+		return
 	}
 
-	// header.payload.signature
-	token := joinParts(headerPayload, signature)
+	// Clear the previous token's data before reuse.
+	for i := range *b {
+		(*b)[i] = 0
+	}
+	*b = (*b)[:0]
 
-	return token, nil
+	encodeBufferPool.Put(b)
+}
+
+// appendBase64 appends the jwt base64 url encoded "src" to "dst".
+func appendBase64(dst, src []byte) []byte {
+	n := len(dst)
+	size := base64.RawURLEncoding.EncodedLen(len(src))
+
+	if cap(dst)-n < size {
+		grown := make([]byte, n, 2*cap(dst)+size)
+		copy(grown, dst)
+		dst = grown
+	}
+
+	dst = dst[:n+size]
+	base64.RawURLEncoding.Encode(dst[n:], src)
+	return dst
 }
 
 // We could omit the "alg" because the token contains it
@@ -58,19 +140,29 @@ func encodeToken(alg Alg, key PrivateKey, payload []byte, customHeader interface
 //
 // Decodes and verifies the given compact "token".
 // It returns the header, payoad and signature parts (decoded).
+// A structurally invalid token results to an error which wraps `ErrMalformed`
+// and a signature mismatch to `ErrInvalidSignature`.
 func decodeToken(alg Alg, key PublicKey, token []byte, compareHeaderFunc HeaderValidator) ([]byte, []byte, []byte, error) {
-	parts := bytes.Split(token, sep)
-	if len(parts) != 3 {
-		return nil, nil, nil, ErrTokenForm
+	return decodeTokenBase64(alg, key, token, compareHeaderFunc, Base64Decode)
+}
+
+// decodeTokenBase64 same as decodeToken but it decodes the token parts through the "base64Decode" function.
+// The signature is always verified against the parts as they are given.
+func decodeTokenBase64(alg Alg, key PublicKey, token []byte, compareHeaderFunc HeaderValidator, base64Decode func([]byte) ([]byte, error)) ([]byte, []byte, []byte, error) {
+	token = trimToken(token)
+	if bytes.IndexFunc(token, isASCIISpace) != -1 {
+		// The base64 decoder skips new lines, reject them explicitly.
+		return nil, nil, nil, fmt.Errorf("%w: whitespace inside the token", ErrMalformed)
 	}
 
-	header := parts[0]
-	payload := parts[1]
-	signature := parts[2]
+	header, payload, signature, ok := SplitToken(token)
+	if !ok {
+		return nil, nil, nil, ErrMalformed
+	}
 
-	headerDecoded, err := Base64Decode(header)
+	headerDecoded, err := base64Decode(header)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, fmt.Errorf("%w: header: %v", ErrMalformed, err)
 	}
 
 	// validate header equality.
@@ -91,6 +183,12 @@ func decodeToken(alg Alg, key PublicKey, token []byte, compareHeaderFunc HeaderV
 	}
 
 	if alg == nil {
+		// The algorithm is never selected by the token itself,
+		// the header validator should return a known one.
+		if dynamicAlg == nil {
+			return nil, nil, nil, ErrTokenAlg
+		}
+
 		alg = dynamicAlg
 	}
 
@@ -99,9 +197,9 @@ func decodeToken(alg Alg, key PublicKey, token []byte, compareHeaderFunc HeaderV
 		key = pubKey
 	}
 
-	signatureDecoded, err := Base64Decode(signature)
+	signatureDecoded, err := base64Decode(signature)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, fmt.Errorf("%w: signature: %v", ErrMalformed, err)
 	}
 	// validate signature.
 	headerPayload := joinParts(header, payload)
@@ -109,9 +207,9 @@ func decodeToken(alg Alg, key PublicKey, token []byte, compareHeaderFunc HeaderV
 		return nil, nil, nil, err
 	}
 
-	payload, err = Base64Decode(payload)
+	payload, err = base64Decode(payload)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, fmt.Errorf("%w: payload: %v", ErrMalformed, err)
 	}
 
 	if decrypt != nil {
@@ -124,11 +222,7 @@ func decodeToken(alg Alg, key PublicKey, token []byte, compareHeaderFunc HeaderV
 	return headerDecoded, payload, signatureDecoded, nil
 }
 
-var (
-	sep    = []byte(".")
-	pad    = []byte("=")
-	padStr = string(pad)
-)
+var sep = []byte(".")
 
 func joinParts(parts ...[]byte) []byte {
 	return bytes.Join(parts, sep)
@@ -211,7 +305,8 @@ type HeaderValidator func(alg string, headerDecoded []byte) (Alg, PublicKey, Inj
 
 // Note that this check is fully hard coded for known
 // algorithms and it is fully hard coded in terms of
-// its serialized format.
+// its serialized format. Headers with extra fields (e.g. "kid")
+// fallback to a JSON decoding of the header.
 func compareHeader(alg string, headerDecoded []byte) (Alg, PublicKey, InjectFunc, error) {
 	if n := len(headerDecoded); n < 25 /* 28 but allow custom short algs*/ {
 		if n == 15 { // header without "typ": "JWT".
@@ -221,7 +316,7 @@ func compareHeader(alg string, headerDecoded []byte) (Alg, PublicKey, InjectFunc
 			}
 		}
 
-		return nil, nil, nil, ErrTokenAlg
+		return compareHeaderJSON(alg, headerDecoded)
 	}
 
 	// Fast check if the order is reversed.
@@ -231,7 +326,7 @@ func compareHeader(alg string, headerDecoded []byte) (Alg, PublicKey, InjectFunc
 	if headerDecoded[2] == 't' {
 		expectedHeader := createHeaderReversed(alg)
 		if !bytes.Equal(expectedHeader, headerDecoded) {
-			return nil, nil, nil, ErrTokenAlg
+			return compareHeaderJSON(alg, headerDecoded)
 		}
 
 		return nil, nil, nil, nil
@@ -239,36 +334,54 @@ func compareHeader(alg string, headerDecoded []byte) (Alg, PublicKey, InjectFunc
 
 	expectedHeader := createHeaderRaw(alg)
 	if !bytes.Equal(expectedHeader, headerDecoded) {
-		return nil, nil, nil, ErrTokenAlg
+		return compareHeaderJSON(alg, headerDecoded)
 	}
 
 	return nil, nil, nil, nil
 }
 
-func createSignature(alg Alg, key PrivateKey, headerAndPayload []byte) ([]byte, error) {
-	signature, err := alg.Sign(key, headerAndPayload)
-	if err != nil {
-		return nil, err
+// compareHeaderJSON is the slow path of the compareHeader,
+// it decodes the header and checks its exact "alg" field.
+func compareHeaderJSON(alg string, headerDecoded []byte) (Alg, PublicKey, InjectFunc, error) {
+	if alg == "" {
+		return nil, nil, nil, ErrTokenAlg
+	}
+
+	var header map[string]interface{}
+	if err := Unmarshal(headerDecoded, &header); err != nil {
+		return nil, nil, nil, ErrTokenAlg
 	}
-	return Base64Encode(signature), nil
+
+	if headerAlg, _ := header["alg"].(string); headerAlg != alg {
+		return nil, nil, nil, ErrTokenAlg
+	}
+
+	return nil, nil, nil, nil
 }
 
-// Base64Encode encodes "src" to jwt base64 url format.
-// We could use the base64.RawURLEncoding but the below is a bit faster.
+// Base64Encode encodes "src" to jwt base64 url format,
+// the URL-safe alphabet without padding (RFC 7515, Section 2).
 func Base64Encode(src []byte) []byte {
-	buf := make([]byte, base64.URLEncoding.EncodedLen(len(src)))
-	base64.URLEncoding.Encode(buf, src)
+	buf := make([]byte, base64.RawURLEncoding.EncodedLen(len(src)))
+	base64.RawURLEncoding.Encode(buf, src)
 
-	return bytes.TrimRight(buf, padStr) // JWT: no trailing '='.
+	return buf
 }
 
-// Base64Decode decodes "src" to jwt base64 url format.
-// We could use the base64.RawURLEncoding but the below is a bit faster.
+// Base64Decode decodes "src" of jwt base64 url format,
+// the URL-safe alphabet without padding (RFC 7515, Section 2).
+// A padded or standard alphabet input results to an error.
 func Base64Decode(src []byte) ([]byte, error) {
-	if n := len(src) % 4; n > 0 {
-		// JWT: Because of no trailing '=' let's suffix it
-		// with the correct number of those '=' before decoding.
-		src = append(src, bytes.Repeat(pad, 4-n)...)
+	buf := make([]byte, base64.RawURLEncoding.DecodedLen(len(src)))
+	n, err := base64.RawURLEncoding.Decode(buf, src)
+	return buf[:n], err
+}
+
+// base64DecodePadded same as Base64Decode but it accepts a correctly padded input too,
+// see `WithPaddedBase64`.
+func base64DecodePadded(src []byte) ([]byte, error) {
+	if !bytes.HasSuffix(src, []byte("=")) {
+		return Base64Decode(src)
 	}
 
 	buf := make([]byte, base64.URLEncoding.DecodedLen(len(src)))
@@ -276,36 +389,92 @@ func Base64Decode(src []byte) ([]byte, error) {
 	return buf[:n], err
 }
 
+// Encode marshals the "claims" and signs them with the "alg" and "key",
+// it returns the token of compact form. A []byte "claims" is signed as it is.
+//
+// It is the raw form of `Sign`: the header is the default one of the algorithm
+// and the claims are signed as they are given, no standard claims are set
+// (see `Sign` and its `MaxAge` option instead).
+//
+// Example Code:
+//
+//	token, err := jwt.Encode(jwt.HS256, []byte("secret"), map[string]interface{}{"sub": "kataras"})
+func Encode(alg Alg, key PrivateKey, claims interface{}) ([]byte, error) {
+	if alg == nil {
+		return nil, ErrTokenAlg
+	}
+
+	payload, err := Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return encodeToken(alg, key, payload, nil)
+}
+
+// DecodeVerify decodes the token of compact form and verifies its
+// header and signature against the "alg" and "key", it returns the payload part (decoded).
+//
+// It is the raw form of `Verify`: the standard claims (e.g. "exp" and "nbf")
+// are NOT validated and the payload is not required to be JSON,
+// the caller is responsible to validate the returned claims.
+// A structurally invalid token results to an error which wraps `ErrMalformed`,
+// an unexpected "alg" header to `ErrTokenAlg` and a signature mismatch to `ErrInvalidSignature`.
+//
+// Example Code:
+//
+//	payload, err := jwt.DecodeVerify(jwt.HS256, []byte("secret"), token)
+//	[handle error...]
+//	var claims myClaims
+//	err = jwt.Unmarshal(payload, &claims)
+//	[custom validation...]
+func DecodeVerify(alg Alg, key PublicKey, token []byte) ([]byte, error) {
+	if alg == nil {
+		return nil, ErrTokenAlg
+	}
+
+	_, payload, _, err := decodeToken(alg, key, token, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
 // Decode decodes the token of compact form WITHOUT verification and validation.
 //
-// This function is only useful to read a token's claims
-// when the source is trusted and no algorithm verification or direct signature and
-// content validation is required.
+// WARNING: the returned token is NOT trusted. The signature is not checked,
+// the algorithm is not checked and the standard claims (e.g. "exp" and "nbf")
+// are not validated. Anyone can craft a token which Decode will happily accept,
+// so NEVER use its result for authentication or authorization decisions.
+//
+// This function is only useful to inspect a token's header and claims,
+// e.g. for debugging and logging purposes when the signing key
+// is not available anymore (e.g. rotated out).
+//
+// It returns `ErrTokenForm` when the token has not three dot-separated parts
+// and an error that wraps `ErrTokenForm` when a part is not valid base64.
 //
 // Use `Verify/VerifyEncrypted` functions instead.
 func Decode(token []byte) (*UnverifiedToken, error) {
-	parts := bytes.Split(token, sep)
-	if len(parts) != 3 {
+	header, payload, signature, ok := SplitToken(token)
+	if !ok {
 		return nil, ErrTokenForm
 	}
 
-	header := parts[0]
-	payload := parts[1]
-	signature := parts[2]
-
 	headerDecoded, err := Base64Decode(header)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: header: %v", ErrTokenForm, err)
 	}
 
 	signatureDecoded, err := Base64Decode(signature)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: signature: %v", ErrTokenForm, err)
 	}
 
 	payload, err = Base64Decode(payload)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: payload: %v", ErrTokenForm, err)
 	}
 
 	tok := &UnverifiedToken{
@@ -316,8 +485,76 @@ func Decode(token []byte) (*UnverifiedToken, error) {
 	return tok, nil
 }
 
+// PeekHeader decodes and returns the header (first part) of the compact form "token"
+// WITHOUT verifying its signature or decoding its payload.
+//
+// It is useful to read the "kid" header field before verification,
+// e.g. to select the correct public key of a key set.
+// The returned header is NOT trusted, see `Decode` for more.
+//
+// It returns `ErrTokenForm` when the token has not three dot-separated parts
+// or its header is not a valid base64 JSON object.
+func PeekHeader(token []byte) (map[string]interface{}, error) {
+	header, _, _, ok := SplitToken(token)
+	if !ok {
+		return nil, ErrTokenForm
+	}
+
+	headerDecoded, err := Base64Decode(header)
+	if err != nil {
+		return nil, fmt.Errorf("%w: header: %v", ErrTokenForm, err)
+	}
+
+	var h map[string]interface{}
+	if err = Unmarshal(headerDecoded, &h); err != nil {
+		return nil, fmt.Errorf("%w: header: %v", ErrTokenForm, err)
+	}
+
+	return h, nil
+}
+
+// trimToken removes the leading and trailing ASCII whitespace of the "token".
+func trimToken(token []byte) []byte {
+	return bytes.TrimFunc(token, isASCIISpace)
+}
+
+func isASCIISpace(r rune) bool {
+	switch r {
+	case ' ', '\t', '\n', '\v', '\f', '\r':
+		return true
+	default:
+		return false
+	}
+}
+
+// SplitToken returns the header, payload and signature parts of the compact form "token",
+// as they are (base64url-encoded) and WITHOUT decoding or verifying them,
+// e.g. to log a fingerprint of the signature or to cache by the payload.
+// The parts are sub-slices of the "token", it does not allocate.
+// It reports false if the token has not three dot-separated parts.
+func SplitToken(token []byte) (header, payload, signature []byte, ok bool) {
+	i := bytes.IndexByte(token, '.')
+	if i < 0 {
+		return nil, nil, nil, false
+	}
+
+	j := bytes.IndexByte(token[i+1:], '.')
+	if j < 0 {
+		return nil, nil, nil, false
+	}
+	j += i + 1
+
+	if bytes.IndexByte(token[j+1:], '.') >= 0 {
+		return nil, nil, nil, false
+	}
+
+	return token[:i:i], token[i+1 : j : j], token[j+1:], true
+}
+
 // UnverifiedToken contains the compact form token parts.
 // Look its `Claims` method to decode to a custom structure.
+//
+// Its contents are NOT verified, see `Decode` for more.
 type UnverifiedToken struct {
 	Header    []byte
 	Payload   []byte
@@ -326,5 +563,5 @@ type UnverifiedToken struct {
 
 // Claims decodes the `Payload` field to the "dest".
 func (t *UnverifiedToken) Claims(dest interface{}) error {
-	return Unmarshal(t.Payload, dest)
+	return unmarshalClaims(t.Payload, dest)
 }
@@ -1,6 +1,11 @@
 package jwt
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
 
 // TokenPair holds the access token and refresh token response.
 type TokenPair struct {
@@ -18,6 +23,91 @@ func NewTokenPair(accessToken, refreshToken []byte) TokenPair {
 	}
 }
 
+// The values of the "typ" claim of the tokens generated by `SignTokenPair`.
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
+// SignTokenPair generates an access token and a longer-lived refresh token
+// and returns a structure which holds both of them, ready to be sent to the client as JSON:
+// {"access_token": "...", "refresh_token": "..."}.
+//
+// The "accessClaims" and "refreshClaims" should be JSON objects (struct or map values),
+// the expiration of each token is set by the "accessMaxAge" and "refreshMaxAge" (see `MaxAge`).
+// Each token carries a "typ" claim of `TokenTypeAccess` and `TokenTypeRefresh` value respectively,
+// so a refresh token can not be used as an access token, see `WithExpectedType`.
+// A "typ" field of the given claims is overridden.
+//
+// Example Code:
+//
+//	tokenPair, err := jwt.SignTokenPair(jwt.HS256, sharedKey,
+//	  UserClaims{Username: "kataras"}, jwt.Claims{Subject: "kataras"},
+//	  15*time.Minute, 7*24*time.Hour)
+//	[handle error...]
+//	json.NewEncoder(w).Encode(tokenPair)
+func SignTokenPair(alg Alg, key PrivateKey, accessClaims, refreshClaims interface{}, accessMaxAge, refreshMaxAge time.Duration) (*TokenPair, error) {
+	accessToken, err := signTokenOfType(alg, key, accessClaims, TokenTypeAccess, accessMaxAge)
+	if err != nil {
+		return nil, fmt.Errorf("jwt: token pair: access token: %w", err)
+	}
+
+	refreshToken, err := signTokenOfType(alg, key, refreshClaims, TokenTypeRefresh, refreshMaxAge)
+	if err != nil {
+		return nil, fmt.Errorf("jwt: token pair: refresh token: %w", err)
+	}
+
+	tokenPair := NewTokenPair(accessToken, refreshToken)
+	return &tokenPair, nil
+}
+
+func signTokenOfType(alg Alg, key PrivateKey, claims interface{}, typ string, maxAge time.Duration) ([]byte, error) {
+	if claims == nil {
+		claims = Map{}
+	}
+
+	payload := MergeUnique(claims, Map{"typ": typ})
+	if payload == nil {
+		return nil, errPayloadNotJSON
+	}
+
+	return Sign(alg, key, payload, MaxAge(maxAge))
+}
+
+// ErrInvalidType indicates that the token's "typ" claim
+// does not match the one expected by the `WithExpectedType` option.
+var ErrInvalidType = errors.New("jwt: invalid token type")
+
+// WithExpectedType is a VerifyOption which makes the verification
+// to fail with an `ErrInvalidType` error if the "typ" payload claim
+// (not the "typ" header field) does not match the given "typ".
+// It prevents refresh tokens from being used as access tokens
+// and vice versa, see `SignTokenPair`.
+//
+// Example Code:
+//
+//	verifiedToken, err := jwt.Verify(jwt.HS256, sharedKey, token, jwt.WithExpectedType(jwt.TokenTypeAccess))
+func WithExpectedType(typ string) VerifyOption {
+	return func(c *verifyConfig) {
+		c.expectedType = typ
+	}
+}
+
+func validateType(payload []byte, expected string) error {
+	var claims struct {
+		Type string `json:"typ"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return errPayloadNotJSON
+	}
+
+	if claims.Type != expected {
+		return ErrInvalidType
+	}
+
+	return nil
+}
+
 // BytesQuote returns a double-quoted []byte slice representing "b".
 func BytesQuote(b []byte) []byte {
 	dst := make([]byte, len(b)+2)
@@ -2,6 +2,7 @@ package jwt
 
 import (
 	"encoding/json"
+	"errors"
 	"reflect"
 	"strconv"
 	"testing"
@@ -42,3 +43,122 @@ func TestTokenPair(t *testing.T) {
 		t.Fatalf("expected token pairs to be matched, expected:\n%#+v\n\nbut got:\n%#+v", tokenPair, tokPair)
 	}
 }
+
+func TestSignTokenPair(t *testing.T) {
+	accessClaims := Map{"username": "kataras", "typ": "refresh"} // "typ" is overridden.
+	tokenPair, err := SignTokenPair(testAlg, testSecret, accessClaims, Claims{Subject: "kataras"}, 10*time.Minute, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.Marshal(tokenPair)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var response map[string]string
+	if err = json.Unmarshal(b, &response); err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		field        string
+		expectedType string
+		expectedAge  time.Duration
+	}{
+		{"access_token", TokenTypeAccess, 10 * time.Minute},
+		{"refresh_token", TokenTypeRefresh, time.Hour},
+	}
+
+	for _, tt := range tests {
+		verifiedToken, err := Verify(testAlg, testSecret, []byte(response[tt.field]))
+		if err != nil {
+			t.Fatalf("[%s] %v", tt.field, err)
+		}
+
+		var claims struct {
+			Type     string `json:"typ"`
+			Username string `json:"username"`
+			Subject  string `json:"sub"`
+		}
+		if err = verifiedToken.Claims(&claims); err != nil {
+			t.Fatal(err)
+		}
+
+		if claims.Type != tt.expectedType {
+			t.Fatalf("[%s] expected typ: %q but got: %q", tt.field, tt.expectedType, claims.Type)
+		}
+
+		if claims.Username == "" && claims.Subject == "" {
+			t.Fatalf("[%s] expected the given claims to be kept", tt.field)
+		}
+
+		if got := time.Duration(verifiedToken.StandardClaims.Expiry-verifiedToken.StandardClaims.IssuedAt) * time.Second; got != tt.expectedAge {
+			t.Fatalf("[%s] expected max age: %s but got: %s", tt.field, tt.expectedAge, got)
+		}
+	}
+
+	if _, err = SignTokenPair(testAlg, testSecret, "not an object", nil, time.Minute, time.Hour); !errors.Is(err, errPayloadNotJSON) {
+		t.Fatalf("expected error: errPayloadNotJSON but got: %v", err)
+	}
+}
+
+func TestWithExpectedType(t *testing.T) {
+	tokenPair, err := SignTokenPair(testAlg, testSecret, Map{"username": "kataras"}, nil, 10*time.Minute, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var accessToken, refreshToken string
+	json.Unmarshal(tokenPair.AccessToken, &accessToken)
+	json.Unmarshal(tokenPair.RefreshToken, &refreshToken)
+
+	untypedToken, err := Sign(testAlg, testSecret, Map{"username": "kataras"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		token       []byte
+		typ         string
+		expectedErr error
+	}{
+		{[]byte(accessToken), TokenTypeAccess, nil},
+		{[]byte(refreshToken), TokenTypeRefresh, nil},
+		{[]byte(refreshToken), TokenTypeAccess, ErrInvalidType},
+		{[]byte(accessToken), TokenTypeRefresh, ErrInvalidType},
+		{untypedToken, TokenTypeAccess, ErrInvalidType},
+	}
+
+	for i, tt := range tests {
+		if _, err = Verify(testAlg, testSecret, tt.token, WithExpectedType(tt.typ)); err != tt.expectedErr {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, tt.expectedErr, err)
+		}
+	}
+
+	// The header "typ" is not checked.
+	if _, err = Verify(testAlg, testSecret, untypedToken, WithExpectedType("JWT")); err != ErrInvalidType {
+		t.Fatalf("expected error: ErrInvalidType but got: %v", err)
+	}
+}
+
+func TestWithExpectedTypeAfterValidators(t *testing.T) {
+	// The "iat" is slightly in the future and the Future validator skips that error,
+	// the refresh token must still be rejected at the access token path.
+	refreshToken, err := Sign(testAlg, testSecret, Map{
+		"typ": TokenTypeRefresh,
+		"iat": Clock().Add(10 * time.Second).Unix(),
+		"exp": Clock().Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, refreshToken, Future(time.Minute), WithExpectedType(TokenTypeAccess)); err != ErrInvalidType {
+		t.Fatalf("expected error: ErrInvalidType but got: %v", err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, refreshToken, Future(time.Minute), WithExpectedType(TokenTypeRefresh)); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,65 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidTypHeader indicates that the token's "typ" header field
+// does not match the one expected by the `WithExpectedTypHeader` option.
+var ErrInvalidTypHeader = errors.New("jwt: invalid typ header")
+
+// WithTypHeader is a SignOption which sets the "typ" header field
+// of the generated token, instead of the default "JWT" one,
+// e.g. "at+jwt" for access tokens (RFC 9068).
+// It is a shortcut of WithHeader("typ", typ).
+//
+// Example Code:
+//
+//	token, err := jwt.Sign(jwt.RS256, privateKey, claims, jwt.WithTypHeader("at+jwt"))
+func WithTypHeader(typ string) SignConfigOption {
+	return WithHeader("typ", typ)
+}
+
+// WithExpectedTypHeader is a VerifyOption which makes the verification
+// to fail with an `ErrInvalidTypHeader` error if the "typ" header field
+// (not the "typ" payload claim, see `WithExpectedType`) does not match the given "typ".
+// By default any "typ" header value is accepted.
+//
+// The comparison is case-insensitive and the "application/" prefix
+// is omitted (RFC 7515, Section 4.1.9), e.g. "application/at+JWT" matches "at+jwt".
+//
+// Example Code:
+//
+//	verifiedToken, err := jwt.Verify(jwt.RS256, publicKey, token, jwt.WithExpectedTypHeader("at+jwt"))
+func WithExpectedTypHeader(typ string) VerifyOption {
+	return func(c *verifyConfig) {
+		c.expectedTypHeader = typ
+	}
+}
+
+func validateTypHeader(headerDecoded []byte, expected string) error {
+	var header struct {
+		Typ string `json:"typ"`
+	}
+	if err := json.Unmarshal(headerDecoded, &header); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTypHeader, err)
+	}
+
+	if !strings.EqualFold(trimMediaTypePrefix(header.Typ), trimMediaTypePrefix(expected)) {
+		return fmt.Errorf("%w: %q", ErrInvalidTypHeader, header.Typ)
+	}
+
+	return nil
+}
+
+func trimMediaTypePrefix(typ string) string {
+	const prefix = "application/"
+	if len(typ) > len(prefix) && strings.EqualFold(typ[:len(prefix)], prefix) {
+		return typ[len(prefix):]
+	}
+
+	return typ
+}
@@ -0,0 +1,60 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWithExpectedTypHeader(t *testing.T) {
+	accessToken, err := Sign(testAlg, testSecret, Map{"username": "kataras"}, WithTypHeader("at+jwt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, accessToken, WithExpectedTypHeader("at+jwt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := `{"alg":"HS256","typ":"at+jwt"}`, string(verifiedToken.Header); expected != got {
+		t.Fatalf("expected header: %s but got: %s", expected, got)
+	}
+
+	// Case-insensitive, without the "application/" prefix.
+	for _, typ := range []string{"AT+JWT", "application/at+jwt", "Application/At+Jwt"} {
+		if _, err = Verify(testAlg, testSecret, accessToken, WithExpectedTypHeader(typ)); err != nil {
+			t.Fatalf("%s: %v", typ, err)
+		}
+	}
+
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, WithExpectedTypHeader("JWT")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, WithExpectedTypHeader("at+jwt")); !errors.Is(err, ErrInvalidTypHeader) {
+		t.Fatalf("expected error: ErrInvalidTypHeader but got: %v", err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, accessToken, WithExpectedTypHeader("JWT")); !errors.Is(err, ErrInvalidTypHeader) {
+		t.Fatalf("expected error: ErrInvalidTypHeader but got: %v", err)
+	}
+
+	// Missing "typ" header field.
+	token, err = SignWithHeader(testAlg, testSecret, Map{"username": "kataras"}, Map{"alg": testAlg.Name()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token); err != nil {
+		t.Fatalf("expected any typ header to be accepted by default but got: %v", err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, WithExpectedTypHeader("JWT")); !errors.Is(err, ErrInvalidTypHeader) {
+		t.Fatalf("expected error: ErrInvalidTypHeader but got: %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package jwt
+
+import (
+	"strings"
+	"time"
+)
+
+// ValidationErrors holds all the validation failures of a token,
+// see `WithAllValidationErrors`.
+// Each one of the errors can be checked through errors.Is and errors.As, e.g.
+// errors.Is(err, ErrExpired).
+type ValidationErrors []error
+
+// Error implements the error interface.
+// It returns the error messages separated by "; ".
+func (errs ValidationErrors) Error() string {
+	var b strings.Builder
+	for i, err := range errs {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		b.WriteString(err.Error())
+	}
+
+	return b.String()
+}
+
+// Unwrap returns the errors, it is used by errors.Is and errors.As.
+func (errs ValidationErrors) Unwrap() []error {
+	return errs
+}
+
+// add appends the "err" if it's not nil.
+// The errors of a ValidationErrors "err" are appended one by one.
+func (errs ValidationErrors) add(err error) ValidationErrors {
+	if err == nil {
+		return errs
+	}
+
+	if other, ok := err.(ValidationErrors); ok {
+		return append(errs, other...)
+	}
+
+	return append(errs, err)
+}
+
+// WithAllValidationErrors is a VerifyOption which collects all the claims validation failures,
+// instead of returning the first one only, e.g. a token which is expired and
+// of an unexpected issuer results to both ErrExpired and ErrInvalidIssuer errors.
+// It is useful to report everything to the caller of an API.
+//
+// The returned error is a type of ValidationErrors.
+// The signature is still verified first, an invalid token results to a single error.
+//
+// In this mode each TokenValidator runs without the previous error,
+// so validators which skip or modify the builtin errors (e.g. `Plain` and `Future`)
+// have no effect.
+//
+// Usage:
+//
+//	verifiedToken, err := jwt.Verify(jwt.HS256, sharedKey, token,
+//	  jwt.WithAllValidationErrors(), jwt.WithExpectedIssuer("my-app"))
+//	var errs jwt.ValidationErrors
+//	if errors.As(err, &errs) {
+//	  for _, err := range errs { [...] }
+//	}
+func WithAllValidationErrors() VerifyOption {
+	return func(c *verifyConfig) {
+		c.allErrors = true
+	}
+}
+
+// validateAllClaims returns all the "nbf", "iat" and "exp" validation failures.
+func validateAllClaims(t time.Time, claims Claims, leeway time.Duration) error {
+	errs := ValidationErrors(nil).
+		add(validateNotBefore(t, claims, leeway)).
+		add(validateIssuedAt(t, claims, leeway)).
+		add(validateExpiry(t, claims, leeway))
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
+
+// validateAll runs all the configured validations and the token validators
+// and collects their failures. The "err" is the time claims validation error, if any.
+func (c *verifyConfig) validateAll(token, payload []byte, claims Claims, err error, validators []TokenValidator) error {
+	errs := ValidationErrors(nil).add(err)
+
+	if len(c.requiredClaims) > 0 {
+		errs = errs.add(validateRequiredClaims(payload, c.requiredClaims))
+	}
+
+	if c.expectedType != "" {
+		errs = errs.add(validateType(payload, c.expectedType))
+	}
+
+	if c.expectedNonce != "" {
+		errs = errs.add(validateNonce(payload, c.expectedNonce))
+	}
+
+	if c.expectedAuthorizedParty != "" {
+		errs = errs.add(validateAuthorizedParty(payload, claims.Audience, c.expectedAuthorizedParty))
+	}
+
+	if c.blocklist != nil {
+		errs = errs.add(validateBlocklist(c.blocklist, claims.ID))
+	}
+
+	for _, validator := range validators {
+		errs = errs.add(c.validateToken(validator, token, claims, nil))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
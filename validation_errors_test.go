@@ -0,0 +1,90 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithAllValidationErrors(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Claims{
+		Expiry: Clock().Add(-time.Minute).Unix(),
+		Issuer: "other-app",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Default: the first error only.
+	_, err = Verify(testAlg, testSecret, token, WithExpectedIssuer("my-app"))
+	if !errors.Is(err, ErrExpired) {
+		t.Fatalf("expected error: ErrExpired but got: %v", err)
+	}
+
+	if errors.Is(err, ErrInvalidIssuer) {
+		t.Fatalf("expected the first error only but got: %v", err)
+	}
+
+	_, err = Verify(testAlg, testSecret, token, WithAllValidationErrors(), WithExpectedIssuer("my-app"))
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ValidationErrors but got: %T: %v", err, err)
+	}
+
+	if expected, got := 2, len(errs); expected != got {
+		t.Fatalf("expected %d errors but got: %d: %v", expected, got, errs)
+	}
+
+	if !errors.Is(err, ErrExpired) || !errors.Is(errs[0], ErrExpired) {
+		t.Fatalf("expected ErrExpired to be collected but got: %v", err)
+	}
+
+	if !errors.Is(err, ErrInvalidIssuer) || !errors.Is(errs[1], ErrInvalidIssuer) {
+		t.Fatalf("expected ErrInvalidIssuer to be collected but got: %v", err)
+	}
+
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || vErr.Claim != "exp" {
+		t.Fatalf("expected the *ValidationError of exp to be found but got: %v", err)
+	}
+
+	if expected, got := errs[0].Error()+"; "+errs[1].Error(), err.Error(); expected != got {
+		t.Fatalf("expected error message: %q but got: %q", expected, got)
+	}
+
+	// All time claims and a verify option.
+	token, err = Sign(testAlg, testSecret, Map{"username": "kataras"}, Claims{
+		NotBefore: Clock().Add(time.Minute).Unix(),
+		IssuedAt:  Clock().Add(time.Minute).Unix(),
+		Expiry:    Clock().Add(-time.Minute).Unix(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Verify(testAlg, testSecret, token, WithAllValidationErrors(), WithRequiredClaims("sub"))
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ValidationErrors but got: %T: %v", err, err)
+	}
+
+	for i, expectedErr := range []error{ErrNotValidYet, ErrIssuedInTheFuture, ErrExpired, ErrMissingRequiredClaim} {
+		if i >= len(errs) || !errors.Is(errs[i], expectedErr) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, expectedErr, errs)
+		}
+	}
+
+	// Valid token.
+	token, err = Sign(testAlg, testSecret, Claims{Issuer: "my-app"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, WithAllValidationErrors(), WithExpectedIssuer("my-app")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Signature errors are not collected.
+	if _, err = Verify(testAlg, []byte("other"), token, WithAllValidationErrors()); err != ErrInvalidSignature {
+		t.Fatalf("expected error: ErrInvalidSignature but got: %v", err)
+	}
+}
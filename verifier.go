@@ -0,0 +1,116 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Verifier verifies tokens of a fixed set of algorithms,
+// each one with its own key. The token's "alg" header field selects the algorithm,
+// a token of any other algorithm fails with an `ErrDisallowedAlg` error.
+//
+// As each algorithm is bound to its own key, an RSA public key is never fed
+// into an HMAC verification, so the classic key-confusion attack
+// (an "HS256" token signed with the server's RSA public key) is blocked.
+// It is safe for concurrent use. See `NewVerifier` to create a new one.
+type Verifier struct {
+	keys       map[string]verifierKey
+	validators []TokenValidator
+}
+
+type verifierKey struct {
+	alg Alg
+	key PublicKey
+}
+
+// NewVerifier returns a new Verifier of the given algorithm-key pairs.
+// The "validators" (e.g. `Expected`, `WithLeeway`) are applied to every token.
+// It returns an `ErrInvalidKey` error if a key is missing or an HMAC algorithm
+// is given a non-secret key, e.g. a PEM-encoded public key.
+//
+// Example Code:
+//
+//	verifier, err := jwt.NewVerifier(map[jwt.Alg]jwt.PublicKey{
+//	  jwt.RS256: rsaPublicKey,
+//	  jwt.ES256: ecdsaPublicKey,
+//	})
+//	verifiedToken, err := verifier.Verify(token)
+func NewVerifier(keys map[Alg]PublicKey, validators ...TokenValidator) (*Verifier, error) {
+	v := &Verifier{
+		keys:       make(map[string]verifierKey, len(keys)),
+		validators: validators,
+	}
+
+	for alg, key := range keys {
+		if alg == nil {
+			return nil, ErrTokenAlg
+		}
+
+		if err := validateVerifierKey(alg, key); err != nil {
+			return nil, fmt.Errorf("%w: %s", err, alg.Name())
+		}
+
+		v.keys[alg.Name()] = verifierKey{alg: alg, key: key}
+	}
+
+	return v, nil
+}
+
+// pemPrefix is the start of a PEM-encoded key.
+var pemPrefix = []byte("-----BEGIN")
+
+func validateVerifierKey(alg Alg, key PublicKey) error {
+	if key == nil {
+		return ErrInvalidKey
+	}
+
+	if _, ok := alg.(*algHMAC); ok {
+		secret, ok := key.([]byte)
+		if !ok || len(secret) == 0 || bytes.Contains(secret, pemPrefix) {
+			return ErrInvalidKey
+		}
+	}
+
+	return nil
+}
+
+// Verify verifies the "token" with the key of its "alg" header field.
+// The "validators" are applied after the ones given at `NewVerifier`.
+func (v *Verifier) Verify(token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
+	return v.VerifyContext(context.Background(), token, validators...)
+}
+
+// VerifyContext same as `Verify` but it accepts a context.
+func (v *Verifier) VerifyContext(ctx context.Context, token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(validators) > 0 {
+		validators = append(v.validators[:len(v.validators):len(v.validators)], validators...)
+	} else {
+		validators = v.validators
+	}
+
+	return verifyToken(nil, nil, nil, token, v.ValidateHeader, validators...)
+}
+
+// ValidateHeader selects the algorithm and the key of the token's "alg" header field.
+// Verifier structure completes the `HeaderValidator` interface.
+func (v *Verifier) ValidateHeader(_ string, headerDecoded []byte) (Alg, PublicKey, InjectFunc, error) {
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(headerDecoded, &header); err != nil || header.Alg == "" {
+		return nil, nil, nil, ErrTokenAlg
+	}
+
+	k, ok := v.keys[header.Alg]
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("%w: %q", ErrDisallowedAlg, header.Alg)
+	}
+
+	return k.alg, k.key, nil, nil
+}
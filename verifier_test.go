@@ -0,0 +1,82 @@
+package jwt
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestVerifierKeyConfusion(t *testing.T) {
+	privateKey, publicKey := MustLoadRSA("./_testfiles/rsa_private_key.pem", "./_testfiles/rsa_public_key.pem")
+	publicKeyPEM, err := os.ReadFile("./_testfiles/rsa_public_key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier, err := NewVerifier(map[Alg]PublicKey{RS256: publicKey}, Expected{Issuer: "my-app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := Claims{Issuer: "my-app", Expiry: time.Now().Add(time.Minute).Unix()}
+	token, err := Sign(RS256, privateKey, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = verifier.Verify(token); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = verifier.Verify(token, Expected{Subject: "kataras"}); !errors.Is(err, ErrExpected) {
+		t.Fatalf("expected error: ErrExpected but got: %v", err)
+	}
+
+	// The attacker signs an HS256 token with the (public) RSA key as the HMAC secret.
+	forged, err := Sign(HS256, publicKeyPEM, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = verifier.Verify(forged); !errors.Is(err, ErrDisallowedAlg) {
+		t.Fatalf("expected error: ErrDisallowedAlg but got: %v", err)
+	}
+
+	// An HMAC algorithm can not be registered with a public key.
+	for _, key := range []PublicKey{publicKey, publicKeyPEM, nil} {
+		if _, err = NewVerifier(map[Alg]PublicKey{RS256: publicKey, HS256: key}); !errors.Is(err, ErrInvalidKey) {
+			t.Fatalf("expected error: ErrInvalidKey but got: %v", err)
+		}
+	}
+
+	// Each algorithm uses its own key.
+	verifier, err = NewVerifier(map[Alg]PublicKey{RS256: publicKey, HS256: testSecret})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = verifier.Verify(forged); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected error: ErrInvalidSignature but got: %v", err)
+	}
+
+	hmacToken, err := Sign(HS256, testSecret, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tok := range [][]byte{token, hmacToken} {
+		if _, err = verifier.Verify(tok); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	none, err := Sign(NONE, nil, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = verifier.Verify(none); !errors.Is(err, ErrDisallowedAlg) {
+		t.Fatalf("expected error: ErrDisallowedAlg but got: %v", err)
+	}
+}
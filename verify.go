@@ -1,8 +1,14 @@
 package jwt
 
 import (
+	"bytes"
+	"context"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"strconv"
+	"time"
 )
 
 // Verify decodes, verifies and validates the standard JWT claims
@@ -25,9 +31,76 @@ import (
 //	var claims map[string]interface{}
 //	verifiedToken.Claims(&claims)
 func Verify(alg Alg, key PublicKey, token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
+	return VerifyContext(context.Background(), alg, key, token, validators...)
+}
+
+// MustVerify same as `Verify` but it panics on errors,
+// e.g. for tests.
+func MustVerify(alg Alg, key PublicKey, token []byte, validators ...TokenValidator) *VerifiedToken {
+	verifiedToken, err := Verify(alg, key, token, validators...)
+	if err != nil {
+		panicHandler(err)
+	}
+
+	return verifiedToken
+}
+
+// VerifyContext same as `Verify` but it accepts a context,
+// it returns the context's error if it is done before the verification.
+// See `RemoteKeySet.VerifyContext` to cancel a remote key fetch.
+func VerifyContext(ctx context.Context, alg Alg, key PublicKey, token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	return verifyToken(alg, key, nil, token, nil, validators...)
 }
 
+// VerifyRaw same as `Verify` but it returns just the payload (decoded) part
+// of the verified token. Useful when the standard claims
+// and the header information are not required by the caller.
+func VerifyRaw(alg Alg, key PublicKey, token []byte, validators ...TokenValidator) ([]byte, error) {
+	verifiedToken, err := verifyToken(alg, key, nil, token, nil, validators...)
+	if err != nil {
+		return nil, err
+	}
+
+	return verifiedToken.Payload, nil
+}
+
+// VerifyAny same as `Verify` but it accepts a list of keys, e.g. current and previous ones,
+// useful on key rotation. The keys are tried in order and the first successful one is returned.
+//
+// Only signature (and invalid key) errors fallback to the next key,
+// any other error, e.g. ErrExpired, is returned immediately.
+// If all keys fail then it returns an error which wraps each key's error,
+// the keys are reported by their index and type, the key material is never included.
+//
+// Example Code:
+//
+//	verifiedToken, err := jwt.VerifyAny(jwt.HS256, []jwt.PublicKey{currentKey, previousKey}, token)
+func VerifyAny(alg Alg, keys []PublicKey, token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
+	if len(keys) == 0 {
+		return nil, ErrInvalidKey
+	}
+
+	errs := make([]error, 0, len(keys))
+	for i, key := range keys {
+		verifiedToken, err := verifyToken(alg, key, nil, token, nil, validators...)
+		if err == nil {
+			return verifiedToken, nil
+		}
+
+		if !errors.Is(err, ErrTokenSignature) && !errors.Is(err, ErrInvalidKey) {
+			return nil, err
+		}
+
+		errs = append(errs, fmt.Errorf("key[%d] (%T): %w", i, key, err))
+	}
+
+	return nil, fmt.Errorf("jwt: verify any: %w", errors.Join(errs...))
+}
+
 // VerifyEncrypted same as `Verify` but it decrypts the payload part with the given "decrypt" function.
 // The "decrypt" function is called AFTER base64-decode and BEFORE Unmarshal.
 // Look the `GCM` function for details.
@@ -45,16 +118,154 @@ func VerifyEncryptedWithHeaderValidator(alg Alg, key PublicKey, decrypt InjectFu
 	return verifyToken(alg, key, decrypt, token, headerValidator, validators...)
 }
 
+// DefaultMaxTokenSize is the default maximum size of a token to be verified,
+// see `WithMaxTokenSize`.
+const DefaultMaxTokenSize = 8 << 10 // 8KB.
+
+// ErrTokenTooLarge indicates that the token exceeds the maximum size
+// and it was rejected before any decoding, see `WithMaxTokenSize`.
+var ErrTokenTooLarge = errors.New("jwt: token is too large")
+
+// WithMaxTokenSize is a VerifyOption which sets the maximum size, in bytes,
+// of the compact form token. A larger token fails with `ErrTokenTooLarge`
+// before any base64 decoding or JSON parsing, it protects against
+// forced large allocations. A zero or negative "n" disables the limit.
+// Defaults to `DefaultMaxTokenSize`.
+func WithMaxTokenSize(n int) VerifyOption {
+	return func(c *verifyConfig) {
+		c.maxTokenSize = n
+	}
+}
+
+// WithPaddedBase64 is a VerifyOption which accepts token parts
+// encoded with the "=" padding of the base64url alphabet, e.g. tokens of legacy systems.
+// The signature is verified against the parts as they are given
+// and an incorrect padding or an invalid base64 input is still rejected.
+// By default, as RFC 7515 requires, a padded part results to an `ErrMalformed` error.
+func WithPaddedBase64() VerifyOption {
+	return func(c *verifyConfig) {
+		c.paddedBase64 = true
+	}
+}
+
+// ErrDisallowedAlg indicates that the token's "alg" header field
+// is not one of the allowed algorithms, see `VerifyWithHeaderAlg`.
+var ErrDisallowedAlg = errors.New("jwt: disallowed token algorithm")
+
+// VerifyWithHeaderAlg same as `Verify` but it selects the algorithm
+// based on the token's "alg" header field, instead of a hard-coded one.
+// The algorithm is looked up through the registered ones (see `RegisterAlg`).
+//
+// To prevent downgrade attacks the "allowed" algorithm names are required,
+// if the token's algorithm is not one of them then it returns an `ErrDisallowedAlg` error.
+// Note that the same "key" is used for all the allowed algorithms,
+// so do not allow symmetric and asymmetric algorithms at the same call.
+//
+// Example Code:
+//
+//	verifiedToken, err := jwt.VerifyWithHeaderAlg(publicKey, token, "RS256", "RS512")
+func VerifyWithHeaderAlg(key PublicKey, token []byte, allowed ...string) (*VerifiedToken, error) {
+	return verifyToken(nil, key, nil, token, allowedAlgHeaderValidator(allowed))
+}
+
+func allowedAlgHeaderValidator(allowed []string) HeaderValidator {
+	return func(_ string, headerDecoded []byte) (Alg, PublicKey, InjectFunc, error) {
+		var header map[string]interface{}
+		if err := json.Unmarshal(headerDecoded, &header); err != nil {
+			return nil, nil, nil, ErrTokenAlg
+		}
+
+		algName, _ := header["alg"].(string)
+		if algName == "" {
+			return nil, nil, nil, ErrTokenAlg
+		}
+
+		for _, name := range allowed {
+			if name == algName {
+				alg, ok := GetAlg(algName)
+				if !ok {
+					return nil, nil, nil, ErrTokenAlg
+				}
+
+				return alg, nil, nil, nil
+			}
+		}
+
+		return nil, nil, nil, fmt.Errorf("%w: %q", ErrDisallowedAlg, algName)
+	}
+}
+
 func verifyToken(alg Alg, key PublicKey, decrypt InjectFunc, token []byte, headerValidator HeaderValidator, validators ...TokenValidator) (*VerifiedToken, error) {
+	return verifyTokenWithConfig(newVerifyConfig(validators), alg, key, decrypt, token, headerValidator, validators)
+}
+
+// verifyTokenWithConfig same as verifyToken but it accepts the already parsed configuration of the "validators".
+func verifyTokenWithConfig(cfg *verifyConfig, alg Alg, key PublicKey, decrypt InjectFunc, token []byte, headerValidator HeaderValidator, validators []TokenValidator) (*VerifiedToken, error) {
+	// Tokens copied from headers or files may carry surrounding whitespace, e.g. a trailing new line.
+	token = trimToken(token)
+
+	if cfg.observer == nil && cfg.logger == nil {
+		return verifyUnobservedToken(cfg, alg, key, decrypt, token, headerValidator, validators)
+	}
+
+	start := time.Now()
+	verifiedToken, err := verifyUnobservedToken(cfg, alg, key, decrypt, token, headerValidator, validators)
+	d := time.Since(start)
+
+	algName := ""
+	if verifiedToken != nil {
+		algName = verifiedToken.Algorithm()
+	} else if alg != nil {
+		algName = alg.Name()
+	}
+
+	if cfg.observer != nil {
+		cfg.observer.OnVerify(algName, d, err)
+	}
+
+	if cfg.logger != nil {
+		cfg.logger(newVerifyEvent(cfg, token, verifiedToken, algName, d, err))
+	}
+
+	return verifiedToken, err
+}
+
+func verifyUnobservedToken(cfg *verifyConfig, alg Alg, key PublicKey, decrypt InjectFunc, token []byte, headerValidator HeaderValidator, validators []TokenValidator) (*VerifiedToken, error) {
 	if len(token) == 0 {
 		return nil, ErrMissing
 	}
 
-	header, payload, signature, err := decodeToken(alg, key, token, headerValidator)
+	if maxSize := cfg.maxTokenSize; maxSize > 0 && len(token) > maxSize {
+		return nil, ErrTokenTooLarge
+	}
+
+	if cfg.issuerResolver != nil {
+		var err error
+		if alg, key, err = resolveIssuer(cfg.issuerResolver, token, cfg.base64Decode()); err != nil {
+			return nil, err
+		}
+	}
+
+	var leaf *x509.Certificate
+	if cfg.x5cRoots != nil {
+		headerValidator = x5cHeaderValidator(cfg.x5cRoots, cfg.now(), key, headerValidator, &leaf)
+	}
+
+	header, payload, signature, err := decodeTokenBase64(alg, key, token, headerValidator, cfg.base64Decode())
 	if err != nil {
 		return nil, err
 	}
 
+	if err = validateCritHeader(header, cfg.criticalHeaders); err != nil {
+		return nil, err
+	}
+
+	if cfg.expectedTypHeader != "" {
+		if err = validateTypHeader(header, cfg.expectedTypHeader); err != nil {
+			return nil, err
+		}
+	}
+
 	if decrypt != nil {
 		payload, err = decrypt(payload)
 		if err != nil {
@@ -62,29 +273,77 @@ func verifyToken(alg Alg, key PublicKey, decrypt InjectFunc, token []byte, heade
 		}
 	}
 
-	var standardClaims Claims
-	standardClaimsErr := json.Unmarshal(payload, &standardClaims) // Use the standard one instead of the custom, no need to support "required" feature here.
-	// Do not exist on this error now, the payload may not be a JSON one.
-	if standardClaimsErr != nil {
-		var secondChange claimsSecondChance // try again with a different structure, which always converted to the standard jwt claims.
-		if err = json.Unmarshal(payload, &secondChange); err != nil {
-			err = errPayloadNotJSON // allow validators to catch this error.
+	payload, err = decompressPayload(header, payload, cfg.maxDecompressedSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.rejectDuplicateKeys {
+		if err = rejectDuplicateKeys(header, payload); err != nil {
+			return nil, err
 		}
+	}
 
-		standardClaims = secondChange.toClaims()
-	} else {
-		err = validateClaims(Clock(), standardClaims)
+	var (
+		standardClaims Claims
+		scanned        bool
+	)
+	if cfg.timeOnly && cfg.blocklist == nil && cfg.expectedAuthorizedParty == "" { // they need the "jti" and "aud" claims.
+		if standardClaims, scanned = scanTimeClaims(payload); scanned {
+			err = cfg.validateClaims(standardClaims)
+		}
 	}
 
-	for _, validator := range validators {
-		// A token validator can skip the builtin validation and return a nil error,
-		// in that case the previous error is skipped.
-		if err = validator.ValidateToken(token, standardClaims, err); err != nil {
-			break
+	if !scanned {
+		standardClaimsErr := json.Unmarshal(payload, &standardClaims) // Use the standard one instead of the custom, no need to support "required" feature here.
+		// Do not exist on this error now, the payload may not be a JSON one.
+		if standardClaimsErr != nil {
+			var secondChange claimsSecondChance // try again with a different structure, which always converted to the standard jwt claims.
+			if err = json.Unmarshal(payload, &secondChange); err != nil {
+				err = errPayloadNotJSON // allow validators to catch this error.
+			} else if standardClaims, err = secondChange.toClaims(cfg.lenientTimestamps); err == nil {
+				// e.g. float timestamps, they should be validated too.
+				err = cfg.validateClaims(standardClaims)
+			}
+		} else {
+			err = cfg.validateClaims(standardClaims)
 		}
 	}
 
-	if err != nil {
+	if cfg.allErrors {
+		err = cfg.validateAll(token, payload, standardClaims, err, validators)
+	} else {
+		// WithReturnOnExpired relaxes the "exp" check only,
+		// the rest of the checks and the validators run as if the token was not expired.
+		var expiredErr error
+		if cfg.returnOnExpired && isExpiredOnly(err) {
+			expiredErr, err = err, nil
+		}
+
+		claimsChecked := err == nil
+		if claimsChecked {
+			err = cfg.validatePayloadClaims(payload, standardClaims)
+		}
+
+		for _, validator := range validators {
+			// A token validator can skip the builtin validation and return a nil error,
+			// in that case the previous error is skipped.
+			if err = cfg.validateToken(validator, token, standardClaims, err); err != nil {
+				break
+			}
+		}
+
+		// A validator which skips a previous error (e.g. Future) cannot skip these checks too.
+		if err == nil && !claimsChecked {
+			err = cfg.validatePayloadClaims(payload, standardClaims)
+		}
+
+		if err == nil {
+			err = expiredErr
+		}
+	}
+
+	if err != nil && !(cfg.returnOnExpired && isExpiredOnly(err)) {
 		// Exit on parsing standard claims error(when Plain is missing) or standard claims validation error or custom validators.
 		return nil, err
 	}
@@ -95,10 +354,49 @@ func verifyToken(alg Alg, key PublicKey, decrypt InjectFunc, token []byte, heade
 		Payload:        payload,
 		Signature:      signature,
 		StandardClaims: standardClaims,
+		certificate:    leaf,
+		clock:          cfg.clock,
 		// We could store the standard claims error when Plain token validator is applied
 		// but there is no a single case of its usability, so we don't, unless is requested.
 	}
-	return verifiedTok, nil
+	return verifiedTok, err
+}
+
+// validatePayloadClaims runs the configured checks of the payload's claims, e.g. `WithRequiredClaims` and `WithBlocklist`.
+// They run before the token validators when there is no previous error,
+// otherwise after them, so a validator cannot skip them by skipping the previous error.
+func (cfg *verifyConfig) validatePayloadClaims(payload []byte, claims Claims) error {
+	if len(cfg.requiredClaims) > 0 {
+		if err := validateRequiredClaims(payload, cfg.requiredClaims); err != nil {
+			return err
+		}
+	}
+
+	if cfg.expectedType != "" {
+		if err := validateType(payload, cfg.expectedType); err != nil {
+			return err
+		}
+	}
+
+	if cfg.expectedNonce != "" {
+		if err := validateNonce(payload, cfg.expectedNonce); err != nil {
+			return err
+		}
+	}
+
+	if cfg.expectedAuthorizedParty != "" {
+		if err := validateAuthorizedParty(payload, claims.Audience, cfg.expectedAuthorizedParty); err != nil {
+			return err
+		}
+	}
+
+	if cfg.blocklist != nil {
+		if err := validateBlocklist(cfg.blocklist, claims.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // VerifiedToken holds the information about a verified token.
@@ -109,6 +407,9 @@ type VerifiedToken struct {
 	Payload        []byte // The payload (decoded) part.
 	Signature      []byte // The signature (decoded) part.
 	StandardClaims Claims // Any standard claims extracted from the payload.
+
+	certificate *x509.Certificate // The validated "x5c" leaf certificate, see `WithX5C`.
+	clock       func() time.Time  // The clock of the verification, see `WithClock`.
 }
 
 // Claims decodes the token's payload to the "dest".
@@ -122,8 +423,267 @@ type VerifiedToken struct {
 // and validated at the `Verify` function itself,
 // therefore NO FURTHER STEP is required
 // to validate the "exp", "iat" and "nbf" claims.
+//
+// It returns a descriptive error if the payload is not a valid JSON.
 func (t *VerifiedToken) Claims(dest interface{}) error {
-	return Unmarshal(t.Payload, dest)
+	err := unmarshalClaims(t.Payload, dest)
+	if err != nil {
+		// e.g. a float "exp" can not be decoded to the int64 field of an embedded Claims,
+		// try again with the integer time claims the token was validated with.
+		if payload, ok := integerTimeClaims(t.Payload, t.StandardClaims); ok {
+			return unmarshalClaims(payload, dest)
+		}
+	}
+
+	return err
+}
+
+// integerTimeClaims replaces the non-integer "nbf", "iat" and "exp" claims (e.g. floats)
+// of the "payload" with the integer values of the given "claims".
+// It reports false if there is nothing to replace.
+func integerTimeClaims(payload []byte, claims Claims) ([]byte, bool) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, false
+	}
+
+	replaced := false
+	replace := func(name string, value int64) {
+		if raw, ok := fields[name]; ok && string(raw) != "null" {
+			if _, err := strconv.ParseInt(string(raw), 10, 64); err != nil {
+				fields[name] = json.RawMessage(strconv.FormatInt(value, 10))
+				replaced = true
+			}
+		}
+	}
+
+	replace("nbf", claims.NotBefore)
+	replace("iat", claims.IssuedAt)
+	replace("exp", claims.Expiry)
+
+	if !replaced {
+		return nil, false
+	}
+
+	payload, err := json.Marshal(fields)
+	if err != nil {
+		return nil, false
+	}
+
+	return payload, true
+}
+
+// Kid returns the "kid" (key ID) header field of the token, if any.
+// See `WithKID` too.
+func (t *VerifiedToken) Kid() string {
+	if !bytes.Contains(t.Header, kidHeaderKey) {
+		return ""
+	}
+
+	var h struct {
+		Kid string `json:"kid"`
+	}
+	if err := Unmarshal(t.Header, &h); err != nil {
+		return ""
+	}
+
+	return h.Kid
+}
+
+// Algorithm returns the "alg" header field of the token,
+// the name of the algorithm which its signature was verified with, e.g. "RS256".
+// It is useful to log or monitor the algorithms of the incoming tokens.
+func (t *VerifiedToken) Algorithm() string {
+	var h struct {
+		Alg string `json:"alg"`
+	}
+	if err := Unmarshal(t.Header, &h); err != nil {
+		return ""
+	}
+
+	return h.Alg
+}
+
+// Expires returns the expiration time of the token, based on its "exp" claim.
+// It reports false if the token has no "exp" claim.
+func (t *VerifiedToken) Expires() (time.Time, bool) {
+	if t.StandardClaims.Expiry <= 0 {
+		return time.Time{}, false
+	}
+
+	return t.StandardClaims.ExpiresAt(), true
+}
+
+// TimeLeft returns the remaining lifetime of the token, based on its "exp" claim
+// and the clock of the verification (see `WithClock`), defaults to the `Clock` package-level variable.
+// It is useful to schedule a refresh before the token expires.
+// It returns zero if the token has no "exp" claim, see `Expires` to check that.
+func (t *VerifiedToken) TimeLeft() time.Duration {
+	expiresAt, ok := t.Expires()
+	if !ok {
+		return 0
+	}
+
+	now := Clock
+	if t.clock != nil {
+		now = t.clock
+	}
+
+	return expiresAt.Sub(now())
+}
+
+var kidHeaderKey = []byte(`"kid"`)
+
+// Headers decodes and returns the header fields of the token,
+// e.g. the custom ones set through the `WithHeader` sign option.
+// Numbers are decoded as json.Number values.
+func (t *VerifiedToken) Headers() (Map, error) {
+	var header Map
+	if err := Unmarshal(t.Header, &header); err != nil {
+		return nil, err
+	}
+
+	return header, nil
+}
+
+func unmarshalClaims(payload []byte, dest interface{}) error {
+	if err := Unmarshal(payload, dest); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return fmt.Errorf("%w: %v", errPayloadNotJSON, err)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// VerifyOption is a TokenValidator which modifies the verification process itself
+// instead of validating the claims, e.g. `WithLeeway`.
+// It can be passed at the last variadic input argument of the `Verify` function,
+// mixed with any other TokenValidator.
+type VerifyOption func(*verifyConfig)
+
+var _ TokenValidator = VerifyOption(nil)
+
+// ValidateToken completes the TokenValidator interface.
+// It respects the previous error as the option
+// is applied before the claims validation.
+func (opt VerifyOption) ValidateToken(_ []byte, _ Claims, err error) error {
+	return err
+}
+
+// verifyConfig holds the configuration of a single verification,
+// it is built through the passed VerifyOptions.
+type verifyConfig struct {
+	// leeway allows a tolerance on the "exp", "nbf" and "iat" checks.
+	leeway time.Duration
+	// clock overrides the `Clock` package-level variable, see `WithClock`.
+	clock func() time.Time
+	// skipIssuedAt disables the "iat" check, see `WithoutIssuedAtCheck`.
+	skipIssuedAt bool
+	// returnOnExpired returns the verified token along with an ErrExpired error, see `WithReturnOnExpired`.
+	returnOnExpired bool
+	// requiredClaims is a list of claim names that the payload must contain.
+	requiredClaims []string
+	// maxDecompressedSize limits the size of a compressed payload, see `WithMaxDecompressedSize`.
+	maxDecompressedSize int64
+	// timeOnly scans the payload for the time claims only, see `WithTimeOnlyValidation`.
+	timeOnly bool
+	// expectedType is the expected value of the "typ" claim.
+	expectedType string
+	// expectedNonce is the expected value of the "nonce" claim, see `WithExpectedNonce`.
+	expectedNonce string
+	// expectedAuthorizedParty is the client ID of the "azp" claim, see `WithExpectedAuthorizedParty`.
+	expectedAuthorizedParty string
+	// blocklist rejects tokens of revoked "jti" claims.
+	blocklist BlocklistStore
+	// allErrors collects all the validation failures, see `WithAllValidationErrors`.
+	allErrors bool
+	// lenientTimestamps accepts numeric string time claims, see `WithLenientTimestamps`.
+	lenientTimestamps bool
+	// expectedTypHeader is the expected value of the "typ" header field.
+	expectedTypHeader string
+	// criticalHeaders are the "crit" header parameters understood by the application.
+	criticalHeaders []string
+	// maxTokenSize limits the size of the compact form token, see `WithMaxTokenSize`.
+	maxTokenSize int
+	// concurrency is the number of goroutines of `VerifyBatch`, see `WithConcurrency`.
+	concurrency int
+	// rejectDuplicateKeys rejects repeated JSON keys, see `WithRejectDuplicateKeys`.
+	rejectDuplicateKeys bool
+	// issuerResolver selects the key and algorithm by the "iss" claim, see `WithIssuerResolver`.
+	issuerResolver IssuerResolver
+	// observer is notified on each verification, see `WithObserver`.
+	observer Observer
+	// logger is called on each verification, see `WithLogger`.
+	logger func(event VerifyEvent)
+	// paddedBase64 accepts padded token parts, see `WithPaddedBase64`.
+	paddedBase64 bool
+	// x5cRoots validates the "x5c" certificate chain, see `WithX5C`.
+	x5cRoots *x509.CertPool
+}
+
+// base64Decode returns the decoder of the token parts.
+func (c *verifyConfig) base64Decode() func([]byte) ([]byte, error) {
+	if c.paddedBase64 {
+		return base64DecodePadded
+	}
+
+	return Base64Decode
+}
+
+// validateClaims validates the "nbf", "iat" and "exp" claims.
+func (c *verifyConfig) validateClaims(claims Claims) error {
+	if c.skipIssuedAt {
+		claims.IssuedAt = 0
+	}
+
+	if c.allErrors {
+		return validateAllClaims(c.now(), claims, c.leeway)
+	}
+
+	return validateClaimsWithLeeway(c.now(), claims, c.leeway)
+}
+
+// now returns the current time of the configured clock, see `WithClock`.
+func (c *verifyConfig) now() time.Time {
+	if c.clock != nil {
+		return c.clock()
+	}
+
+	return Clock()
+}
+
+// defaultVerifyConfig is the read-only configuration used when no VerifyOption is passed.
+var defaultVerifyConfig = &verifyConfig{maxTokenSize: DefaultMaxTokenSize}
+
+func newVerifyConfig(validators []TokenValidator) *verifyConfig {
+	cfg := defaultVerifyConfig
+
+	for _, validator := range validators {
+		var opt VerifyOption
+		switch v := validator.(type) {
+		case VerifyOption:
+			opt = v
+		case ObserverOption:
+			opt = func(c *verifyConfig) { c.observer = v.observer }
+		}
+
+		if opt == nil {
+			continue
+		}
+
+		if cfg == defaultVerifyConfig {
+			c := *defaultVerifyConfig
+			cfg = &c
+		}
+
+		opt(cfg)
+	}
+
+	return cfg
 }
 
 var errPayloadNotJSON = errors.New("jwt: payload is not a type of JSON") // malformed JSON or it's not a JSON at all.
@@ -170,3 +730,13 @@ type (
 func (fn TokenValidatorFunc) ValidateToken(token []byte, standardClaims Claims, err error) error {
 	return fn(token, standardClaims, err)
 }
+
+// validateToken calls the "validator" with the current time
+// of the configured clock installed to the claims, see `Leeway` and `Future`.
+func (c *verifyConfig) validateToken(validator TokenValidator, token []byte, claims Claims, err error) error {
+	if c.clock != nil {
+		claims.now = c.clock()
+	}
+
+	return validator.ValidateToken(token, claims, err)
+}
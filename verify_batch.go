@@ -0,0 +1,91 @@
+package jwt
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// VerifyResult is the result of a single token verification of `VerifyBatch`.
+// Its Token is nil when Err is not nil, except for an ErrExpired one
+// when the `WithReturnOnExpired` option is used: the Token may be non-nil along with that error.
+type VerifyResult struct {
+	Token *VerifiedToken
+	Err   error
+}
+
+// WithConcurrency is a VerifyOption which sets the number of goroutines
+// `VerifyBatch` uses to verify its tokens. A zero or negative "n"
+// means one per available CPU (runtime.GOMAXPROCS).
+// It has no effect on the other verify functions.
+// Defaults to 1, the tokens are verified sequentially.
+func WithConcurrency(n int) VerifyOption {
+	return func(c *verifyConfig) {
+		if n <= 0 {
+			n = runtime.GOMAXPROCS(0)
+		}
+
+		c.concurrency = n
+	}
+}
+
+// VerifyBatch verifies each one of the "tokens" with the same algorithm, key and validators
+// and returns their results in the same order, e.g. for an offline audit of stored tokens.
+// A failed token does not stop the verification of the rest.
+//
+// The options are parsed once for the whole batch,
+// pass the `WithConcurrency` option to verify the tokens in parallel.
+//
+// Example Code:
+//
+//	results := jwt.VerifyBatch(jwt.HS256, sharedKey, tokens, jwt.WithConcurrency(0))
+//	for i, result := range results {
+//	  if result.Err != nil { [handle the error of tokens[i]...] }
+//	}
+func VerifyBatch(alg Alg, key PublicKey, tokens [][]byte, validators ...TokenValidator) []VerifyResult {
+	results := make([]VerifyResult, len(tokens))
+	if len(tokens) == 0 {
+		return results
+	}
+
+	cfg := newVerifyConfig(validators) // parsed once for all the tokens.
+	verify := func(i int) {
+		verifiedToken, err := verifyTokenWithConfig(cfg, alg, key, nil, tokens[i], nil, validators)
+		results[i] = VerifyResult{Token: verifiedToken, Err: err}
+	}
+
+	workers := cfg.concurrency
+	if workers > len(tokens) {
+		workers = len(tokens)
+	}
+
+	if workers <= 1 {
+		for i := range tokens {
+			verify(i)
+		}
+
+		return results
+	}
+
+	var (
+		next int64 = -1
+		wg   sync.WaitGroup
+	)
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= len(tokens) {
+					return
+				}
+
+				verify(i)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,116 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func testBatchTokens(t testing.TB, n int) [][]byte {
+	t.Helper()
+
+	tokens := make([][]byte, n)
+	for i := range tokens {
+		expiry := time.Now().Add(time.Minute)
+		if i%3 == 1 {
+			expiry = time.Now().Add(-time.Minute)
+		}
+
+		token, err := Sign(testAlg, testSecret, Map{"index": i, "exp": expiry.Unix()})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if i%3 == 2 {
+			if token[len(token)-5] == 'A' { // invalid signature.
+				token[len(token)-5] = 'B'
+			} else {
+				token[len(token)-5] = 'A'
+			}
+		}
+
+		tokens[i] = token
+	}
+
+	return tokens
+}
+
+func TestVerifyBatch(t *testing.T) {
+	tokens := testBatchTokens(t, 30)
+	tokens = append(tokens, nil, []byte("malformed"))
+
+	for _, concurrency := range []int{1, 4, 0} {
+		results := VerifyBatch(testAlg, testSecret, tokens, WithConcurrency(concurrency))
+		if expected, got := len(tokens), len(results); expected != got {
+			t.Fatalf("expected: %d results but got: %d", expected, got)
+		}
+
+		for i, result := range results[:30] {
+			switch i % 3 {
+			case 0:
+				if result.Err != nil {
+					t.Fatalf("[%d] %v", i, result.Err)
+				}
+
+				var claims struct {
+					Index int `json:"index"`
+				}
+				if err := result.Token.Claims(&claims); err != nil {
+					t.Fatal(err)
+				}
+
+				if expected, got := i, claims.Index; expected != got {
+					t.Fatalf("expected result of token: %d but got: %d", expected, got)
+				}
+			case 1:
+				if !errors.Is(result.Err, ErrExpired) || result.Token != nil {
+					t.Fatalf("[%d] expected error: ErrExpired but got: %v", i, result.Err)
+				}
+			case 2:
+				if !errors.Is(result.Err, ErrInvalidSignature) {
+					t.Fatalf("[%d] expected error: ErrInvalidSignature but got: %v", i, result.Err)
+				}
+			}
+		}
+
+		if err := results[30].Err; err != ErrMissing {
+			t.Fatalf("expected error: ErrMissing but got: %v", err)
+		}
+
+		if err := results[31].Err; !errors.Is(err, ErrMalformed) {
+			t.Fatalf("expected error: ErrMalformed but got: %v", err)
+		}
+	}
+
+	// Validators and options apply to every token.
+	results := VerifyBatch(testAlg, testSecret, tokens[:2], WithLeeway(2*time.Minute), WithConcurrency(2))
+	for i, result := range results {
+		if result.Err != nil {
+			t.Fatalf("[%d] %v", i, result.Err)
+		}
+	}
+
+	if results = VerifyBatch(testAlg, testSecret, nil); len(results) != 0 {
+		t.Fatalf("expected no results but got: %d", len(results))
+	}
+}
+
+func BenchmarkVerifyBatch(b *testing.B) {
+	tokens := testBatchTokens(b, 1000)
+
+	b.Run("Loop", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			for _, token := range tokens {
+				_, _ = Verify(testAlg, testSecret, token, WithLeeway(time.Second))
+			}
+		}
+	})
+
+	b.Run("Batch", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			VerifyBatch(testAlg, testSecret, tokens, WithLeeway(time.Second), WithConcurrency(0))
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package jwt
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// VerifyEvent describes the outcome of a token verification, see `WithLogger`.
+// It never holds the key or the token itself, the token is identified by its fingerprint.
+type VerifyEvent struct {
+	// Verified reports whether the token passed the verification and the validators.
+	Verified bool
+	// Algorithm is the algorithm of the token, see `Observer.OnVerify` for its value on failures.
+	Algorithm string
+	// Issuer is the "iss" claim of the token, if any.
+	// It is NOT trusted on failure, as it is read from an unverified payload.
+	Issuer string
+	// Fingerprint is the hex-encoded SHA-256 hash of the token's signature part,
+	// it identifies the token without exposing it.
+	// It is empty for a token rejected by its size, see `WithMaxTokenSize`.
+	Fingerprint string
+	// Duration is the time spent on the verification.
+	Duration time.Duration
+	// Err is the verification error, nil on success.
+	Err error
+}
+
+// WithLogger is a VerifyOption which calls the "logger" on each verification,
+// successful or not, e.g. to keep an audit trail of the failed verifications.
+// The event holds the outcome, the algorithm, the issuer and the error,
+// the token is fingerprinted instead of logged whole.
+//
+// Example Code:
+//
+//	logVerify := jwt.WithLogger(func(event jwt.VerifyEvent) {
+//	  if !event.Verified {
+//	    slog.Warn("jwt: verification failed", "fingerprint", event.Fingerprint, "iss", event.Issuer, "err", event.Err)
+//	  }
+//	})
+//
+//	verifiedToken, err := jwt.Verify(jwt.HS256, sharedKey, token, logVerify)
+func WithLogger(logger func(event VerifyEvent)) VerifyOption {
+	return func(c *verifyConfig) {
+		c.logger = logger
+	}
+}
+
+func newVerifyEvent(cfg *verifyConfig, token []byte, verifiedToken *VerifiedToken, alg string, d time.Duration, err error) VerifyEvent {
+	event := VerifyEvent{
+		Verified:  err == nil,
+		Algorithm: alg,
+		Duration:  d,
+		Err:       err,
+	}
+
+	// An oversized token is never hashed nor decoded, it would undo the size limit.
+	if errors.Is(err, ErrTokenTooLarge) || (cfg.maxTokenSize > 0 && len(token) > cfg.maxTokenSize) {
+		return event
+	}
+
+	event.Fingerprint = fingerprint(token)
+
+	if verifiedToken != nil {
+		event.Issuer = verifiedToken.StandardClaims.Issuer
+	} else if _, payload, _, ok := SplitToken(token); ok {
+		if payload, decodeErr := cfg.base64Decode()(payload); decodeErr == nil {
+			var claims struct {
+				Issuer string `json:"iss"`
+			}
+			if json.Unmarshal(payload, &claims) == nil {
+				event.Issuer = claims.Issuer
+			}
+		}
+	}
+
+	return event
+}
+
+// fingerprint returns the hex-encoded SHA-256 hash of the token's signature part,
+// or of the whole token if it's malformed. An empty token has an empty fingerprint.
+func fingerprint(token []byte) string {
+	if len(token) == 0 {
+		return ""
+	}
+
+	if _, _, signature, ok := SplitToken(token); ok {
+		token = signature
+	}
+
+	sum := sha256.Sum256(token)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,105 @@
+package jwt
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithLogger(t *testing.T) {
+	var events []VerifyEvent
+	logVerify := WithLogger(func(event VerifyEvent) {
+		events = append(events, event)
+	})
+
+	token, err := Sign(testAlg, testSecret, Map{"iss": "auth.example.com", "username": "kataras"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, signature, _ := SplitToken(token)
+	sum := sha256.Sum256(signature)
+	expectedFingerprint := hex.EncodeToString(sum[:])
+
+	if _, err = Verify(testAlg, testSecret, token, logVerify); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, []byte("othersecret"), token, logVerify); !errors.Is(err, ErrTokenSignature) {
+		t.Fatalf("expected error: ErrTokenSignature but got: %v", err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, []byte("a.b"), logVerify); !errors.Is(err, ErrTokenForm) {
+		t.Fatalf("expected error: ErrTokenForm but got: %v", err)
+	}
+
+	if expected, got := 3, len(events); expected != got {
+		t.Fatalf("expected: %d events but got: %d", expected, got)
+	}
+
+	if got := events[0]; !got.Verified || got.Err != nil || got.Algorithm != testAlg.Name() ||
+		got.Issuer != "auth.example.com" || got.Fingerprint != expectedFingerprint || got.Duration < 0 {
+		t.Fatalf("unexpected success event: %#+v", got)
+	}
+
+	if got := events[1]; got.Verified || !errors.Is(got.Err, ErrTokenSignature) || got.Algorithm != testAlg.Name() ||
+		got.Issuer != "auth.example.com" || got.Fingerprint != expectedFingerprint {
+		t.Fatalf("unexpected failure event: %#+v", got)
+	}
+
+	if got := events[2]; got.Verified || !errors.Is(got.Err, ErrTokenForm) || got.Issuer != "" || got.Fingerprint == "" {
+		t.Fatalf("unexpected malformed token event: %#+v", got)
+	}
+
+	// Redacted: the event holds neither the key nor the token or its signature.
+	for _, event := range events {
+		dump := fmt.Sprintf("%#+v %v", event, event.Err)
+		for _, secret := range []string{string(testSecret), string(token), string(signature)} {
+			if strings.Contains(dump, secret) {
+				t.Fatalf("event leaks %q: %s", secret, dump)
+			}
+		}
+	}
+}
+
+func TestWithLoggerOversizedToken(t *testing.T) {
+	var events []VerifyEvent
+	logVerify := WithLogger(func(event VerifyEvent) {
+		events = append(events, event)
+	})
+
+	token, err := Sign(testAlg, testSecret, Map{"iss": "auth.example.com", "data": strings.Repeat("a", DefaultMaxTokenSize)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, logVerify); !errors.Is(err, ErrTokenTooLarge) {
+		t.Fatalf("expected error: ErrTokenTooLarge but got: %v", err)
+	}
+
+	// Neither hashed nor decoded.
+	if got := events[0]; !errors.Is(got.Err, ErrTokenTooLarge) || got.Issuer != "" || got.Fingerprint != "" {
+		t.Fatalf("unexpected oversized token event: %#+v", got)
+	}
+
+	// The issuer of a failed padded token is read through its decoder.
+	header := base64.URLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	payload := base64.URLEncoding.EncodeToString([]byte(`{"iss":"auth.example.com"}`))
+	if !strings.HasSuffix(payload, "=") {
+		t.Fatalf("expected a padded payload: %s", payload)
+	}
+
+	paddedToken := joinParts([]byte(header), []byte(payload), []byte("c2lnbmF0dXJl"))
+	if _, err = Verify(testAlg, testSecret, paddedToken, logVerify, WithPaddedBase64()); !errors.Is(err, ErrTokenSignature) {
+		t.Fatalf("expected error: ErrTokenSignature but got: %v", err)
+	}
+
+	if got := events[1]; got.Issuer != "auth.example.com" || got.Fingerprint == "" {
+		t.Fatalf("unexpected padded token event: %#+v", got)
+	}
+}
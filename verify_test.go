@@ -2,9 +2,15 @@ package jwt
 
 import (
 	"bytes"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 // The actual implementation tests live inside token_test.go and each algorithm's test file.
@@ -125,3 +131,653 @@ func TestVerifyWithSingleAudienceString_CustomClaimsAndStandard(t *testing.T) {
 		t.Fatalf("expected:\n%#+v\n\nbut got:\n%#+v", standardClaims, gotStandard)
 	}
 }
+
+func TestVerifyWithHeaderAlg(t *testing.T) {
+	claims := Map{"username": "kataras"}
+
+	for _, alg := range []Alg{HS256, HS512} {
+		token, err := Sign(alg, testSecret, claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		verifiedToken, err := VerifyWithHeaderAlg(testSecret, token, HS256.Name(), HS512.Name())
+		if err != nil {
+			t.Fatalf("[%s] %v", alg.Name(), err)
+		}
+
+		var got Map
+		if err = verifiedToken.Claims(&got); err != nil {
+			t.Fatal(err)
+		}
+
+		if !compareMap(claims, got) {
+			t.Fatalf("[%s] expected claims: %#+v but got: %#+v", alg.Name(), claims, got)
+		}
+	}
+
+	token, err := Sign(HS384, testSecret, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = VerifyWithHeaderAlg(testSecret, token, HS256.Name(), HS512.Name()); !errors.Is(err, ErrDisallowedAlg) {
+		t.Fatalf("expected error: ErrDisallowedAlg but got: %v", err)
+	}
+
+	// No allowed algorithms, nothing should pass.
+	if _, err = VerifyWithHeaderAlg(testSecret, token); !errors.Is(err, ErrDisallowedAlg) {
+		t.Fatalf("expected error: ErrDisallowedAlg but got: %v", err)
+	}
+
+	// Unsigned tokens should not pass unless NONE is explicitly allowed.
+	unsignedToken, err := Sign(NONE, nil, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = VerifyWithHeaderAlg(testSecret, unsignedToken, HS256.Name()); !errors.Is(err, ErrDisallowedAlg) {
+		t.Fatalf("expected error: ErrDisallowedAlg but got: %v", err)
+	}
+
+	// Allowed but modified signature.
+	token, err = Sign(HS256, testSecret, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = VerifyWithHeaderAlg([]byte("othersecret"), token, HS256.Name()); !errors.Is(err, ErrTokenSignature) {
+		t.Fatalf("expected error: ErrTokenSignature but got: %v", err)
+	}
+}
+
+func TestVerifyWithClock(t *testing.T) {
+	past := time.Date(2020, 10, 26, 1, 1, 1, 0, time.UTC)
+
+	token, err := Sign(testAlg, testSecret, Claims{
+		IssuedAt: past.Unix(),
+		Expiry:   past.Add(time.Minute).Unix(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token); !errors.Is(err, ErrExpired) {
+		t.Fatalf("expected error: ErrExpired but got: %v", err)
+	}
+
+	prevClock := Clock
+	t.Cleanup(func() {
+		Clock = prevClock
+	})
+
+	Clock = func() time.Time {
+		return past.Add(30 * time.Second)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token); err != nil {
+		t.Fatalf("expected token to be valid at the frozen time but got: %v", err)
+	}
+}
+
+func TestVerifyWithClockOption(t *testing.T) {
+	past := time.Date(2020, 10, 26, 1, 1, 1, 0, time.UTC)
+
+	token, err := Sign(testAlg, testSecret, Claims{
+		IssuedAt: past.Unix(),
+		Expiry:   past.Add(time.Minute).Unix(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		now    time.Time
+		leeway time.Duration
+		err    error
+	}{
+		{past.Add(30 * time.Second), 0, nil},
+		{past.Add(2 * time.Minute), 0, ErrExpired},
+		{past.Add(2 * time.Minute), 2 * time.Minute, nil},
+		{past.Add(-time.Minute), 0, ErrIssuedInTheFuture},
+		{past.Add(-time.Minute), 2 * time.Minute, nil},
+	}
+
+	// Many concurrent verifications of different clocks and leeways,
+	// run with -race to detect shared state.
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		for _, tt := range tests {
+			wg.Add(1)
+			go func(now time.Time, leeway time.Duration, expected error) {
+				defer wg.Done()
+
+				clock := func() time.Time { return now }
+				_, err := Verify(testAlg, testSecret, token, WithClock(clock), WithLeeway(leeway))
+				if !errors.Is(err, expected) {
+					t.Errorf("now: %s, leeway: %s: expected error: %v but got: %v", now, leeway, expected, err)
+				}
+			}(tt.now, tt.leeway, tt.err)
+		}
+	}
+	wg.Wait()
+
+	// The package-level Clock is not affected.
+	if _, err = Verify(testAlg, testSecret, token); !errors.Is(err, ErrExpired) {
+		t.Fatalf("expected error: ErrExpired but got: %v", err)
+	}
+}
+
+func TestVerifiedTokenClaims(t *testing.T) {
+	type userClaims struct {
+		Claims
+		Username string `json:"username"`
+	}
+
+	expected := userClaims{
+		Claims:   Claims{Subject: "user-1", Issuer: "my-app", Audience: Audience{"api"}},
+		Username: "kataras",
+	}
+
+	token, err := Sign(testAlg, testSecret, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got userClaims
+	if err = verifiedToken.Claims(&got); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected:\n%#+v\n\nbut got:\n%#+v", expected, got)
+	}
+
+	if !reflect.DeepEqual(expected.Claims, verifiedToken.StandardClaims) {
+		t.Fatalf("expected standard claims:\n%#+v\n\nbut got:\n%#+v", expected.Claims, verifiedToken.StandardClaims)
+	}
+
+	// Test non-JSON payload.
+	token, err = Sign(testAlg, testSecret, []byte("raw payload"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err = Verify(testAlg, testSecret, token, Plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = verifiedToken.Claims(&got); !errors.Is(err, errPayloadNotJSON) {
+		t.Fatalf("expected error: errPayloadNotJSON but got: %v", err)
+	}
+}
+
+func TestVerifiedTokenClaimsFloatTimestamps(t *testing.T) {
+	type userClaims struct {
+		Claims
+		Username string `json:"username"`
+	}
+
+	exp := Clock().Add(time.Minute).Unix()
+	token, err := Sign(testAlg, testSecret, []byte(fmt.Sprintf(`{"username":"kataras","sub":"user-1","exp":%d.5}`, exp)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var claims userClaims
+	if err = verifiedToken.Claims(&claims); err != nil {
+		t.Fatal(err)
+	}
+
+	if claims.Username != "kataras" || claims.Subject != "user-1" || claims.Expiry != exp {
+		t.Fatalf("unexpected claims: %#+v", claims)
+	}
+
+	// Other decode errors are still reported.
+	var invalid struct {
+		Username int `json:"username"`
+	}
+	if err = verifiedToken.Claims(&invalid); err == nil {
+		t.Fatalf("expected a decode error")
+	}
+}
+
+func TestVerifiedTokenFields(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"}, Claims{Subject: "user-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(verifiedToken.Token, token) {
+		t.Fatalf("expected original token: %q but got: %q", token, verifiedToken.Token)
+	}
+
+	var header map[string]interface{}
+	if err = json.Unmarshal(verifiedToken.Header, &header); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := testAlg.Name(), header["alg"]; expected != got {
+		t.Fatalf("expected header alg: %q but got: %v", expected, got)
+	}
+
+	if expected, got := "JWT", header["typ"]; expected != got {
+		t.Fatalf("expected header typ: %q but got: %v", expected, got)
+	}
+
+	if expected, got := "user-1", verifiedToken.StandardClaims.Subject; expected != got {
+		t.Fatalf("expected standard claims subject: %q but got: %q", expected, got)
+	}
+
+	if len(verifiedToken.Signature) == 0 {
+		t.Fatalf("expected signature part to be filled")
+	}
+
+	payload, err := VerifyRaw(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(payload, verifiedToken.Payload) {
+		t.Fatalf("expected raw payload: %q but got: %q", verifiedToken.Payload, payload)
+	}
+
+	if _, err = VerifyRaw(testAlg, []byte("othersecret"), token); err != ErrTokenSignature {
+		t.Fatalf("expected error: ErrTokenSignature but got: %v", err)
+	}
+}
+
+func TestVerifiedTokenAlgorithm(t *testing.T) {
+	privateKey, err := GenerateEdDSAKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		alg           Alg
+		signKey       PrivateKey
+		verKey        PublicKey
+		headerOptions []SignOption
+	}{
+		{HS256, testSecret, testSecret, nil},
+		{HS512, testSecret, testSecret, nil},
+		{EdDSA, privateKey, privateKey.Public(), nil},
+		{HS384, testSecret, testSecret, []SignOption{WithKID("api-1"), WithHeader("cty", "JWT")}},
+	}
+
+	for _, tt := range tests {
+		token, err := Sign(tt.alg, tt.signKey, Map{"username": "kataras"}, tt.headerOptions...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		verifiedToken, err := Verify(tt.alg, tt.verKey, token)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if expected, got := tt.alg.Name(), verifiedToken.Algorithm(); expected != got {
+			t.Fatalf("expected algorithm: %q but got: %q", expected, got)
+		}
+	}
+
+	if got := (&VerifiedToken{}).Algorithm(); got != "" {
+		t.Fatalf("expected an empty algorithm but got: %q", got)
+	}
+}
+
+func TestVerifiedTokenExpires(t *testing.T) {
+	prevClock := Clock
+	defer func() {
+		Clock = prevClock
+	}()
+
+	now := time.Date(2020, 10, 26, 1, 1, 1, 0, time.UTC)
+	Clock = func() time.Time {
+		return now
+	}
+
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"}, MaxAge(15*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expiresAt, ok := verifiedToken.Expires()
+	if !ok {
+		t.Fatalf("expected exp claim to be present")
+	}
+
+	if expected := now.Add(15 * time.Minute); !expiresAt.Equal(expected) {
+		t.Fatalf("expected expiration time: %s but got: %s", expected, expiresAt)
+	}
+
+	if expected, got := 15*time.Minute, verifiedToken.TimeLeft(); expected != got {
+		t.Fatalf("expected time left: %s but got: %s", expected, got)
+	}
+
+	now = now.Add(10 * time.Minute)
+	if expected, got := 5*time.Minute, verifiedToken.TimeLeft(); expected != got {
+		t.Fatalf("expected time left: %s but got: %s", expected, got)
+	}
+
+	// Absent exp.
+	token, err = Sign(testAlg, testSecret, Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err = Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expiresAt, ok = verifiedToken.Expires(); ok || !expiresAt.IsZero() {
+		t.Fatalf("expected no expiration time but got: %s", expiresAt)
+	}
+
+	if got := verifiedToken.TimeLeft(); got != 0 {
+		t.Fatalf("expected zero time left but got: %s", got)
+	}
+}
+
+func TestVerifiedTokenTimeLeftWithClock(t *testing.T) {
+	now := time.Date(2020, 10, 26, 1, 1, 1, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"}, WithSignClock(clock), MaxAge(15*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token, WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := 15*time.Minute, verifiedToken.TimeLeft(); expected != got {
+		t.Fatalf("expected time left: %s but got: %s", expected, got)
+	}
+
+	now = now.Add(10 * time.Minute)
+	if expected, got := 5*time.Minute, verifiedToken.TimeLeft(); expected != got {
+		t.Fatalf("expected time left: %s but got: %s", expected, got)
+	}
+}
+
+func TestVerifyMalformedAndInvalidSignature(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts := bytes.Split(token, sep)
+
+	var malformed = [][]byte{
+		joinParts(parts[0], parts[1]),                        // two segments.
+		joinParts(parts[0], parts[1], parts[2], parts[2]),    // four segments.
+		joinParts([]byte("$$"), parts[1], parts[2]),          // bad header base64.
+		joinParts(parts[0], parts[1], []byte("$$")),          // bad signature base64.
+		joinParts(parts[0], parts[1], append(parts[2], '=')), // padded signature.
+	}
+
+	for i, tt := range malformed {
+		_, err = Verify(testAlg, testSecret, tt)
+		if !errors.Is(err, ErrMalformed) {
+			t.Fatalf("[%d] expected error: ErrMalformed but got: %v", i, err)
+		}
+
+		if errors.Is(err, ErrInvalidSignature) {
+			t.Fatalf("[%d] expected malformed error to not be ErrInvalidSignature", i)
+		}
+	}
+
+	// Valid structure, wrong key.
+	_, err = Verify(testAlg, []byte("other"), token)
+	if !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected error: ErrInvalidSignature but got: %v", err)
+	}
+
+	if errors.Is(err, ErrMalformed) {
+		t.Fatalf("expected signature error to not be ErrMalformed")
+	}
+}
+
+func TestWithMaxTokenSize(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"data": strings.Repeat("a", 1<<10)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, WithMaxTokenSize(len(token))); err != nil {
+		t.Fatalf("expected a token of the limit size to be accepted but got: %v", err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, WithMaxTokenSize(len(token)-1)); err != ErrTokenTooLarge {
+		t.Fatalf("expected error: ErrTokenTooLarge but got: %v", err)
+	}
+
+	// Default limit.
+	largeToken, err := Sign(testAlg, testSecret, Map{"data": strings.Repeat("a", DefaultMaxTokenSize)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, largeToken); err != ErrTokenTooLarge {
+		t.Fatalf("expected error: ErrTokenTooLarge but got: %v", err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, largeToken, WithMaxTokenSize(0)); err != nil {
+		t.Fatalf("expected the limit to be disabled but got: %v", err)
+	}
+
+	// Rejected before decoding.
+	garbage := bytes.Repeat([]byte("$"), DefaultMaxTokenSize+1)
+	if _, err = Verify(testAlg, testSecret, garbage); err != ErrTokenTooLarge {
+		t.Fatalf("expected error: ErrTokenTooLarge but got: %v", err)
+	}
+}
+
+func TestWithPaddedBase64(t *testing.T) {
+	signPadded := func(header, payload string) []byte {
+		input := joinParts([]byte(header), []byte(payload))
+		signature, err := testAlg.Sign(testSecret, input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return joinParts(input, []byte(base64.URLEncoding.EncodeToString(signature)))
+	}
+
+	header := base64.URLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	payload := base64.URLEncoding.EncodeToString([]byte(`{"username":"kataras"}`))
+	if !strings.HasSuffix(payload, "=") {
+		t.Fatalf("expected a padded payload: %s", payload)
+	}
+
+	paddedToken := signPadded(header, payload)
+
+	// Rejected by default.
+	if _, err := Verify(testAlg, testSecret, paddedToken); !errors.Is(err, ErrMalformed) {
+		t.Fatalf("expected error: ErrMalformed but got: %v", err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, paddedToken, WithPaddedBase64())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := `{"username":"kataras"}`, string(verifiedToken.Payload); expected != got {
+		t.Fatalf("expected payload: %s but got: %s", expected, got)
+	}
+
+	// Unpadded parts are still accepted.
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, WithPaddedBase64()); err != nil {
+		t.Fatal(err)
+	}
+
+	// The signature covers the parts as they are given.
+	rawPayload := strings.TrimRight(payload, "=")
+	_, _, paddedSignature, _ := SplitToken(paddedToken)
+	if _, err = Verify(testAlg, testSecret, joinParts([]byte(header), []byte(rawPayload), paddedSignature), WithPaddedBase64()); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected error: ErrInvalidSignature but got: %v", err)
+	}
+
+	// Invalid base64 is still rejected.
+	var invalid = []string{
+		rawPayload + "=",   // incorrect padding.
+		rawPayload + "===", // too much padding.
+		"$$" + payload,     // invalid character.
+		"eyJ1c2Vy+/==",     // standard alphabet.
+	}
+
+	for i, tt := range invalid {
+		if _, err = Verify(testAlg, testSecret, signPadded(header, tt), WithPaddedBase64()); !errors.Is(err, ErrMalformed) {
+			t.Fatalf("[%d] expected error: ErrMalformed but got: %v", i, err)
+		}
+	}
+}
+
+func TestVerifySurroundingWhitespace(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var accepted = []string{
+		" " + string(token),
+		string(token) + "\n",
+		"\t" + string(token) + "\r\n",
+	}
+
+	for i, tt := range accepted {
+		verifiedToken, err := Verify(testAlg, testSecret, []byte(tt))
+		if err != nil {
+			t.Fatalf("[%d] %v", i, err)
+		}
+
+		if !bytes.Equal(verifiedToken.Token, token) {
+			t.Fatalf("[%d] expected the trimmed token but got: %q", i, verifiedToken.Token)
+		}
+
+		if _, err = DecodeVerify(testAlg, testSecret, []byte(tt)); err != nil {
+			t.Fatalf("[%d] %v", i, err)
+		}
+	}
+
+	header, payload, signature, _ := SplitToken(token)
+	var rejected = [][]byte{
+		joinParts(header, append([]byte(" "), payload...), signature),                                     // internal space.
+		joinParts(header, payload, append(signature[:10:10], append([]byte("\n"), signature[10:]...)...)), // internal new line.
+	}
+
+	for i, tt := range rejected {
+		if _, err = Verify(testAlg, testSecret, tt); !errors.Is(err, ErrMalformed) {
+			t.Fatalf("[%d] expected error: ErrMalformed but got: %v", i, err)
+		}
+	}
+
+	if _, err = Verify(testAlg, testSecret, []byte(" \n")); !errors.Is(err, ErrMissing) {
+		t.Fatalf("expected error: ErrMissing but got: %v", err)
+	}
+}
+
+func TestVerifyAny(t *testing.T) {
+	previousKey, currentKey := []byte("previous-secret"), []byte("current-secret")
+
+	token, err := Sign(testAlg, previousKey, Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := VerifyAny(testAlg, []PublicKey{currentKey, previousKey}, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := `{"username":"kataras"}`, string(verifiedToken.Payload); expected != got {
+		t.Fatalf("expected payload: %s but got: %s", expected, got)
+	}
+
+	_, err = VerifyAny(testAlg, []PublicKey{currentKey, "invalid-key-type"}, token)
+	if !errors.Is(err, ErrTokenSignature) || !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected aggregated ErrTokenSignature and ErrInvalidKey errors but got: %v", err)
+	}
+
+	for _, expected := range []string{"key[0] ([]uint8)", "key[1] (string)"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected error message to report: %q but got: %v", expected, err)
+		}
+	}
+
+	if strings.Contains(err.Error(), string(currentKey)) {
+		t.Fatalf("expected error to not contain the key material: %v", err)
+	}
+
+	// Non-signature errors are returned immediately.
+	expiredToken, err := Sign(testAlg, previousKey, Map{"exp": Clock().Add(-time.Minute).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = VerifyAny(testAlg, []PublicKey{previousKey, currentKey}, expiredToken); !errors.Is(err, ErrExpired) {
+		t.Fatalf("expected error: ErrExpired but got: %v", err)
+	}
+
+	if _, err = VerifyAny(testAlg, nil, token); err != ErrInvalidKey {
+		t.Fatalf("expected error: ErrInvalidKey but got: %v", err)
+	}
+}
+
+func TestMustVerify(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var verifiedToken *VerifiedToken
+	catchPanic(t, false, func() {
+		verifiedToken = MustVerify(testAlg, testSecret, token)
+	})
+
+	if verifiedToken == nil || !bytes.Equal(verifiedToken.Token, token) {
+		t.Fatalf("expected the verified token")
+	}
+
+	catchPanic(t, true, func() {
+		MustVerify(testAlg, []byte("othersecret"), token)
+	})
+
+	defer func() {
+		if err, ok := recover().(error); !ok || !errors.Is(err, ErrTokenSignature) {
+			t.Fatalf("expected a panic of ErrTokenSignature but got: %v", err)
+		}
+	}()
+
+	MustVerify(testAlg, []byte("othersecret"), token)
+}
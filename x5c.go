@@ -0,0 +1,134 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidX5C indicates that the "x5c" header field of a token is missing or invalid,
+// its certificate chain does not validate against the CA pool
+// or its leaf certificate's key does not match the verification key.
+// See `WithX5C`.
+var ErrInvalidX5C = errors.New("jwt: invalid x5c certificate chain")
+
+// WithX5C is a VerifyOption which verifies the token's signature
+// with the public key of the leaf certificate of its "x5c" header field (RFC 7515, Section 4.1.6).
+// The chain, the leaf certificate first and its intermediates next,
+// must validate against the "roots" CA pool at the verification time (see `WithClock`).
+// A nil "roots" pool trusts no certificate, the system pool is never used.
+//
+// When a key is given to the verify function (or by a header validator)
+// the leaf certificate's public key must match that key.
+// Any failure results to an error which wraps `ErrInvalidX5C`.
+// The validated leaf certificate is available through the `VerifiedToken.Certificate` method.
+//
+// Example Code:
+//
+//	roots := x509.NewCertPool()
+//	roots.AddCert(caCert)
+//
+//	verifiedToken, err := jwt.Verify(jwt.ES256, nil, token, jwt.WithX5C(roots))
+//	[handle error...]
+//	subject := verifiedToken.Certificate().Subject
+func WithX5C(roots *x509.CertPool) VerifyOption {
+	return func(c *verifyConfig) {
+		if roots == nil {
+			roots = x509.NewCertPool()
+		}
+
+		c.x5cRoots = roots
+	}
+}
+
+// Certificate returns the leaf certificate of the token's "x5c" header field,
+// validated through the `WithX5C` verify option. It returns nil otherwise.
+func (t *VerifiedToken) Certificate() *x509.Certificate {
+	return t.certificate
+}
+
+// x5cHeaderValidator wraps the "next" header validator (`CompareHeader` if nil)
+// and it selects the public key of the validated leaf certificate, which is stored to the "leaf".
+func x5cHeaderValidator(roots *x509.CertPool, now time.Time, key PublicKey, next HeaderValidator, leaf **x509.Certificate) HeaderValidator {
+	if next == nil {
+		next = CompareHeader
+	}
+
+	return func(alg string, headerDecoded []byte) (Alg, PublicKey, InjectFunc, error) {
+		dynamicAlg, pubKey, decrypt, err := next(alg, headerDecoded)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		if pubKey != nil {
+			key = pubKey
+		}
+
+		cert, err := verifyX5C(headerDecoded, roots, now)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		if key != nil {
+			k, ok := key.(interface{ Equal(crypto.PublicKey) bool })
+			if !ok || !k.Equal(cert.PublicKey) {
+				return nil, nil, nil, fmt.Errorf("%w: leaf certificate key does not match the verification key", ErrInvalidX5C)
+			}
+		}
+
+		*leaf = cert
+		return dynamicAlg, cert.PublicKey, decrypt, nil
+	}
+}
+
+// verifyX5C parses the "x5c" header field and validates its chain against the "roots".
+// It returns the leaf certificate.
+func verifyX5C(headerDecoded []byte, roots *x509.CertPool, now time.Time) (*x509.Certificate, error) {
+	var header struct {
+		X5C []string `json:"x5c"`
+	}
+	if err := Unmarshal(headerDecoded, &header); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidX5C, err)
+	}
+
+	if len(header.X5C) == 0 {
+		return nil, fmt.Errorf("%w: missing x5c header", ErrInvalidX5C)
+	}
+
+	certs := make([]*x509.Certificate, 0, len(header.X5C))
+	for i, s := range header.X5C {
+		// Standard base64 encoded DER, not base64url.
+		der, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: certificate [%d]: %v", ErrInvalidX5C, i, err)
+		}
+
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("%w: certificate [%d]: %v", ErrInvalidX5C, i, err)
+		}
+
+		certs = append(certs, cert)
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	leaf := certs[0]
+	_, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidX5C, err)
+	}
+
+	return leaf, nil
+}
@@ -0,0 +1,166 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+)
+
+type testCertificate struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// testIssueCertificate issues a certificate of the "name" signed by the "parent",
+// a nil parent issues a self-signed one.
+func testIssueCertificate(t *testing.T, name string, isCA bool, notAfter time.Time, parent *testCertificate) *testCertificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	if isCA {
+		template.KeyUsage |= x509.KeyUsageCertSign
+	}
+
+	parentCert, parentKey := template, key
+	if parent != nil {
+		parentCert, parentKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testCertificate{cert: cert, key: key}
+}
+
+func testX5C(certs ...*testCertificate) []string {
+	x5c := make([]string, 0, len(certs))
+	for _, c := range certs {
+		x5c = append(x5c, base64.StdEncoding.EncodeToString(c.cert.Raw))
+	}
+
+	return x5c
+}
+
+func TestWithX5C(t *testing.T) {
+	notAfter := time.Now().Add(time.Hour)
+	root := testIssueCertificate(t, "Test Root CA", true, notAfter, nil)
+	intermediate := testIssueCertificate(t, "Test Intermediate CA", true, notAfter, root)
+	leaf := testIssueCertificate(t, "api.example.com", false, notAfter, intermediate)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root.cert)
+
+	token, err := Sign(ES256, leaf.key, Map{"username": "kataras"}, MaxAge(time.Minute), WithHeader("x5c", testX5C(leaf, intermediate)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(ES256, nil, token, WithX5C(roots))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cert := verifiedToken.Certificate(); cert == nil || cert.Subject.CommonName != "api.example.com" {
+		t.Fatalf("expected the leaf certificate but got: %v", cert)
+	}
+
+	// A matching verification key.
+	if _, err = Verify(ES256, &leaf.key.PublicKey, token, WithX5C(roots)); err != nil {
+		t.Fatal(err)
+	}
+
+	// No certificate without the option.
+	if verifiedToken, err = Verify(ES256, &leaf.key.PublicKey, token); err != nil {
+		t.Fatal(err)
+	} else if verifiedToken.Certificate() != nil {
+		t.Fatalf("expected no certificate")
+	}
+
+	otherRoot := testIssueCertificate(t, "Other Root CA", true, notAfter, nil)
+	otherRoots := x509.NewCertPool()
+	otherRoots.AddCert(otherRoot.cert)
+
+	expiredLeaf := testIssueCertificate(t, "expired.example.com", false, time.Now().Add(-time.Minute), intermediate)
+	otherKey := testIssueCertificate(t, "other.example.com", false, notAfter, intermediate)
+
+	signX5C := func(key *ecdsa.PrivateKey, x5c interface{}) []byte {
+		t.Helper()
+
+		token, err := Sign(ES256, key, Map{"username": "kataras"}, MaxAge(time.Minute), WithHeader("x5c", x5c))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return token
+	}
+
+	var tests = []struct {
+		name     string
+		key      PublicKey
+		token    []byte
+		roots    *x509.CertPool
+		expected error
+	}{
+		{"untrusted root", nil, token, otherRoots, ErrInvalidX5C},
+		{"nil roots", nil, token, nil, ErrInvalidX5C},
+		{"missing intermediate", nil, signX5C(leaf.key, testX5C(leaf)), roots, ErrInvalidX5C},
+		{"expired leaf", nil, signX5C(expiredLeaf.key, testX5C(expiredLeaf, intermediate)), roots, ErrInvalidX5C},
+		{"missing header", nil, MustSign(ES256, leaf.key, Map{"username": "kataras"}), roots, ErrInvalidX5C},
+		{"invalid base64", nil, signX5C(leaf.key, []string{"$$"}), roots, ErrInvalidX5C},
+		{"invalid certificate", nil, signX5C(leaf.key, []string{"AAAA"}), roots, ErrInvalidX5C},
+		{"key mismatch", &otherKey.key.PublicKey, token, roots, ErrInvalidX5C},
+		{"signed by another key", nil, signX5C(otherKey.key, testX5C(leaf, intermediate)), roots, ErrTokenSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifiedToken, err := Verify(ES256, tt.key, tt.token, WithX5C(tt.roots))
+			if !errors.Is(err, tt.expected) {
+				t.Fatalf("expected error: %v but got: %v", tt.expected, err)
+			}
+
+			if verifiedToken != nil {
+				t.Fatalf("expected no verified token")
+			}
+		})
+	}
+
+	// The chain is validated at the verification time.
+	if _, err = Verify(ES256, nil, token, WithX5C(roots), WithClock(func() time.Time {
+		return notAfter.Add(time.Hour)
+	})); !errors.Is(err, ErrInvalidX5C) {
+		t.Fatalf("expected error: ErrInvalidX5C but got: %v", err)
+	}
+}